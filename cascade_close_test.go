@@ -23,6 +23,7 @@ import (
  * - Receive with timeout, get no message
  * - Close second consumer, try to receive again, get error, check 1 + 3 still ok
  * - Ctx.close, try to receive on the other two, get error
+ * - Check that the context and the objects created from it report that they are closed
  */
 func TestCascadeClose(t *testing.T) {
 
@@ -86,8 +87,8 @@ func TestCascadeClose(t *testing.T) {
 
 	testMsg, err = consumer2.ReceiveNoWait()
 	assert.NotNil(t, err)
-	assert.Equal(t, "2019", err.GetErrorCode())
-	assert.Equal(t, "MQRC_HOBJ_ERROR", err.GetReason())
+	assert.Equal(t, mqjms.ContextImpl_ILLEGAL_STATE_CODE, err.GetErrorCode())
+	assert.Equal(t, mqjms.ConsumerImpl_CONSUMER_CLOSED_REASON, err.GetReason())
 	assert.Nil(t, testMsg)
 
 	testMsg, err = consumer3.ReceiveNoWait()
@@ -102,20 +103,97 @@ func TestCascadeClose(t *testing.T) {
 
 	testMsg, err = consumer1.ReceiveNoWait()
 	assert.NotNil(t, err)
-	assert.Equal(t, "2018", err.GetErrorCode())
-	assert.Equal(t, "MQRC_HCONN_ERROR", err.GetReason())
+	assert.Equal(t, mqjms.ContextImpl_ILLEGAL_STATE_CODE, err.GetErrorCode())
+	assert.Equal(t, mqjms.ContextImpl_CONTEXT_CLOSED_REASON, err.GetReason())
 	assert.Nil(t, testMsg)
 
 	testMsg, err = consumer2.ReceiveNoWait()
 	assert.NotNil(t, err)
-	assert.Equal(t, "2018", err.GetErrorCode())
-	assert.Equal(t, "MQRC_HCONN_ERROR", err.GetReason())
+	assert.Equal(t, mqjms.ContextImpl_ILLEGAL_STATE_CODE, err.GetErrorCode())
+	assert.Equal(t, mqjms.ContextImpl_CONTEXT_CLOSED_REASON, err.GetReason())
 	assert.Nil(t, testMsg)
 
 	testMsg, err = consumer3.ReceiveNoWait()
 	assert.NotNil(t, err)
-	assert.Equal(t, "2018", err.GetErrorCode())
-	assert.Equal(t, "MQRC_HCONN_ERROR", err.GetReason())
+	assert.Equal(t, mqjms.ContextImpl_ILLEGAL_STATE_CODE, err.GetErrorCode())
+	assert.Equal(t, mqjms.ContextImpl_CONTEXT_CLOSED_REASON, err.GetReason())
+	assert.Nil(t, testMsg)
+
+	// Close a closed context to check it doesn't complain.
+	context.Close()
+
+	// Everything else that needs the context reports that it is closed.
+	errSend := context.CreateProducer().SendString(queue, "NotSent")
+	assert.NotNil(t, errSend)
+	assert.Equal(t, mqjms.ContextImpl_CONTEXT_CLOSED_REASON, errSend.GetReason())
+
+	errCommit := context.Commit()
+	assert.NotNil(t, errCommit)
+	assert.Equal(t, mqjms.ContextImpl_CONTEXT_CLOSED_REASON, errCommit.GetReason())
+
+	consumer4, conErr := context.CreateConsumer(queue)
+	assert.NotNil(t, conErr)
+	assert.Equal(t, mqjms.ContextImpl_CONTEXT_CLOSED_REASON, conErr.GetReason())
+	assert.Nil(t, consumer4)
+
+	browser, browseErr := context.CreateBrowser(queue)
+	assert.NotNil(t, browseErr)
+	assert.Equal(t, mqjms.ContextImpl_CONTEXT_CLOSED_REASON, browseErr.GetReason())
+	assert.Nil(t, browser)
+
+}
+
+/*
+ * Test that closing a context also closes the browsers that were created from it,
+ * and that a closed browser reports that it is closed.
+ */
+func TestCascadeCloseBrowser(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	// We are testing Close behaviour here, but auto-cleanup just in case.
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+
+	browser1, browseErr := context.CreateBrowser(queue)
+	assert.Nil(t, browseErr)
+	if browser1 != nil {
+		defer browser1.Close()
+	}
+
+	browser2, browseErr := context.CreateBrowser(queue)
+	assert.Nil(t, browseErr)
+	if browser2 != nil {
+		defer browser2.Close()
+	}
+
+	// Close the first browser directly.
+	browser1.Close()
+
+	enum1, browseErr := browser1.GetEnumeration()
+	assert.Nil(t, browseErr)
+	testMsg, err := enum1.GetNext()
+	assert.NotNil(t, err)
+	assert.Equal(t, mqjms.ConsumerImpl_CONSUMER_CLOSED_REASON, err.GetReason())
+	assert.Nil(t, testMsg)
+
+	// The second browser is closed by closing the context.
+	context.Close()
+
+	enum2, browseErr := browser2.GetEnumeration()
+	assert.Nil(t, browseErr)
+	testMsg, err = enum2.GetNext()
+	assert.NotNil(t, err)
+	assert.Equal(t, mqjms.ContextImpl_CONTEXT_CLOSED_REASON, err.GetReason())
 	assert.Nil(t, testMsg)
 
 }
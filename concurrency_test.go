@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+/*
+ * Test that a receive with a long wait on one goroutine does not stop another
+ * goroutine from sending messages using the same context.
+ */
+func TestSendDuringBlockingReceive(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// The receiving goroutine waits on one queue while messages are sent to another.
+	waitQueue := context.CreateQueue("DEV.QUEUE.2")
+	sendQueue := context.CreateQueue("DEV.QUEUE.1")
+
+	waitConsumer, conErr := context.CreateConsumer(waitQueue)
+	assert.Nil(t, conErr)
+	if waitConsumer != nil {
+		defer waitConsumer.Close()
+	}
+
+	sendConsumer, conErr2 := context.CreateConsumer(sendQueue)
+	assert.Nil(t, conErr2)
+	if sendConsumer != nil {
+		defer sendConsumer.Close()
+	}
+
+	// Check no message on the queues to start with
+	testMsg, err := waitConsumer.ReceiveNoWait()
+	assert.Nil(t, err)
+	assert.Nil(t, testMsg)
+
+	testMsg, err = sendConsumer.ReceiveNoWait()
+	assert.Nil(t, err)
+	assert.Nil(t, testMsg)
+
+	// Start a receive that waits for much longer than the rest of the test.
+	waitTime := int32(10000)
+	receiveDone := make(chan jms20subset.Message)
+	go func() {
+		msg, _ := waitConsumer.Receive(waitTime)
+		receiveDone <- msg
+	}()
+
+	// Sending (and receiving without a wait) should only be held up for at most
+	// one wait slice, rather than the whole wait of the other goroutine.
+	msgBody := "ConcurrentSendMsg"
+
+	startTime := currentTimeMillis()
+	errSend := context.CreateProducer().SendString(sendQueue, msgBody)
+	rcvBody, errRcv := sendConsumer.ReceiveStringBodyNoWait()
+	endTime := currentTimeMillis()
+
+	assert.Nil(t, errSend)
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvBody)
+	assert.Equal(t, msgBody, *rcvBody)
+	assert.True(t, (endTime-startTime) < 2*int64(mqjms.ConsumerImpl_DEFAULT_RECEIVE_WAIT_SLICE_MILLIS)+300)
+
+	// Wake up the waiting goroutine with a message.
+	errSend = context.CreateProducer().SendString(waitQueue, msgBody)
+	assert.Nil(t, errSend)
+
+	rcvMsg := <-receiveDone
+	assert.NotNil(t, rcvMsg)
+
+}
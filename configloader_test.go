@@ -0,0 +1,175 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+/*
+ * Test loading every setting of a ConnectionFactory from a YAML file.
+ */
+func TestLoadConnectionFactoryYAML(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "configloader")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	passwordFile := filepath.Join(dir, "password")
+	ioutil.WriteFile(passwordFile, []byte("secret-password\n"), 0600)
+
+	configFile := filepath.Join(dir, "cf.yaml")
+	ioutil.WriteFile(configFile, []byte(`
+queueManager: QM1
+hostname: myhost
+port: 1414
+channel: DEV.APP.SVRCONN
+transportType: client
+applName: configapp
+username: app
+passwordFile: `+passwordFile+`
+tlsCipherSpec: ANY_TLS12_OR_HIGHER
+tlsClientAuth: required
+keyRepository: /tmp/key
+certificateLabel: mylabel
+keyRepoPassword: keystore-password
+tlsPeerName: CN=QM1,O=My Company
+tlsKeyResetCount: 1048576
+tlsFipsRequired: true
+tlsCertificateValPolicy: rfc5280
+receiveBufferSize: 65536
+sendCheckCount: 10
+receiveWaitSlice: 500
+producerHandleCacheSize: 4
+messageHandlePoolSize: 8
+traceMQI: true
+`), 0600)
+
+	cf, err := mqjms.LoadConnectionFactory(configFile)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "QM1", cf.QMName)
+	assert.Equal(t, "myhost", cf.Hostname)
+	assert.Equal(t, 1414, cf.PortNumber)
+	assert.Equal(t, "DEV.APP.SVRCONN", cf.ChannelName)
+	assert.Equal(t, mqjms.TransportType_CLIENT, cf.TransportType)
+	assert.Equal(t, "configapp", cf.ApplName)
+	assert.Equal(t, "app", cf.UserName)
+	assert.Equal(t, "secret-password", cf.Password)
+	assert.Equal(t, "ANY_TLS12_OR_HIGHER", cf.TLSCipherSpec)
+	assert.Equal(t, mqjms.TLSClientAuth_REQUIRED, cf.TLSClientAuth)
+	assert.Equal(t, "/tmp/key", cf.KeyRepository)
+	assert.Equal(t, "mylabel", cf.CertificateLabel)
+	assert.Equal(t, "keystore-password", cf.KeyRepoPassword)
+	assert.Equal(t, "CN=QM1,O=My Company", cf.TLSPeerName)
+	assert.Equal(t, 1048576, cf.TLSKeyResetCount)
+	assert.True(t, cf.TLSFipsRequired)
+	assert.Equal(t, mqjms.TLSCertificateValPolicy_RFC5280, cf.TLSCertificateValPolicy)
+	assert.Equal(t, 65536, cf.ReceiveBufferSize)
+	assert.Equal(t, 10, cf.SendCheckCount)
+	assert.Equal(t, 500, cf.ReceiveWaitSlice)
+	assert.Equal(t, 4, cf.ProducerHandleCacheSize)
+	assert.Equal(t, 8, cf.MessageHandlePoolSize)
+	assert.True(t, cf.TraceMQI)
+
+}
+
+/*
+ * Test that environment variables take precedence over a JSON file, and that
+ * the file can be named by MQ_CONFIG_FILE.
+ */
+func TestLoadConnectionFactoryEnvironment(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "configloader")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "cf.json")
+	ioutil.WriteFile(configFile, []byte(`{
+  "queueManager": "QM1",
+  "hostname": "myhost",
+  "port": 1414,
+  "password": "file-password",
+  "receiveBufferSize": 1000000
+}`), 0600)
+
+	passwordFile := filepath.Join(dir, "password")
+	ioutil.WriteFile(passwordFile, []byte("env-password"), 0600)
+
+	env := map[string]string{
+		mqjms.ConfigFileEnv: configFile,
+		"MQ_HOSTNAME":       "otherhost",
+		"MQ_PORT":           "1415",
+		"MQ_PASSWORD_FILE":  passwordFile,
+		"MQ_CHANNEL":        "",
+	}
+	for name, value := range env {
+		os.Setenv(name, value)
+		defer os.Unsetenv(name)
+	}
+
+	cf, err := mqjms.LoadConnectionFactory("")
+	assert.Nil(t, err)
+
+	assert.Equal(t, "QM1", cf.QMName)
+	assert.Equal(t, "otherhost", cf.Hostname)
+	assert.Equal(t, 1415, cf.PortNumber)
+	assert.Equal(t, "env-password", cf.Password)
+	assert.Equal(t, 1000000, cf.ReceiveBufferSize)
+	assert.Equal(t, "", cf.ChannelName)
+
+}
+
+/*
+ * Test the errors that are returned for configuration that is not valid.
+ */
+func TestLoadConnectionFactoryErrors(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "configloader")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// A setting that isn't recognised, for example because of a typing mistake.
+	configFile := filepath.Join(dir, "cf.yaml")
+	ioutil.WriteFile(configFile, []byte("queueManager: QM1\nhostnme: myhost\n"), 0600)
+
+	_, err = mqjms.LoadConnectionFactory(configFile)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "hostnme")
+
+	// A value that is not valid for the setting.
+	ioutil.WriteFile(configFile, []byte("port: fourteen\n"), 0600)
+
+	_, err = mqjms.LoadConnectionFactory(configFile)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "port")
+
+	// A file whose format can't be determined from its name.
+	otherFile := filepath.Join(dir, "cf.txt")
+	ioutil.WriteFile(otherFile, []byte("port: 1414\n"), 0600)
+
+	_, err = mqjms.LoadConnectionFactory(otherFile)
+	assert.NotNil(t, err)
+
+	// An environment variable that is not valid.
+	os.Setenv("MQ_TRANSPORT_TYPE", "carrier-pigeon")
+	defer os.Unsetenv("MQ_TRANSPORT_TYPE")
+
+	_, err = mqjms.LoadConnectionFactory("")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "MQ_TRANSPORT_TYPE")
+
+}
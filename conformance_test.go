@@ -0,0 +1,36 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset/conformance"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+/*
+ * Test that the IBM MQ provider passes the provider conformance suite, which
+ * checks the behaviour that other providers such as memjms must match.
+ */
+func TestConformance(t *testing.T) {
+
+	conformance.Run(t, func() (jms20subset.ConnectionFactory, error) {
+
+		// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+		cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+		if cfErr != nil {
+			return nil, cfErr
+		}
+		return cf, nil
+	})
+
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+// countingCredentialsProvider returns the same credentials on every call, and
+// counts the number of calls.
+type countingCredentialsProvider struct {
+	lock  sync.Mutex
+	creds mqjms.Credentials
+	err   error
+	calls int
+}
+
+func (provider *countingCredentialsProvider) Credentials() (mqjms.Credentials, error) {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	provider.calls++
+	return provider.creds, provider.err
+}
+
+/*
+ * Test that the CredentialsProvider is called for every JMSContext that is
+ * created, including child contexts.
+ */
+func TestCredentialsProvider(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Supply the credentials from the provider rather than the ConnectionFactory.
+	provider := &countingCredentialsProvider{
+		creds: mqjms.Credentials{UserName: cf.UserName, Password: cf.Password},
+	}
+	cf.UserName = ""
+	cf.Password = ""
+	cf.CredentialsProvider = provider
+
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+	assert.Equal(t, 1, provider.calls)
+
+	childContext, childErr := context.CreateContext(jms20subset.JMSContextAUTOACKNOWLEDGE)
+	assert.Nil(t, childErr)
+	if childContext != nil {
+		defer childContext.Close()
+	}
+	assert.Equal(t, 2, provider.calls)
+
+	// Wrong credentials are rejected by the queue manager, which shows that
+	// the credentials from the provider are used.
+	provider.creds.Password = "wrong-password"
+	badContext, badErr := cf.CreateContext()
+	assert.Nil(t, badContext)
+	assert.NotNil(t, badErr)
+	assert.Equal(t, "MQRC_NOT_AUTHORIZED", badErr.GetReason())
+	assert.Equal(t, 3, provider.calls)
+
+	// A failure of the provider is returned without connecting.
+	providerErr := errors.New("token server unavailable")
+	provider.err = providerErr
+	badContext, badErr = cf.CreateContext()
+	assert.Nil(t, badContext)
+	assert.NotNil(t, badErr)
+	assert.Equal(t, mqjms.ConnectionFactoryImpl_CREDENTIALS_REASON, badErr.GetReason())
+	assert.True(t, errors.Is(badErr, providerErr))
+
+	var securityErr jms20subset.SecurityException
+	assert.True(t, errors.As(badErr, &securityErr))
+
+}
+
+/*
+ * Test that FileCredentialsProvider picks up a password that has changed.
+ */
+func TestFileCredentialsProvider(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "credentials")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	passwordFile := filepath.Join(dir, "password")
+	tokenFile := filepath.Join(dir, "token")
+	ioutil.WriteFile(passwordFile, []byte("password1\n"), 0600)
+
+	provider := mqjms.FileCredentialsProvider{UserName: "app", PasswordFile: passwordFile}
+
+	creds, err := provider.Credentials()
+	assert.Nil(t, err)
+	assert.Equal(t, mqjms.Credentials{UserName: "app", Password: "password1"}, creds)
+
+	// The rotated password is read on the next call.
+	ioutil.WriteFile(passwordFile, []byte("password2\n"), 0600)
+
+	creds, err = provider.Credentials()
+	assert.Nil(t, err)
+	assert.Equal(t, "password2", creds.Password)
+
+	// A missing file is reported as an error.
+	provider = mqjms.FileCredentialsProvider{TokenFile: tokenFile}
+	_, err = provider.Credentials()
+	assert.NotNil(t, err)
+
+	ioutil.WriteFile(tokenFile, []byte("eyJhbGciOi.token.sig"), 0600)
+	creds, err = provider.Credentials()
+	assert.Nil(t, err)
+	assert.Equal(t, "eyJhbGciOi.token.sig", creds.Token)
+
+}
+
+/*
+ * Test connecting with a JSON Web Token, which is read from the file named by
+ * the MQ_TEST_TOKEN_FILE environment variable.
+ */
+func TestTokenAuthentication(t *testing.T) {
+
+	tokenFile := os.Getenv("MQ_TEST_TOKEN_FILE")
+	if tokenFile == "" {
+		// Requires a token server and a queue manager that is configured to trust it.
+		fmt.Println("Skipping TestTokenAuthentication as MQ_TEST_TOKEN_FILE is not set.")
+		return
+	}
+
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	cf.UserName = ""
+	cf.Password = ""
+	cf.CredentialsProvider = mqjms.FileCredentialsProvider{TokenFile: tokenFile}
+
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// A token that is not valid is rejected.
+	cf.CredentialsProvider = nil
+	cf.Token = "not-a-valid-token"
+
+	badContext, badErr := cf.CreateContext()
+	assert.Nil(t, badContext)
+	assert.NotNil(t, badErr)
+
+}
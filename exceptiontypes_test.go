@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+/*
+ * Test that failures are reported using the typed exceptions, which can be
+ * detected using errors.Is and errors.As rather than by comparing error codes.
+ */
+func TestExceptionTypes(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// A queue that does not exist is an invalid destination, and the MQ error
+	// can still be reached using errors.As.
+	badQueue := context.CreateQueue("DOES.NOT.EXIST.QUEUE")
+	errSend := context.CreateProducer().SendString(badQueue, "NotSent")
+	assert.NotNil(t, errSend)
+	assert.Equal(t, "2085", errSend.GetErrorCode())
+	assert.True(t, errors.Is(errSend, jms20subset.InvalidDestinationException{}))
+	assert.False(t, errors.Is(errSend, jms20subset.IllegalStateException{}))
+	assert.False(t, jms20subset.IsRetryable(errSend))
+
+	var destErr jms20subset.InvalidDestinationException
+	assert.True(t, errors.As(errSend, &destErr))
+	assert.Equal(t, "MQRC_UNKNOWN_OBJECT_NAME", destErr.GetReason())
+
+	var mqret *ibmmq.MQReturn
+	assert.True(t, errors.As(errSend, &mqret))
+	assert.Equal(t, int32(ibmmq.MQRC_UNKNOWN_OBJECT_NAME), mqret.MQRC)
+
+	// A selector that cannot be parsed.
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, conErr := context.CreateConsumerWithSelector(queue, "NotAValidSelector")
+	assert.NotNil(t, conErr)
+	assert.Nil(t, consumer)
+	assert.True(t, errors.Is(conErr, jms20subset.InvalidSelectorException{}))
+
+	// A property value that cannot be converted to the type that is asked for.
+	msg := context.CreateTextMessage()
+	propValue := "notANumber"
+	propErr := msg.SetStringProperty("myProperty", &propValue)
+	assert.Nil(t, propErr)
+
+	_, propErr = msg.GetIntProperty("myProperty")
+	assert.NotNil(t, propErr)
+	assert.Equal(t, mqjms.MessageImpl_PROPERTY_CONVERT_FAILED_REASON, propErr.GetReason())
+	assert.True(t, errors.Is(propErr, jms20subset.MessageFormatException{}))
+
+	// Using a message after it has been released.
+	msg.Release()
+	_, propErr = msg.GetStringProperty("myProperty")
+	assert.NotNil(t, propErr)
+	assert.True(t, errors.Is(propErr, jms20subset.IllegalStateException{}))
+
+	// Using the context after it has been closed.
+	context.Close()
+	errCommit := context.Commit()
+	assert.NotNil(t, errCommit)
+	assert.True(t, errors.Is(errCommit, jms20subset.IllegalStateException{}))
+	assert.False(t, jms20subset.IsRetryable(errCommit))
+
+}
+
+/*
+ * Test that bad credentials are reported as a SecurityException.
+ */
+func TestSecurityException(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Set a value we know will cause a failure
+	cf.UserName = "wrong_user"
+
+	context, err := cf.CreateContext()
+	assert.NotNil(t, err)
+	if context != nil {
+		defer context.Close()
+	}
+
+	assert.Equal(t, "2035", err.GetErrorCode())
+	assert.True(t, errors.Is(err, jms20subset.SecurityException{}))
+
+	var secErr jms20subset.SecurityException
+	assert.True(t, errors.As(err, &secErr))
+	assert.False(t, jms20subset.IsRetryable(secErr))
+
+}
+
+/*
+ * Test the classification of exceptions as retryable, which does not need a
+ * queue manager.
+ */
+func TestExceptionRetryable(t *testing.T) {
+
+	plainErr := jms20subset.CreateJMSException("MQRC_UNKNOWN_OBJECT_NAME", "2085", nil)
+	assert.False(t, jms20subset.IsRetryable(plainErr))
+
+	retryErr := jms20subset.CreateRetryableJMSException("MQRC_Q_MGR_NOT_AVAILABLE", "2059", nil)
+	assert.True(t, jms20subset.IsRetryable(retryErr))
+
+	fullErr := jms20subset.CreateResourceAllocationException("MQRC_Q_FULL", "2053", nil)
+	assert.True(t, jms20subset.IsRetryable(fullErr))
+	assert.True(t, errors.Is(fullErr, jms20subset.ResourceAllocationException{}))
+
+	backoutErr := jms20subset.CreateTransactionRolledBackException("MQRC_BACKED_OUT", "2003", nil)
+	assert.True(t, jms20subset.IsRetryable(backoutErr))
+
+	// Exceptions with the same code and reason match each other.
+	assert.True(t, errors.Is(plainErr, jms20subset.CreateJMSException("MQRC_UNKNOWN_OBJECT_NAME", "2085", nil)))
+	assert.False(t, errors.Is(plainErr, retryErr))
+
+	// The typed exceptions still match an exception with the same code and
+	// reason, as well as any exception of their own type.
+	assert.True(t, errors.Is(fullErr, jms20subset.CreateJMSException("MQRC_Q_FULL", "2053", nil)))
+	assert.False(t, errors.Is(fullErr, plainErr))
+
+	// An exception is found to be retryable when it is wrapped by another error.
+	assert.True(t, jms20subset.IsRetryable(fmt.Errorf("sending failed: %w", fullErr)))
+	assert.False(t, jms20subset.IsRetryable(errors.New("not a JMSException")))
+
+	// The linked error is returned by Unwrap.
+	linkedErr := errors.New("linked")
+	wrappingErr := jms20subset.CreateJMSException("reason", "code", linkedErr)
+	assert.True(t, errors.Is(wrappingErr, linkedErr))
+
+}
@@ -1,8 +1,14 @@
 module github.com/zemlya25/mq-golang-jms20
 
-go 1.13
+go 1.18
 
 require (
-	github.com/ibm-messaging/mq-golang/v5 v5.3.2
+	github.com/ibm-messaging/mq-golang/v5 v5.6.0
 	github.com/stretchr/testify v1.8.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 )
@@ -19,17 +19,17 @@ type ConnectionFactory interface {
 	// CreateContext creates a connection to the messaging provider using the
 	// configuration parameters that are encapsulated by this ConnectionFactory.
 	//
-	// Optional MQOptions can be provided to configure the connection prior to initialisation
-	// but are not typically required. Most calls to this function pass zero arguments.
+	// Optional provider-specific ConnectionOptions can be provided to configure the
+	// connection prior to initialisation but are not typically required. Most calls to this function pass zero arguments.
 	//
 	// Defaults to sessionMode of JMSContextAUTOACKNOWLEDGE
-	CreateContext(opts ...MQOptions) (JMSContext, JMSException)
+	CreateContext(opts ...ConnectionOption) (JMSContext, JMSException)
 
 	// CreateContextWithSessionMode creates a connection to the messaging provider using the
 	// configuration parameters that are encapsulated by this ConnectionFactory,
 	// and the specified session mode.
 	//
-	// Optional MQOptions can be provided to configure the connection prior to initialisation
-	// but are not typically required. Most calls to this function pass zero arguments.
-	CreateContextWithSessionMode(sessionMode int, opts ...MQOptions) (JMSContext, JMSException)
+	// Optional provider-specific ConnectionOptions can be provided to configure the
+	// connection prior to initialisation but are not typically required. Most calls to this function pass zero arguments.
+	CreateContextWithSessionMode(sessionMode int, opts ...ConnectionOption) (JMSContext, JMSException)
 }
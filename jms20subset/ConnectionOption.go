@@ -0,0 +1,63 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package jms20subset provides interfaces for messaging applications in the style of the Java Message Service (JMS) API.
+package jms20subset
+
+import "fmt"
+
+// ConnectionOption is a provider-specific option that configures a connection
+// before it is made, and is passed to ConnectionFactory.CreateContext.
+//
+// Each provider defines the options that it understands, for example the
+// MQOptions in the mqjms package, so that this package does not depend on the
+// libraries of any provider. A provider returns a JMSException from
+// CreateContext if it is given an option that it does not understand.
+type ConnectionOption interface {
+
+	// Apply applies the option to the provider's connection settings, which are
+	// given as the target. It returns an error if the option was given a value
+	// that is not valid, or if the target is not the connection settings of the
+	// provider that defined the option.
+	Apply(target interface{}) error
+}
+
+// MQOptions is the previous name of ConnectionOption, which is kept so that
+// existing code that refers to it continues to compile.
+//
+// Deprecated: Use ConnectionOption, and the options defined in mqjms.
+type MQOptions = ConnectionOption
+
+// WithMaxMsgLength sets the maximum length of message that can be sent or
+// received over the client channel, in bytes.
+//
+// The option can be applied to the connection settings of any provider that
+// has a SetMaxMsgLength method, as those of IBM MQ do.
+//
+// Deprecated: Use mqjms.WithMaxMsgLength. This function will be removed in the
+// next release.
+func WithMaxMsgLength(maxMsgLength int32) ConnectionOption {
+	return maxMsgLengthOption(maxMsgLength)
+}
+
+// maxMsgLengthOption is the option that is returned by WithMaxMsgLength.
+type maxMsgLengthOption int32
+
+// Apply sets the maximum message length of the target, returning an error if the
+// target does not support it.
+func (o maxMsgLengthOption) Apply(target interface{}) error {
+
+	setter, ok := target.(interface {
+		SetMaxMsgLength(maxMsgLength int32) error
+	})
+	if !ok {
+		return fmt.Errorf("WithMaxMsgLength cannot be applied to %T", target)
+	}
+
+	return setter.SetMaxMsgLength(int32(o))
+}
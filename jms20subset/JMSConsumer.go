@@ -10,6 +10,11 @@
 // Package jms20subset provides interfaces for messaging applications in the style of the Java Message Service (JMS) API.
 package jms20subset
 
+import (
+	"context"
+	"io"
+)
+
 // JMSConsumer provides the ability for an application to receive messages
 // from a queue or a topic.
 //
@@ -27,6 +32,36 @@ type JMSConsumer interface {
 	// available. A value of zero or less indicates to wait indefinitely.
 	Receive(waitMillis int32) (Message, JMSException)
 
+	// ReceiveInto behaves in the same way as Receive(waitMillis), but receives
+	// the message into the supplied buffer rather than allocating one, so that
+	// applications can reuse the same buffer for many messages.
+	//
+	// The body of a BytesMessage returned by this method may refer directly to
+	// the supplied buffer, so the buffer must not be reused until the application
+	// has finished with the message. If the message is larger than the buffer
+	// then an error is returned and the message is not received.
+	ReceiveInto(buffer []byte, waitMillis int32) (Message, JMSException)
+
+	// ReceiveContext returns a message if one is available, or otherwise
+	// waits until one becomes available or the supplied Go context is
+	// cancelled or reaches its deadline, in which case an error is returned
+	// that links to the context error.
+	ReceiveContext(ctx context.Context) (Message, JMSException)
+
+	// ReceiveChannel starts a background receive loop that delivers messages
+	// from this JMSConsumer on the returned message channel, so that they can
+	// be consumed using a select statement alongside other channels.
+	//
+	// bufferSize is the number of received messages that may be held in the
+	// channel waiting for the application. When the buffer is full no further
+	// messages are received until the application has taken one, so messages
+	// remain on the queue rather than building up in memory.
+	//
+	// The loop stops when the supplied Go context is cancelled or when a
+	// receive fails, in which case the error is delivered on the error channel.
+	// Both channels are closed when the loop stops.
+	ReceiveChannel(ctx context.Context, bufferSize int) (<-chan Message, <-chan JMSException)
+
 	// ReceiveStringBodyNoWait receives the next message for this JMSConsumer
 	// and returns its body as a string. If a message is not immediately
 	// available a nil is returned.
@@ -51,6 +86,20 @@ type JMSConsumer interface {
 	// indefinitely.
 	ReceiveBytesBody(waitMillis int32) (*[]byte, JMSException)
 
+	// ReceiveStream receives data that was sent using SendStream, waiting for
+	// up to the specified number of milliseconds for it to become available (a
+	// value of zero or less waits indefinitely), and returns a reader that
+	// receives the data in chunks as it is read. A nil reader is returned if
+	// nothing is available. A message that was sent in some other way is
+	// received as a stream containing its body.
+	//
+	// If the JMSContext is transacted then the data is received under the
+	// transaction. Otherwise it is only removed from the queue once all of the
+	// data has been read, when the reader returns io.EOF or is closed, and it is
+	// left on the queue if the reader is closed before then or if a failure
+	// occurs. The reader must always be closed.
+	ReceiveStream(waitMillis int32) (io.ReadCloser, JMSException)
+
 	// Closes the JMSConsumer in order to free up any resources that were
 	// allocated by the provider on behalf of this consumer.
 	Close()
@@ -21,6 +21,16 @@ const JMSContextSESSIONTRANSACTED int = 0
 // objects so that it can send and receive messages.
 type JMSContext interface {
 
+	// CreateContext creates a new JMSContext that shares the connection of
+	// this JMSContext to the messaging provider, with the specified session mode.
+	//
+	// The contexts that share a connection also share its transaction scope, so
+	// Commit and Rollback on a transacted JMSContext apply to the work done under
+	// the transaction of any of them. The connection is closed when the last of
+	// them is closed, so they can be closed in any order. Use
+	// ConnectionFactory.CreateContext for an independent transaction scope.
+	CreateContext(sessionMode int) (JMSContext, JMSException)
+
 	// CreateProducer creates a new producer object that can be used to configure
 	// and send messages.
 	//
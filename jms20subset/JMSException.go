@@ -10,11 +10,19 @@
 // Package jms20subset provides interfaces for messaging applications in the style of the Java Message Service (JMS) API.
 package jms20subset
 
+import "errors"
+
 // JMSException represents an interface for returning details of a
 // condition that has caused a function call to fail.
 //
 // It includes provider-specific Reason and ErrorCode attributes, and an
 // optional reference to an Error describing the low-level problem.
+//
+// Particular categories of failure are returned as one of the more specific
+// exception types defined in this package, such as InvalidDestinationException,
+// which can be detected using errors.As or errors.Is. The exceptions created by
+// this package return the linked error from Unwrap, so errors.As can also be
+// used to reach the provider's own error type.
 type JMSException interface {
 	GetReason() string
 	GetErrorCode() string
@@ -22,11 +30,32 @@ type JMSException interface {
 	Error() string
 }
 
+// RetryableException is implemented by the exceptions that can report whether
+// the failure they describe is likely to be temporary. Use IsRetryable to check
+// an error for this.
+type RetryableException interface {
+
+	// IsRetryable returns true if the failure is likely to be temporary, so
+	// that the same operation may succeed if it is tried again later, for
+	// example after reconnecting to the queue manager.
+	IsRetryable() bool
+}
+
+// IsRetryable returns true if the error, or an error that it wraps, is a
+// RetryableException that describes a failure that is likely to be temporary.
+func IsRetryable(err error) bool {
+
+	var retryable RetryableException
+	return errors.As(err, &retryable) && retryable.IsRetryable()
+
+}
+
 // JMSExceptionImpl is a struct that implements the JMSException interface
 type JMSExceptionImpl struct {
 	reason    string
 	errorCode string
 	linkedErr error
+	retryable bool
 }
 
 // GetReason returns the provider-specific reason string describing the error.
@@ -51,6 +80,31 @@ func (ex JMSExceptionImpl) GetLinkedError() error {
 
 }
 
+// Unwrap returns the linked Error object, so that errors.Is and errors.As can
+// examine the low-level problem.
+func (ex JMSExceptionImpl) Unwrap() error {
+
+	return ex.linkedErr
+
+}
+
+// IsRetryable returns true if the failure is likely to be temporary.
+func (ex JMSExceptionImpl) IsRetryable() bool {
+
+	return ex.retryable
+
+}
+
+// Is reports whether this exception matches the target error for errors.Is,
+// which is the case if the target is a JMSExceptionImpl with the same error
+// code and reason.
+func (ex JMSExceptionImpl) Is(target error) bool {
+
+	targetEx, ok := target.(JMSExceptionImpl)
+	return ok && targetEx.errorCode == ex.errorCode && targetEx.reason == ex.reason
+
+}
+
 // Error allows the JMSExceptionImpl struct to be treated as a Golang error,
 // while also returning a human readable string representation of the error.
 func (ex JMSExceptionImpl) Error() string {
@@ -75,3 +129,173 @@ func CreateJMSException(reason string, errorCode string, linkedErr error) JMSExc
 
 	return ex
 }
+
+// CreateRetryableJMSException is a helper function for creating a JMSException
+// that describes a failure that is likely to be temporary, such as the loss of
+// the connection to the queue manager.
+func CreateRetryableJMSException(reason string, errorCode string, linkedErr error) JMSException {
+
+	ex := JMSExceptionImpl{
+		reason:    reason,
+		errorCode: errorCode,
+		linkedErr: linkedErr,
+		retryable: true,
+	}
+
+	return ex
+}
+
+// InvalidDestinationException is returned when a destination is not understood
+// by the provider, or is no longer valid, for example because the queue does
+// not exist.
+type InvalidDestinationException struct {
+	JMSExceptionImpl
+}
+
+// Is reports whether the target is also an InvalidDestinationException, so that
+// errors.Is(err, InvalidDestinationException{}) can be used to check for this
+// type of failure. Otherwise it is compared in the same way as a
+// JMSExceptionImpl, by its error code and reason.
+func (ex InvalidDestinationException) Is(target error) bool {
+	if _, ok := target.(InvalidDestinationException); ok {
+		return true
+	}
+	return ex.JMSExceptionImpl.Is(target)
+}
+
+// CreateInvalidDestinationException is a helper function for creating an
+// InvalidDestinationException.
+func CreateInvalidDestinationException(reason string, errorCode string, linkedErr error) JMSException {
+	return InvalidDestinationException{JMSExceptionImpl{reason: reason, errorCode: errorCode, linkedErr: linkedErr}}
+}
+
+// InvalidSelectorException is returned when the syntax of a message selector
+// is not valid.
+type InvalidSelectorException struct {
+	JMSExceptionImpl
+}
+
+// Is reports whether the target is also an InvalidSelectorException.
+func (ex InvalidSelectorException) Is(target error) bool {
+	if _, ok := target.(InvalidSelectorException); ok {
+		return true
+	}
+	return ex.JMSExceptionImpl.Is(target)
+}
+
+// CreateInvalidSelectorException is a helper function for creating an
+// InvalidSelectorException.
+func CreateInvalidSelectorException(reason string, errorCode string, linkedErr error) JMSException {
+	return InvalidSelectorException{JMSExceptionImpl{reason: reason, errorCode: errorCode, linkedErr: linkedErr}}
+}
+
+// MessageFormatException is returned when the body or a property of a message
+// cannot be converted to the type that has been asked for.
+type MessageFormatException struct {
+	JMSExceptionImpl
+}
+
+// Is reports whether the target is also a MessageFormatException.
+func (ex MessageFormatException) Is(target error) bool {
+	if _, ok := target.(MessageFormatException); ok {
+		return true
+	}
+	return ex.JMSExceptionImpl.Is(target)
+}
+
+// CreateMessageFormatException is a helper function for creating a
+// MessageFormatException.
+func CreateMessageFormatException(reason string, errorCode string, linkedErr error) JMSException {
+	return MessageFormatException{JMSExceptionImpl{reason: reason, errorCode: errorCode, linkedErr: linkedErr}}
+}
+
+// SecurityException is returned when the provider rejects the credentials that
+// were supplied, or the application is not authorized to carry out an operation.
+type SecurityException struct {
+	JMSExceptionImpl
+}
+
+// Is reports whether the target is also a SecurityException.
+func (ex SecurityException) Is(target error) bool {
+	if _, ok := target.(SecurityException); ok {
+		return true
+	}
+	return ex.JMSExceptionImpl.Is(target)
+}
+
+// CreateSecurityException is a helper function for creating a SecurityException.
+func CreateSecurityException(reason string, errorCode string, linkedErr error) JMSException {
+	return SecurityException{JMSExceptionImpl{reason: reason, errorCode: errorCode, linkedErr: linkedErr}}
+}
+
+// TransactionRolledBackException is returned when a call to Commit results in
+// the transaction being rolled back. The work that was done in the transaction
+// can be tried again, so it is always retryable.
+type TransactionRolledBackException struct {
+	JMSExceptionImpl
+}
+
+// Is reports whether the target is also a TransactionRolledBackException.
+func (ex TransactionRolledBackException) Is(target error) bool {
+	if _, ok := target.(TransactionRolledBackException); ok {
+		return true
+	}
+	return ex.JMSExceptionImpl.Is(target)
+}
+
+// IsRetryable returns true, as a transaction that was rolled back can be tried again.
+func (ex TransactionRolledBackException) IsRetryable() bool {
+	return true
+}
+
+// CreateTransactionRolledBackException is a helper function for creating a
+// TransactionRolledBackException.
+func CreateTransactionRolledBackException(reason string, errorCode string, linkedErr error) JMSException {
+	return TransactionRolledBackException{JMSExceptionImpl{reason: reason, errorCode: errorCode, linkedErr: linkedErr}}
+}
+
+// IllegalStateException is returned when a function is called at a time when
+// it cannot be carried out, for example using an object after it has been closed.
+type IllegalStateException struct {
+	JMSExceptionImpl
+}
+
+// Is reports whether the target is also an IllegalStateException.
+func (ex IllegalStateException) Is(target error) bool {
+	if _, ok := target.(IllegalStateException); ok {
+		return true
+	}
+	return ex.JMSExceptionImpl.Is(target)
+}
+
+// CreateIllegalStateException is a helper function for creating an
+// IllegalStateException.
+func CreateIllegalStateException(reason string, errorCode string, linkedErr error) JMSException {
+	return IllegalStateException{JMSExceptionImpl{reason: reason, errorCode: errorCode, linkedErr: linkedErr}}
+}
+
+// ResourceAllocationException is returned when the provider is unable to
+// allocate the resources needed by an operation, for example because a queue
+// is full. These conditions are usually temporary, so it is always retryable.
+type ResourceAllocationException struct {
+	JMSExceptionImpl
+}
+
+// Is reports whether the target is also a ResourceAllocationException.
+func (ex ResourceAllocationException) Is(target error) bool {
+	if _, ok := target.(ResourceAllocationException); ok {
+		return true
+	}
+	return ex.JMSExceptionImpl.Is(target)
+}
+
+// IsRetryable returns true, as resources may become available later.
+func (ex ResourceAllocationException) IsRetryable() bool {
+	return true
+}
+
+// CreateResourceAllocationException is a helper function for creating a
+// ResourceAllocationException.
+func CreateResourceAllocationException(reason string, errorCode string, linkedErr error) JMSException {
+	return ResourceAllocationException{JMSExceptionImpl{reason: reason, errorCode: errorCode, linkedErr: linkedErr}}
+}
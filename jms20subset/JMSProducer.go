@@ -10,6 +10,11 @@
 // Package jms20subset provides interfaces for messaging applications in the style of the Java Message Service (JMS) API.
 package jms20subset
 
+import (
+	"context"
+	"io"
+)
+
 // JMSProducer is a simple object used to send messages on behalf of a
 // JMSContext. It provides various methods to send a message to a specified
 // Destination. It also provides methods to allow message options to be
@@ -20,6 +25,11 @@ type JMSProducer interface {
 	// that are defined on this JMSProducer.
 	Send(dest Destination, msg Message) JMSException
 
+	// SendContext sends a message to the specified Destination in the same
+	// way as Send, but gives up and returns an error if the supplied Go context
+	// is cancelled or reaches its deadline before the message can be sent.
+	SendContext(ctx context.Context, dest Destination, msg Message) JMSException
+
 	// Send a TextMessage with the specified body to the specified Destination
 	// using any message options that are defined on this JMSProducer.
 	//
@@ -34,6 +44,17 @@ type JMSProducer interface {
 	// name and different parameters we must use a different function name.
 	SendBytes(dest Destination, body []byte) JMSException
 
+	// SendStream sends the data that is read from the stream until it reaches
+	// io.EOF to the specified Destination, so that it can be received by a
+	// JMSConsumer using ReceiveStream. This allows data that is too large to be
+	// held in memory to be sent, as the stream is read and sent in chunks.
+	//
+	// If the JMSContext is transacted then the whole stream is sent under the
+	// transaction, and is only received once the transaction is committed.
+	// Otherwise nothing is left on the Destination if the stream or a send fails
+	// part way through.
+	SendStream(dest Destination, stream io.Reader) JMSException
+
 	// SetDeliveryMode sets the delivery mode of messages sent using this
 	// JMSProducer - for example whether a message is persistent or non-persistent.
 	//
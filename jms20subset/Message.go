@@ -96,4 +96,13 @@ type Message interface {
 
 	// ClearProperties removes all message properties from this message.
 	ClearProperties() JMSException
+
+	// Release frees the resources that are held by this message, such as the
+	// storage for its message properties, without waiting for the message to be
+	// garbage collected. The message properties cannot be used after the message
+	// has been released.
+	//
+	// Calling Release is optional, but is recommended for applications that send
+	// or receive a large number of messages.
+	Release()
 }
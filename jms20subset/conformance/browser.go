@@ -0,0 +1,124 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package conformance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// browseNext browses the next message and checks that it is the expected one, or
+// that there is no next message if expected is nil.
+func browseNext(t *testing.T, iter jms20subset.MessageIterator, expected jms20subset.Message) {
+
+	gotMsg, brErr := iter.GetNext()
+	assert.Nil(t, brErr)
+
+	if expected == nil {
+		assert.Nil(t, gotMsg)
+	} else if assert.NotNil(t, gotMsg) {
+		assert.Equal(t, expected.GetJMSMessageID(), gotMsg.GetJMSMessageID())
+	}
+}
+
+// testQueueBrowser checks that a browser returns the messages on the queue in the
+// order they would be received, including those sent while it is browsing,
+// without removing them.
+func testQueueBrowser(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	producer := context.CreateProducer()
+	msg1 := context.CreateTextMessageWithString("browser msg 1")
+	msg2 := context.CreateTextMessageWithString("browser msg 2")
+	assert.Nil(t, producer.Send(queue, msg1))
+	assert.Nil(t, producer.Send(queue, msg2))
+
+	browser, brErr := context.CreateBrowser(queue)
+	if !assert.Nil(t, brErr) {
+		return
+	}
+	defer browser.Close()
+
+	iter, iterErr := browser.GetEnumeration()
+	if !assert.Nil(t, iterErr) {
+		return
+	}
+
+	browseNext(t, iter, msg1)
+
+	msg3 := context.CreateTextMessageWithString("browser msg 3")
+	assert.Nil(t, producer.Send(queue, msg3))
+
+	browseNext(t, iter, msg2)
+	browseNext(t, iter, msg3)
+	browseNext(t, iter, nil)
+
+	// Browsing did not remove any of the messages.
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	for _, expected := range []string{"browser msg 1", "browser msg 2", "browser msg 3"} {
+		assert.Equal(t, expected, receiveText(t, consumer))
+	}
+
+	browser.Close()
+	_, brErr = iter.GetNext()
+	checkIllegalState(t, brErr, "MQJMS_E_CONSUMER_CLOSED")
+
+}
+
+// testQueueBrowserWhileGetting checks that a browser skips over messages that
+// are received by a consumer while it is browsing.
+func testQueueBrowserWhileGetting(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	producer := context.CreateProducer()
+	msgs := make([]jms20subset.TextMessage, 0, 6)
+	for _, body := range []string{"msg 1", "msg 2", "msg 3", "msg 4", "msg 5", "msg 6"} {
+		msg := context.CreateTextMessageWithString(body)
+		msgs = append(msgs, msg)
+	}
+
+	assert.Nil(t, producer.Send(queue, msgs[0]))
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	browser, brErr := context.CreateBrowser(queue)
+	if !assert.Nil(t, brErr) {
+		return
+	}
+	defer browser.Close()
+
+	iter, iterErr := browser.GetEnumeration()
+	if !assert.Nil(t, iterErr) {
+		return
+	}
+
+	// The only message is received before it is browsed.
+	assert.Equal(t, "msg 1", receiveText(t, consumer))
+	browseNext(t, iter, nil)
+
+	for _, msg := range msgs[1:] {
+		assert.Nil(t, producer.Send(queue, msg))
+	}
+
+	browseNext(t, iter, msgs[1])
+	browseNext(t, iter, msgs[2])
+
+	// Messages that are received ahead of the browser are not browsed.
+	assert.Equal(t, "msg 2", receiveText(t, consumer))
+	assert.Equal(t, "msg 3", receiveText(t, consumer))
+	assert.Equal(t, "msg 4", receiveText(t, consumer))
+
+	browseNext(t, iter, msgs[4])
+	browseNext(t, iter, msgs[5])
+	browseNext(t, iter, nil)
+
+}
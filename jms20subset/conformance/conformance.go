@@ -0,0 +1,156 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package conformance provides a suite of tests that checks that a provider of
+// the jms20subset interfaces behaves in the same way as the IBM MQ provider.
+//
+// The suite is run from a test of the provider by passing a function that
+// creates its ConnectionFactory, for example:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, func() (jms20subset.ConnectionFactory, error) {
+//			return memjms.NewConnectionFactory(), nil
+//		})
+//	}
+package conformance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// QueueName is the name of the queue that the tests send messages to. It must
+// exist on the provider, and any messages already on it are removed by the tests.
+const QueueName string = "DEV.QUEUE.1"
+
+// ReplyQueueName is the name of a second queue, which the tests use as the
+// destination for replies. It must exist on the provider.
+const ReplyQueueName string = "DEV.QUEUE.2"
+
+// FactoryConstructor creates the ConnectionFactory of the provider that is being
+// tested. It is called once for each test in the suite.
+type FactoryConstructor func() (jms20subset.ConnectionFactory, error)
+
+// conformanceTest is a single test in the suite, which is given a JMSContext
+// with the default session mode and an empty queue.
+type conformanceTest struct {
+	name string
+	run  func(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue)
+}
+
+// tests lists every test in the suite, in the order that they are run.
+var tests = []conformanceTest{
+	{"TextMessageBody", testTextMessageBody},
+	{"BytesMessageBody", testBytesMessageBody},
+	{"MixedMessageTypes", testMixedMessageTypes},
+	{"ReceiveInto", testReceiveInto},
+	{"DeliveryModeAndPriority", testDeliveryModeAndPriority},
+	{"PriorityOrdering", testPriorityOrdering},
+	{"TimeToLive", testTimeToLive},
+	{"ReceiveWithWait", testReceiveWithWait},
+	{"ReplyTo", testReplyTo},
+	{"CorrelIDParsing", testCorrelIDParsing},
+	{"CorrelIDParsingOnSend", testCorrelIDParsingOnSend},
+	{"SelectorParsing", testSelectorParsing},
+	{"GetByCorrelID", testGetByCorrelID},
+	{"GetByMsgID", testGetByMsgID},
+	{"PropertyExistsGetNames", testPropertyExistsGetNames},
+	{"PropertyConversionString", testPropertyConversionString},
+	{"PropertyConversionOtherTypes", testPropertyConversionOtherTypes},
+	{"MessageRelease", testMessageRelease},
+	{"PutTransaction", testPutTransaction},
+	{"GetTransaction", testGetTransaction},
+	{"IndependentTransactions", testIndependentTransactions},
+	{"CloseRollsBack", testCloseRollsBack},
+	{"CascadeClose", testCascadeClose},
+	{"QueueBrowser", testQueueBrowser},
+	{"QueueBrowserWhileGetting", testQueueBrowserWhileGetting},
+	{"Stream", testStream},
+	{"StreamClose", testStreamClose},
+	{"StreamTransaction", testStreamTransaction},
+}
+
+// Run runs every test in the suite against the provider, each as a subtest of t.
+func Run(t *testing.T, newFactory FactoryConstructor) {
+
+	for _, test := range tests {
+
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+
+			cf, cfErr := newFactory()
+			if !assert.Nil(t, cfErr) {
+				return
+			}
+
+			context, ctxErr := cf.CreateContext()
+			if !assert.Nil(t, ctxErr) {
+				return
+			}
+			defer context.Close()
+
+			queue := context.CreateQueue(QueueName)
+
+			// Start with empty queues, in case a previous run left messages behind.
+			drainQueue(t, context, queue)
+			drainQueue(t, context, context.CreateQueue(ReplyQueueName))
+
+			test.run(t, cf, context, queue)
+
+			// Tidy up after a failure, so that the next test starts cleanly.
+			drainQueue(t, context, queue)
+		})
+	}
+}
+
+// drainQueue receives any messages that are on the queue.
+func drainQueue(t *testing.T, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	consumer, conErr := context.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer consumer.Close()
+
+	for {
+		msg, rcvErr := consumer.ReceiveNoWait()
+		if msg == nil || rcvErr != nil {
+			return
+		}
+	}
+}
+
+// createConsumer creates a consumer for the queue, failing the test if that is
+// not possible.
+func createConsumer(t *testing.T, context jms20subset.JMSContext, queue jms20subset.Queue) jms20subset.JMSConsumer {
+
+	consumer, conErr := context.CreateConsumer(queue)
+	if conErr != nil {
+		t.Fatalf("Unable to create a consumer: %v", conErr)
+	}
+
+	return consumer
+}
+
+// receiveText receives a message with no wait and returns its body, failing the
+// test if it is not a TextMessage.
+func receiveText(t *testing.T, consumer jms20subset.JMSConsumer) string {
+
+	msg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+
+	textMsg, isText := msg.(jms20subset.TextMessage)
+	if !isText || textMsg.GetText() == nil {
+		assert.Fail(t, "Did not receive a text message with a body")
+		return ""
+	}
+
+	return *textMsg.GetText()
+}
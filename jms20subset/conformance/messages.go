@@ -0,0 +1,333 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package conformance
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// testTextMessageBody checks that the body of a TextMessage is received as it was
+// sent, and that an empty body is received as nil.
+func testTextMessageBody(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	producer := context.CreateProducer()
+
+	for _, body := range []string{"Hello conformance", "  with spaces  "} {
+		assert.Nil(t, producer.SendString(queue, body))
+		assert.Equal(t, body, receiveText(t, consumer))
+	}
+
+	// A message with no body, and one with an empty body, are both received with
+	// a nil body.
+	assert.Nil(t, producer.Send(queue, context.CreateTextMessage()))
+	assert.Nil(t, producer.SendString(queue, ""))
+
+	for i := 0; i < 2; i++ {
+		msg, rcvErr := consumer.ReceiveNoWait()
+		assert.Nil(t, rcvErr)
+		textMsg, isText := msg.(jms20subset.TextMessage)
+		assert.True(t, isText)
+		if isText {
+			assert.Nil(t, textMsg.GetText())
+		}
+	}
+
+	// Nothing else is left on the queue.
+	msg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, msg)
+
+}
+
+// testBytesMessageBody checks that the body of a BytesMessage is received as it
+// was sent, including when it is empty.
+func testBytesMessageBody(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	producer := context.CreateProducer()
+	msgBytes := []byte{'b', 'y', 't', 'e', 's', 0, 1, 2}
+
+	msg := context.CreateBytesMessage()
+	assert.Equal(t, 0, msg.GetBodyLength())
+	msg.WriteBytes(msgBytes)
+	assert.Equal(t, len(msgBytes), msg.GetBodyLength())
+
+	assert.Nil(t, producer.Send(queue, msg))
+	assert.Nil(t, producer.Send(queue, context.CreateBytesMessage()))
+	assert.Nil(t, producer.SendBytes(queue, msgBytes))
+
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	bytesMsg, isBytes := rcvMsg.(jms20subset.BytesMessage)
+	if assert.True(t, isBytes) {
+		assert.Equal(t, msgBytes, *bytesMsg.ReadBytes())
+		assert.Equal(t, len(msgBytes), bytesMsg.GetBodyLength())
+	}
+
+	rcvBytes, rcvErr := consumer.ReceiveBytesBodyNoWait()
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, rcvBytes) {
+		assert.Equal(t, 0, len(*rcvBytes))
+	}
+
+	rcvBytes, rcvErr = consumer.ReceiveBytesBody(1000)
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, rcvBytes) {
+		assert.Equal(t, msgBytes, *rcvBytes)
+	}
+
+}
+
+// testMixedMessageTypes checks that receiving the body of a message as the wrong
+// type returns a MessageFormatException.
+func testMixedMessageTypes(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	producer := context.CreateProducer()
+	assert.Nil(t, producer.SendBytes(queue, []byte("bytes")))
+	assert.Nil(t, producer.SendString(queue, "text"))
+
+	strBody, rcvErr := consumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, strBody)
+	if assert.NotNil(t, rcvErr) {
+		assert.Equal(t, "MQJMS_DIR_MIN_NOTTEXT", rcvErr.GetReason())
+		assert.Equal(t, "MQJMS6068", rcvErr.GetErrorCode())
+	}
+
+	bytesBody, rcvErr := consumer.ReceiveBytesBodyNoWait()
+	assert.Nil(t, bytesBody)
+	if assert.NotNil(t, rcvErr) {
+		assert.Equal(t, "MQJMS_DIR_MIN_NOTBYTES", rcvErr.GetReason())
+		assert.Equal(t, "MQJMS6068", rcvErr.GetErrorCode())
+	}
+
+}
+
+// testReceiveInto checks receiving into a buffer supplied by the application,
+// and that a message which does not fit stays on the queue.
+func testReceiveInto(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	producer := context.CreateProducer()
+	buffer := make([]byte, 10)
+
+	msgBytes := []byte{1, 2, 3, 4}
+	assert.Nil(t, producer.SendBytes(queue, msgBytes))
+
+	rcvMsg, rcvErr := consumer.ReceiveInto(buffer, 1000)
+	assert.Nil(t, rcvErr)
+	bytesMsg, isBytes := rcvMsg.(jms20subset.BytesMessage)
+	if assert.True(t, isBytes) {
+		assert.Equal(t, msgBytes, *bytesMsg.ReadBytes())
+		assert.Equal(t, msgBytes, buffer[0:4])
+	}
+
+	assert.Nil(t, producer.SendString(queue, "fits"))
+	rcvMsg, rcvErr = consumer.ReceiveInto(buffer, 1000)
+	assert.Nil(t, rcvErr)
+	textMsg, isText := rcvMsg.(jms20subset.TextMessage)
+	if assert.True(t, isText) {
+		assert.Equal(t, "fits", *textMsg.GetText())
+	}
+
+	assert.Nil(t, producer.SendBytes(queue, make([]byte, 100)))
+	rcvMsg, rcvErr = consumer.ReceiveInto(buffer, 1000)
+	assert.Nil(t, rcvMsg)
+	if assert.NotNil(t, rcvErr) {
+		assert.Equal(t, "MQRC_TRUNCATED_MSG_FAILED", rcvErr.GetReason())
+		assert.Equal(t, "2080", rcvErr.GetErrorCode())
+	}
+
+	rcvBytes, rcvErr := consumer.ReceiveBytesBodyNoWait()
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, rcvBytes) {
+		assert.Equal(t, 100, len(*rcvBytes))
+	}
+
+}
+
+// testDeliveryModeAndPriority checks the delivery mode and priority that are set on
+// the producer, and that they are received with the message.
+func testDeliveryModeAndPriority(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	producer := context.CreateProducer()
+	assert.Equal(t, jms20subset.DeliveryMode_PERSISTENT, producer.GetDeliveryMode())
+	assert.Equal(t, jms20subset.Priority_DEFAULT, producer.GetPriority())
+	assert.Equal(t, 0, producer.GetTimeToLive())
+
+	// Invalid values are ignored, so that method chaining can be used.
+	producer.SetDeliveryMode(42).SetPriority(-1).SetTimeToLive(-1)
+	assert.Equal(t, jms20subset.DeliveryMode_PERSISTENT, producer.GetDeliveryMode())
+	assert.Equal(t, jms20subset.Priority_DEFAULT, producer.GetPriority())
+	assert.Equal(t, 0, producer.GetTimeToLive())
+
+	checks := []struct {
+		deliveryMode int
+		priority     int
+	}{
+		{jms20subset.DeliveryMode_PERSISTENT, jms20subset.Priority_DEFAULT},
+		{jms20subset.DeliveryMode_NON_PERSISTENT, 2},
+		{jms20subset.DeliveryMode_PERSISTENT, 7},
+	}
+
+	for _, check := range checks {
+
+		producer.SetDeliveryMode(check.deliveryMode).SetPriority(check.priority)
+		assert.Equal(t, check.deliveryMode, producer.GetDeliveryMode())
+		assert.Equal(t, check.priority, producer.GetPriority())
+
+		msg := context.CreateTextMessageWithString("mode and priority")
+		assert.Nil(t, producer.Send(queue, msg))
+		assert.Equal(t, check.deliveryMode, msg.GetJMSDeliveryMode())
+		assert.Equal(t, check.priority, msg.GetJMSPriority())
+
+		rcvMsg, rcvErr := consumer.ReceiveNoWait()
+		assert.Nil(t, rcvErr)
+		if assert.NotNil(t, rcvMsg) {
+			assert.Equal(t, check.deliveryMode, rcvMsg.GetJMSDeliveryMode())
+			assert.Equal(t, check.priority, rcvMsg.GetJMSPriority())
+			assert.Equal(t, msg.GetJMSMessageID(), rcvMsg.GetJMSMessageID())
+		}
+	}
+
+}
+
+// testPriorityOrdering checks that messages are received highest priority first,
+// and in the order they were sent within each priority.
+func testPriorityOrdering(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	producer := context.CreateProducer()
+
+	sends := []struct {
+		priority int
+		body     string
+	}{
+		{1, "low 1"}, {4, "default 1"}, {9, "highest"}, {1, "low 2"},
+		{6, "high 1"}, {4, "default 2"}, {6, "high 2"}, {0, "lowest"},
+	}
+
+	for _, send := range sends {
+		assert.Nil(t, producer.SetPriority(send.priority).SendString(queue, send.body))
+	}
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	for _, expected := range []string{"highest", "high 1", "high 2", "default 1", "default 2", "low 1", "low 2", "lowest"} {
+		assert.Equal(t, expected, receiveText(t, consumer))
+	}
+
+}
+
+// testTimeToLive checks that a message can be received until its time to live has
+// passed, and that its expiration is the time it was sent plus the time to live.
+func testTimeToLive(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	ttlMillis := 1000
+	producer := context.CreateProducer().SetTimeToLive(ttlMillis)
+	assert.Equal(t, ttlMillis, producer.GetTimeToLive())
+
+	sendTime := time.Now().UnixNano() / int64(time.Millisecond)
+	assert.Nil(t, producer.SendString(queue, "Get me before I expire!"))
+
+	time.Sleep(500 * time.Millisecond)
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, rcvMsg) {
+
+		// Allow for the timestamp being held with less precision by some providers.
+		timestamp := rcvMsg.GetJMSTimestamp()
+		assert.True(t, math.Abs(float64(timestamp-sendTime)) < 250)
+
+		expirationDiff := timestamp + int64(ttlMillis) - rcvMsg.GetJMSExpiration()
+		assert.True(t, math.Abs(float64(expirationDiff)) < 250)
+	}
+
+	assert.Nil(t, producer.SendString(queue, "Catch me if you can!"))
+	time.Sleep(1500 * time.Millisecond)
+	rcvMsg, rcvErr = consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+}
+
+// testReceiveWithWait checks that a receive waits for up to the specified time
+// when there is no message, and returns a message that arrives while it waits.
+func testReceiveWithWait(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	waitMillis := int32(500)
+	start := time.Now()
+	rcvMsg, rcvErr := consumer.Receive(waitMillis)
+	elapsed := time.Since(start)
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+	assert.True(t, elapsed >= time.Duration(waitMillis)*time.Millisecond)
+	assert.True(t, elapsed < 5*time.Second)
+
+	// Send from another JMSContext while the receive is waiting.
+	sendContext, ctxErr := context.CreateContext(jms20subset.JMSContextAUTOACKNOWLEDGE)
+	if !assert.Nil(t, ctxErr) {
+		return
+	}
+	defer sendContext.Close()
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		sendContext.CreateProducer().SendString(queue, "worth the wait")
+	}()
+
+	rcvBody, rcvErr := consumer.ReceiveStringBody(5000)
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, rcvBody) {
+		assert.Equal(t, "worth the wait", *rcvBody)
+	}
+
+}
+
+// testReplyTo checks that the reply destination is received with the message.
+func testReplyTo(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	msg := context.CreateTextMessageWithString("request")
+	assert.Nil(t, msg.GetJMSReplyTo())
+	assert.Nil(t, msg.SetJMSReplyTo(context.CreateQueue(ReplyQueueName)))
+	assert.Nil(t, context.CreateProducer().Send(queue, msg))
+
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, rcvMsg) && assert.NotNil(t, rcvMsg.GetJMSReplyTo()) {
+		assert.Equal(t, ReplyQueueName, rcvMsg.GetJMSReplyTo().GetDestinationName())
+	}
+
+}
@@ -0,0 +1,328 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package conformance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// sendAndReceive sends the message to the queue and receives it again.
+func sendAndReceive(t *testing.T, context jms20subset.JMSContext, queue jms20subset.Queue, msg jms20subset.Message) jms20subset.Message {
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	assert.Nil(t, context.CreateProducer().Send(queue, msg))
+
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	if rcvMsg == nil {
+		t.Fatalf("Did not receive the message that was sent")
+	}
+
+	return rcvMsg
+}
+
+// testPropertyExistsGetNames checks that properties can be set, replaced and
+// removed, and that the names are returned in the order they were first set.
+func testPropertyExistsGetNames(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	msg := context.CreateTextMessageWithString("properties")
+
+	propNames, namesErr := msg.GetPropertyNames()
+	assert.Nil(t, namesErr)
+	assert.Equal(t, 0, len(propNames))
+
+	exists, propErr := msg.PropertyExists("myProperty")
+	assert.Nil(t, propErr)
+	assert.False(t, exists)
+
+	value, value2 := "myValue", "myValue2"
+	assert.Nil(t, msg.SetStringProperty("myProperty", &value))
+	assert.Nil(t, msg.SetIntProperty("myIntProperty", 42))
+	assert.Nil(t, msg.SetBooleanProperty("myBoolProperty", true))
+	assert.Nil(t, msg.SetDoubleProperty("myDoubleProperty", 3.5))
+
+	// Setting a property again replaces its value but keeps its position.
+	assert.Nil(t, msg.SetStringProperty("myProperty", &value2))
+
+	exists, propErr = msg.PropertyExists("myProperty")
+	assert.Nil(t, propErr)
+	assert.True(t, exists)
+
+	expectedNames := []string{"myProperty", "myIntProperty", "myBoolProperty", "myDoubleProperty"}
+	propNames, namesErr = msg.GetPropertyNames()
+	assert.Nil(t, namesErr)
+	assert.Equal(t, expectedNames, propNames)
+
+	// The properties are received with the message.
+	rcvMsg := sendAndReceive(t, context, queue, msg)
+
+	propNames, namesErr = rcvMsg.GetPropertyNames()
+	assert.Nil(t, namesErr)
+	assert.Equal(t, expectedNames, propNames)
+
+	gotValue, propErr := rcvMsg.GetStringProperty("myProperty")
+	assert.Nil(t, propErr)
+	if assert.NotNil(t, gotValue) {
+		assert.Equal(t, value2, *gotValue)
+	}
+
+	gotInt, propErr := rcvMsg.GetIntProperty("myIntProperty")
+	assert.Nil(t, propErr)
+	assert.Equal(t, 42, gotInt)
+
+	gotBool, propErr := rcvMsg.GetBooleanProperty("myBoolProperty")
+	assert.Nil(t, propErr)
+	assert.True(t, gotBool)
+
+	gotDouble, propErr := rcvMsg.GetDoubleProperty("myDoubleProperty")
+	assert.Nil(t, propErr)
+	assert.Equal(t, 3.5, gotDouble)
+
+	// Setting a string property to nil removes it.
+	assert.Nil(t, rcvMsg.SetStringProperty("myProperty", nil))
+	exists, propErr = rcvMsg.PropertyExists("myProperty")
+	assert.Nil(t, propErr)
+	assert.False(t, exists)
+
+	gotValue, propErr = rcvMsg.GetStringProperty("myProperty")
+	assert.Nil(t, propErr)
+	assert.Nil(t, gotValue)
+
+	propNames, namesErr = rcvMsg.GetPropertyNames()
+	assert.Nil(t, namesErr)
+	assert.Equal(t, expectedNames[1:], propNames)
+
+	// ClearProperties removes all of them.
+	assert.Nil(t, rcvMsg.ClearProperties())
+	propNames, namesErr = rcvMsg.GetPropertyNames()
+	assert.Nil(t, namesErr)
+	assert.Equal(t, 0, len(propNames))
+
+}
+
+// testPropertyConversionString checks the conversion of string properties to the
+// other property types.
+func testPropertyConversionString(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	checks := []struct {
+		value     string
+		intOK     bool
+		intVal    int
+		boolOK    bool
+		boolVal   bool
+		doubleOK  bool
+		doubleVal float64
+	}{
+		{"myValue", false, 0, false, false, false, 0},
+		{"", false, 0, false, false, false, 0},
+		{"245", true, 245, false, false, true, 245},
+		{"-34678", true, -34678, false, false, true, -34678},
+		{"true", false, 0, true, true, false, 0},
+		{"false", false, 0, true, false, false, 0},
+		{"2.718527453", false, 0, false, false, true, 2.718527453},
+		{"-25675752.212345678", false, 0, false, false, true, -25675752.212345678},
+	}
+
+	msg := context.CreateTextMessage()
+	for i := range checks {
+		assert.Nil(t, msg.SetStringProperty(propertyName(i), &checks[i].value))
+	}
+
+	rcvMsg := sendAndReceive(t, context, queue, msg)
+
+	for i, check := range checks {
+
+		name := propertyName(i)
+
+		gotStr, propErr := rcvMsg.GetStringProperty(name)
+		assert.Nil(t, propErr)
+		if assert.NotNil(t, gotStr) {
+			assert.Equal(t, check.value, *gotStr)
+		}
+
+		gotInt, propErr := rcvMsg.GetIntProperty(name)
+		checkConversion(t, check.value, check.intOK, propErr)
+		assert.Equal(t, check.intVal, gotInt, "Int of %q", check.value)
+
+		gotBool, propErr := rcvMsg.GetBooleanProperty(name)
+		checkConversion(t, check.value, check.boolOK, propErr)
+		assert.Equal(t, check.boolVal, gotBool, "Boolean of %q", check.value)
+
+		gotDouble, propErr := rcvMsg.GetDoubleProperty(name)
+		checkConversion(t, check.value, check.doubleOK, propErr)
+		assert.Equal(t, check.doubleVal, gotDouble, "Double of %q", check.value)
+	}
+
+	// A property that is not set is returned as the zero value, without an error.
+	gotStr, propErr := rcvMsg.GetStringProperty("thisPropertyIsNotSet")
+	assert.Nil(t, propErr)
+	assert.Nil(t, gotStr)
+
+	gotInt, propErr := rcvMsg.GetIntProperty("thisPropertyIsNotSet")
+	assert.Nil(t, propErr)
+	assert.Equal(t, 0, gotInt)
+
+}
+
+// testPropertyConversionOtherTypes checks the conversion of int, boolean and
+// double properties to the other property types.
+func testPropertyConversionOtherTypes(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	msg := context.CreateBytesMessage()
+	msg.WriteBytes([]byte("properties"))
+	assert.Nil(t, msg.SetIntProperty("intOne", 1))
+	assert.Nil(t, msg.SetIntProperty("intLarge", -3789753467))
+	assert.Nil(t, msg.SetBooleanProperty("boolTrue", true))
+	assert.Nil(t, msg.SetBooleanProperty("boolFalse", false))
+	assert.Nil(t, msg.SetDoubleProperty("doubleDecimal", 3867493.68473625))
+	assert.Nil(t, msg.SetDoubleProperty("doubleNegative", -87654335674.383656))
+
+	rcvMsg := sendAndReceive(t, context, queue, msg)
+
+	stringChecks := map[string]string{
+		"intOne":    "1",
+		"intLarge":  "-3789753467",
+		"boolTrue":  "true",
+		"boolFalse": "false",
+	}
+	for name, expected := range stringChecks {
+		gotStr, propErr := rcvMsg.GetStringProperty(name)
+		assert.Nil(t, propErr)
+		if assert.NotNil(t, gotStr, name) {
+			assert.Equal(t, expected, *gotStr, name)
+		}
+	}
+
+	intChecks := map[string]int{
+		"intOne":         1,
+		"intLarge":       -3789753467,
+		"boolTrue":       1,
+		"boolFalse":      0,
+		"doubleDecimal":  3867494,
+		"doubleNegative": -87654335674,
+	}
+	for name, expected := range intChecks {
+		gotInt, propErr := rcvMsg.GetIntProperty(name)
+		assert.Nil(t, propErr)
+		assert.Equal(t, expected, gotInt, name)
+	}
+
+	boolChecks := map[string]bool{
+		"intOne":    true,
+		"intLarge":  false,
+		"boolTrue":  true,
+		"boolFalse": false,
+	}
+	for name, expected := range boolChecks {
+		gotBool, propErr := rcvMsg.GetBooleanProperty(name)
+		assert.Nil(t, propErr)
+		assert.Equal(t, expected, gotBool, name)
+	}
+
+	doubleChecks := map[string]float64{
+		"intOne":         1,
+		"intLarge":       -3789753467,
+		"boolTrue":       1,
+		"boolFalse":      0,
+		"doubleDecimal":  3867493.68473625,
+		"doubleNegative": -87654335674.383656,
+	}
+	for name, expected := range doubleChecks {
+		gotDouble, propErr := rcvMsg.GetDoubleProperty(name)
+		assert.Nil(t, propErr)
+		assert.Equal(t, expected, gotDouble, name)
+	}
+
+	// The body of the message is not affected by its properties.
+	if bytesMsg, isBytes := rcvMsg.(jms20subset.BytesMessage); assert.True(t, isBytes) {
+		assert.Equal(t, []byte("properties"), *bytesMsg.ReadBytes())
+	}
+
+}
+
+// testMessageRelease checks that the properties of a message cannot be used after
+// it has been released, and that a released message cannot be sent.
+func testMessageRelease(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	propValue := "myValue"
+	msg := context.CreateTextMessageWithString("ReleaseMsg")
+	assert.Nil(t, msg.SetStringProperty("myProperty", &propValue))
+	assert.Nil(t, context.CreateProducer().Send(queue, msg))
+
+	// The body is still available after a message is released, but the properties
+	// are not.
+	msg.Release()
+	assert.Equal(t, "ReleaseMsg", *msg.GetText())
+
+	_, propErr := msg.GetStringProperty("myProperty")
+	if assert.NotNil(t, propErr) {
+		assert.Equal(t, "MQRC_HMSG_ERROR", propErr.GetReason())
+		assert.Equal(t, "2460", propErr.GetErrorCode())
+	}
+
+	sendErr := context.CreateProducer().Send(queue, msg)
+	if assert.NotNil(t, sendErr) {
+		assert.Equal(t, "MQRC_HMSG_ERROR", sendErr.GetReason())
+	}
+
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	if !assert.NotNil(t, rcvMsg) {
+		return
+	}
+
+	gotValue, propErr := rcvMsg.GetStringProperty("myProperty")
+	assert.Nil(t, propErr)
+	if assert.NotNil(t, gotValue) {
+		assert.Equal(t, propValue, *gotValue)
+	}
+
+	// Releasing a message more than once has no further effect.
+	rcvMsg.Release()
+	rcvMsg.Release()
+
+	_, propErr = rcvMsg.GetPropertyNames()
+	if assert.NotNil(t, propErr) {
+		assert.Equal(t, "MQRC_HMSG_ERROR", propErr.GetReason())
+	}
+
+	// Only the message that was sent once is on the queue.
+	rcvMsg, rcvErr = consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+}
+
+// propertyName returns the name of the i'th property in a table driven test.
+func propertyName(i int) string {
+	return "property" + string(rune('A'+i))
+}
+
+// checkConversion checks that converting a property either succeeded, or returned
+// the error for a property that cannot be converted.
+func checkConversion(t *testing.T, value string, expectOK bool, propErr jms20subset.JMSException) {
+
+	if expectOK {
+		assert.Nil(t, propErr, "Converting %q", value)
+		return
+	}
+
+	if assert.NotNil(t, propErr, "Converting %q", value) {
+		assert.Equal(t, "MQJMS_E_BAD_TYPE", propErr.GetReason())
+		assert.Equal(t, "1055", propErr.GetErrorCode())
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package conformance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// correlIDCases are correlation IDs that are set on a message, together with the
+// value that is then returned by GetJMSCorrelationID.
+var correlIDCases = []struct {
+	input    string
+	expected string
+}{
+	{"", ""},
+	{"Hello World", "Hello World"},
+	{"  ", "  "},
+	{"010203040506", "010203040506"},
+
+	// Text that is longer than the 24 bytes of a correlation ID is truncated, and
+	// since it is hex encoded only the first 12 characters are kept.
+	{"ThisIsAVeryLongCorrelationIDWhichIsMoreThanTwentyFourCharacters", "ThisIsAVeryL"},
+
+	// The format of a message ID, which is used in request/reply scenarios.
+	{"414d5120514d312020202020202020201017155c0255b621", "414d5120514d312020202020202020201017155c0255b621"},
+
+	// A correlation ID that is all zeros is the same as not having one.
+	{"000000000000000000000000000000000000000000000000", ""},
+}
+
+// testCorrelIDParsing checks the correlation ID that is returned from a message
+// after it has been set.
+func testCorrelIDParsing(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	msg := context.CreateTextMessage()
+	assert.Equal(t, "", msg.GetJMSCorrelationID())
+
+	for _, correlCase := range correlIDCases {
+		assert.Nil(t, msg.SetJMSCorrelationID(correlCase.input))
+		assert.Equal(t, correlCase.expected, msg.GetJMSCorrelationID(), "Correlation ID %q", correlCase.input)
+	}
+
+}
+
+// testCorrelIDParsingOnSend checks the correlation ID that is received with a
+// message, for each of the forms of correlation ID.
+func testCorrelIDParsingOnSend(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	producer := context.CreateProducer()
+
+	for _, correlCase := range correlIDCases {
+
+		msg := context.CreateTextMessage()
+		assert.Nil(t, msg.SetJMSCorrelationID(correlCase.input))
+		assert.Nil(t, producer.Send(queue, msg))
+
+		rcvMsg, rcvErr := consumer.ReceiveNoWait()
+		assert.Nil(t, rcvErr)
+		if assert.NotNil(t, rcvMsg) {
+			assert.Equal(t, correlCase.expected, rcvMsg.GetJMSCorrelationID(), "Correlation ID %q", correlCase.input)
+		}
+	}
+
+}
+
+// testSelectorParsing checks which selectors are accepted when a consumer is
+// created, and that the others return an InvalidSelectorException.
+func testSelectorParsing(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	validSelectors := []string{
+		"",
+		"JMSCorrelationID = 'MyCorrelID'",
+		"JMSCorrelationID = '414d5120514d312020202020202020201017155c0255b621'",
+		"JMSMessageID = 'ID:1234'",
+		"JMSMessageID = '1234'",
+	}
+
+	for _, selector := range validSelectors {
+		consumer, conErr := context.CreateConsumerWithSelector(queue, selector)
+		assert.Nil(t, conErr, "Selector %q", selector)
+		if assert.NotNil(t, consumer, "Selector %q", selector) {
+			_, rcvErr := consumer.ReceiveNoWait()
+			assert.Nil(t, rcvErr)
+			consumer.Close()
+		}
+	}
+
+	invalidSelectors := []string{
+		"JMSCorrelationID",
+		"JMSCorrelationID = ",
+		"JMSCorrelationID = '",
+		"JMSCorrelationID = ''",
+		"JMSMessageID = 'ID:'",
+		"JMSPriority = '4'",
+	}
+
+	for _, selector := range invalidSelectors {
+		consumer, conErr := context.CreateConsumerWithSelector(queue, selector)
+		assert.Nil(t, consumer, "Selector %q", selector)
+		if assert.NotNil(t, conErr, "Selector %q", selector) {
+			assert.ErrorIs(t, conErr, jms20subset.InvalidSelectorException{})
+		}
+	}
+
+}
+
+// testGetByCorrelID checks that a consumer with a correlation ID selector receives
+// only the message with that correlation ID.
+func testGetByCorrelID(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	producer := context.CreateProducer()
+	producer.SendString(queue, "One")
+	producer.SendString(queue, "Two")
+
+	myCorrelID := "MyCorrelID"
+	sentMsg := context.CreateTextMessageWithString("Three")
+	assert.Nil(t, sentMsg.SetJMSCorrelationID(myCorrelID))
+	assert.Nil(t, producer.Send(queue, sentMsg))
+
+	producer.SendString(queue, "Four")
+
+	correlConsumer, conErr := context.CreateConsumerWithSelector(queue, "JMSCorrelationID = '"+myCorrelID+"'")
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer correlConsumer.Close()
+
+	rcvMsg, rcvErr := correlConsumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, rcvMsg) {
+		assert.Equal(t, sentMsg.GetJMSMessageID(), rcvMsg.GetJMSMessageID())
+		assert.Equal(t, myCorrelID, rcvMsg.GetJMSCorrelationID())
+	}
+
+	// There are no more messages with that correlation ID.
+	rcvMsg, rcvErr = correlConsumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+	// The other messages are still on the queue, in the order they were sent.
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	for _, expected := range []string{"One", "Two", "Four"} {
+		assert.Equal(t, expected, receiveText(t, consumer))
+	}
+
+}
+
+// testGetByMsgID checks that a consumer with a message ID selector receives the
+// message with that ID, with or without the "ID:" prefix.
+func testGetByMsgID(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	producer := context.CreateProducer()
+	producer.SendString(queue, "One")
+
+	sentMsg := context.CreateTextMessageWithString("Two")
+	assert.Nil(t, producer.Send(queue, sentMsg))
+
+	sentMsg2 := context.CreateTextMessageWithString("Three")
+	assert.Nil(t, producer.Send(queue, sentMsg2))
+
+	producer.SendString(queue, "Four")
+
+	for _, sent := range []jms20subset.TextMessage{sentMsg, sentMsg2} {
+
+		selector := "JMSMessageID = '" + sent.GetJMSMessageID() + "'"
+		if sent == sentMsg2 {
+			selector = "JMSMessageID = 'ID:" + sent.GetJMSMessageID() + "'"
+		}
+
+		msgIDConsumer, conErr := context.CreateConsumerWithSelector(queue, selector)
+		if !assert.Nil(t, conErr) {
+			return
+		}
+
+		rcvMsg, rcvErr := msgIDConsumer.ReceiveNoWait()
+		assert.Nil(t, rcvErr)
+		if assert.NotNil(t, rcvMsg) {
+			assert.Equal(t, sent.GetJMSMessageID(), rcvMsg.GetJMSMessageID())
+			assert.Equal(t, *sent.GetText(), *rcvMsg.(jms20subset.TextMessage).GetText())
+		}
+
+		msgIDConsumer.Close()
+	}
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	for _, expected := range []string{"One", "Four"} {
+		assert.Equal(t, expected, receiveText(t, consumer))
+	}
+
+}
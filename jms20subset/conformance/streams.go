@@ -0,0 +1,171 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package conformance
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// streamData returns data for a stream that is larger than the default receive
+// buffer size of the IBM MQ provider, so that it is sent in several chunks.
+func streamData() []byte {
+
+	data := make([]byte, 100000)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	return data
+}
+
+// receiveStream receives a stream and reads all of it, failing the test if there
+// is no stream or it cannot be read.
+func receiveStream(t *testing.T, consumer jms20subset.JMSConsumer) []byte {
+
+	reader, rcvErr := consumer.ReceiveStream(1000)
+	assert.Nil(t, rcvErr)
+	if reader == nil {
+		assert.Fail(t, "Did not receive a stream")
+		return nil
+	}
+
+	data, readErr := io.ReadAll(reader)
+	assert.Nil(t, readErr)
+	assert.Nil(t, reader.Close())
+
+	return data
+}
+
+// testStream checks that the data sent using SendStream is received as it was
+// sent by ReceiveStream, and that any other message is received as a stream
+// containing its body.
+func testStream(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	producer := context.CreateProducer()
+
+	data := streamData()
+	assert.Nil(t, producer.SendStream(queue, bytes.NewReader(data)))
+	assert.Equal(t, data, receiveStream(t, consumer))
+
+	assert.Nil(t, producer.SendStream(queue, bytes.NewReader(nil)))
+	assert.Equal(t, 0, len(receiveStream(t, consumer)))
+
+	assert.Nil(t, producer.SendString(queue, "not a stream"))
+	assert.Equal(t, "not a stream", string(receiveStream(t, consumer)))
+
+	// Nothing else is left on the queue.
+	reader, rcvErr := consumer.ReceiveStream(100)
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, reader)
+
+	// A stream that cannot be read is not received.
+	readErr := errors.New("conformance read failure")
+	sendErr := producer.SendStream(queue, iotest.ErrReader(readErr))
+	if assert.NotNil(t, sendErr) {
+		assert.True(t, errors.Is(sendErr, readErr))
+	}
+
+	reader, rcvErr = consumer.ReceiveStream(100)
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, reader)
+
+}
+
+// testStreamClose checks that a stream that is closed before all of it has been
+// read is left on the queue, and that a stream whose data has all been read is
+// removed from the queue when it is closed, even if io.EOF was not returned.
+func testStreamClose(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	data := streamData()
+	assert.Nil(t, context.CreateProducer().SendStream(queue, bytes.NewReader(data)))
+
+	reader, rcvErr := consumer.ReceiveStream(1000)
+	assert.Nil(t, rcvErr)
+	if !assert.NotNil(t, reader) {
+		return
+	}
+
+	start := make([]byte, 10)
+	_, readErr := io.ReadFull(reader, start)
+	assert.Nil(t, readErr)
+	assert.Equal(t, data[:10], start)
+
+	assert.Nil(t, reader.Close())
+	assert.Nil(t, reader.Close()) // Has no further effect
+
+	_, readErr = reader.Read(start)
+	assert.NotNil(t, readErr)
+
+	// Read exactly the length of the data, without reading io.EOF.
+	reader, rcvErr = consumer.ReceiveStream(1000)
+	assert.Nil(t, rcvErr)
+	if !assert.NotNil(t, reader) {
+		return
+	}
+
+	all := make([]byte, len(data))
+	_, readErr = io.ReadFull(reader, all)
+	assert.Nil(t, readErr)
+	assert.Equal(t, data, all)
+	assert.Nil(t, reader.Close())
+
+	reader, rcvErr = consumer.ReceiveStream(100)
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, reader)
+
+}
+
+// testStreamTransaction checks that a stream is sent and received under the
+// transaction of a transacted JMSContext.
+func testStreamTransaction(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	txContext := createTransactedContext(t, cf)
+	defer txContext.Close()
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	txConsumer := createConsumer(t, txContext, queue)
+	defer txConsumer.Close()
+
+	data := streamData()
+	assert.Nil(t, txContext.CreateProducer().SendStream(queue, bytes.NewReader(data)))
+
+	reader, rcvErr := consumer.ReceiveStream(100)
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, reader)
+
+	assert.Nil(t, txContext.Commit())
+
+	// The stream is received again once the transaction that received it is
+	// rolled back.
+	assert.Equal(t, data, receiveStream(t, txConsumer))
+	assert.Nil(t, txContext.Rollback())
+
+	assert.Equal(t, data, receiveStream(t, txConsumer))
+	assert.Nil(t, txContext.Commit())
+
+	reader, rcvErr = consumer.ReceiveStream(100)
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, reader)
+
+}
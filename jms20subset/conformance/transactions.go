@@ -0,0 +1,232 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package conformance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// createTransactedContext creates a JMSContext with the JMSContextSESSIONTRANSACTED
+// session mode, failing the test if that is not possible.
+func createTransactedContext(t *testing.T, cf jms20subset.ConnectionFactory) jms20subset.JMSContext {
+
+	txContext, ctxErr := cf.CreateContextWithSessionMode(jms20subset.JMSContextSESSIONTRANSACTED)
+	if ctxErr != nil {
+		t.Fatalf("Unable to create a transacted context: %v", ctxErr)
+	}
+
+	return txContext
+}
+
+// testPutTransaction checks that messages sent under a transaction are only seen
+// by other contexts once the transaction is committed.
+func testPutTransaction(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	txContext := createTransactedContext(t, cf)
+	defer txContext.Close()
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	txProducer := txContext.CreateProducer()
+	assert.Nil(t, txProducer.SendString(queue, "rolled back"))
+
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+	assert.Nil(t, txContext.Rollback())
+	rcvMsg, rcvErr = consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+	assert.Nil(t, txProducer.SendString(queue, "committed 1"))
+	assert.Nil(t, txProducer.SendString(queue, "committed 2"))
+
+	rcvMsg, rcvErr = consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+	assert.Nil(t, txContext.Commit())
+	assert.Equal(t, "committed 1", receiveText(t, consumer))
+	assert.Equal(t, "committed 2", receiveText(t, consumer))
+
+}
+
+// testGetTransaction checks that messages received under a transaction are put
+// back on the queue when the transaction is rolled back.
+func testGetTransaction(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	producer := context.CreateProducer()
+	assert.Nil(t, producer.SendString(queue, "first"))
+	assert.Nil(t, producer.SendString(queue, "second"))
+
+	txContext := createTransactedContext(t, cf)
+	defer txContext.Close()
+
+	txConsumer := createConsumer(t, txContext, queue)
+	defer txConsumer.Close()
+
+	assert.Equal(t, "first", receiveText(t, txConsumer))
+	assert.Equal(t, "second", receiveText(t, txConsumer))
+
+	// Other contexts do not see messages that have been received under a
+	// transaction that is still in progress.
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+	// A rollback puts the messages back on the queue in their original order.
+	assert.Nil(t, txContext.Rollback())
+	assert.Equal(t, "first", receiveText(t, txConsumer))
+	assert.Nil(t, txContext.Commit())
+
+	assert.Equal(t, "second", receiveText(t, consumer))
+	rcvMsg, rcvErr = consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+}
+
+// testIndependentTransactions checks that the transactions of two contexts are
+// committed and rolled back independently of each other, and that Commit and
+// Rollback have no effect on a context that is not transacted.
+func testIndependentTransactions(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	txContext1 := createTransactedContext(t, cf)
+	defer txContext1.Close()
+
+	txContext2 := createTransactedContext(t, cf)
+	defer txContext2.Close()
+
+	assert.Nil(t, txContext1.CreateProducer().SendString(queue, "from 1"))
+	assert.Nil(t, txContext2.CreateProducer().SendString(queue, "from 2"))
+
+	assert.Nil(t, txContext2.Commit())
+	assert.Nil(t, txContext1.Rollback())
+
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	assert.Equal(t, "from 2", receiveText(t, consumer))
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+	// A message sent by a context that is not transacted is available at once,
+	// and is not affected by a rollback.
+	assert.Nil(t, context.CreateProducer().SendString(queue, "auto"))
+	assert.Nil(t, context.Rollback())
+	assert.Nil(t, context.Commit())
+	assert.Equal(t, "auto", receiveText(t, consumer))
+
+}
+
+// testCloseRollsBack checks that closing a transacted context rolls back the
+// transaction that was in progress.
+func testCloseRollsBack(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	assert.Nil(t, context.CreateProducer().SendString(queue, "received"))
+
+	txContext := createTransactedContext(t, cf)
+	txConsumer := createConsumer(t, txContext, queue)
+	assert.Equal(t, "received", receiveText(t, txConsumer))
+	assert.Nil(t, txContext.CreateProducer().SendString(queue, "sent"))
+
+	txContext.Close()
+
+	// The message that was received is back on the queue, and the one that was
+	// sent is not.
+	consumer := createConsumer(t, context, queue)
+	defer consumer.Close()
+
+	assert.Equal(t, "received", receiveText(t, consumer))
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+}
+
+// testCascadeClose checks that closing a context closes the consumers that were
+// created from it, and that a closed context or consumer returns an
+// IllegalStateException when it is used.
+func testCascadeClose(t *testing.T, cf jms20subset.ConnectionFactory, context jms20subset.JMSContext, queue jms20subset.Queue) {
+
+	closeContext, ctxErr := context.CreateContext(jms20subset.JMSContextAUTOACKNOWLEDGE)
+	if !assert.Nil(t, ctxErr) {
+		return
+	}
+
+	consumer1 := createConsumer(t, closeContext, queue)
+	consumer2 := createConsumer(t, closeContext, queue)
+
+	browser, brErr := closeContext.CreateBrowser(queue)
+	if !assert.Nil(t, brErr) {
+		return
+	}
+
+	consumer2.Close()
+	consumer2.Close() // Has no further effect
+
+	_, rcvErr := consumer1.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+
+	_, rcvErr = consumer2.ReceiveNoWait()
+	checkIllegalState(t, rcvErr, "MQJMS_E_CONSUMER_CLOSED")
+
+	closeContext.Close()
+	closeContext.Close() // Has no further effect
+
+	_, rcvErr = consumer1.ReceiveNoWait()
+	checkIllegalState(t, rcvErr, "MQJMS_E_CONTEXT_CLOSED")
+
+	_, rcvErr = consumer2.ReceiveNoWait()
+	checkIllegalState(t, rcvErr, "MQJMS_E_CONTEXT_CLOSED")
+
+	iter, iterErr := browser.GetEnumeration()
+	if assert.Nil(t, iterErr) {
+		_, brErr = iter.GetNext()
+		checkIllegalState(t, brErr, "MQJMS_E_CONTEXT_CLOSED")
+	}
+
+	sendErr := closeContext.CreateProducer().SendString(queue, "NotSent")
+	checkIllegalState(t, sendErr, "MQJMS_E_CONTEXT_CLOSED")
+
+	checkIllegalState(t, closeContext.Commit(), "MQJMS_E_CONTEXT_CLOSED")
+
+	consumer3, conErr := closeContext.CreateConsumer(queue)
+	assert.Nil(t, consumer3)
+	checkIllegalState(t, conErr, "MQJMS_E_CONTEXT_CLOSED")
+
+	browser2, brErr := closeContext.CreateBrowser(queue)
+	assert.Nil(t, browser2)
+	checkIllegalState(t, brErr, "MQJMS_E_CONTEXT_CLOSED")
+
+	// Closing one context has no effect on another.
+	_, rcvErr = createConsumer(t, context, queue).ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+
+}
+
+// checkIllegalState checks that the error is an IllegalStateException with the
+// specified reason.
+func checkIllegalState(t *testing.T, err jms20subset.JMSException, reason string) {
+
+	if assert.NotNil(t, err) {
+		assert.Equal(t, reason, err.GetReason())
+		assert.Equal(t, "IllegalState", err.GetErrorCode())
+		assert.ErrorIs(t, err, jms20subset.IllegalStateException{})
+	}
+}
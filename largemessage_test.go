@@ -295,6 +295,51 @@ func TestLargeReceiveBytesBodyBytesMessage(t *testing.T) {
 
 }
 
+/*
+ * Test the send/receive of a message that is larger than the maximum message
+ * length of the queue (4MB on the developer queue manager), by having the library
+ * split it into segments and reassemble them when it is received.
+ */
+func TestSegmentedLargeMessage(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Messages are sent as segments of up to 1MB, and only a single segment at
+	// a time needs to fit in the receive buffer.
+	cf.SegmentSize = 1024 * 1024
+	cf.ReceiveBufferSize = cf.SegmentSize
+
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	bytes10MB := make([]byte, 10*1024*1024)
+	for i := range bytes10MB {
+		bytes10MB[i] = byte(i % 251)
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	errSend := context.CreateProducer().SetTimeToLive(60000).SendBytes(queue, bytes10MB)
+	assert.Nil(t, errSend)
+
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	rcvBytes, errRcv := consumer.ReceiveBytesBodyNoWait()
+	assert.Nil(t, errRcv)
+	if assert.NotNil(t, rcvBytes) {
+		assert.Equal(t, bytes10MB, *rcvBytes)
+	}
+
+}
+
 func getStringOver32kb() string {
 
 	// Build a text string which is over 32KB (in a not very efficient way!)
@@ -0,0 +1,175 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"bytes"
+	"log"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+// recordingLogger is a Logger that keeps the messages written to it so that
+// the test can check them.
+type recordingLogger struct {
+	lock    sync.Mutex
+	entries []loggedEntry
+}
+
+type loggedEntry struct {
+	level  mqjms.LogLevel
+	msg    string
+	fields map[string]interface{}
+}
+
+func (logger *recordingLogger) Log(level mqjms.LogLevel, msg string, fields ...mqjms.LogField) {
+	logger.lock.Lock()
+	defer logger.lock.Unlock()
+
+	entry := loggedEntry{level: level, msg: msg, fields: make(map[string]interface{})}
+	for _, field := range fields {
+		entry.fields[field.Key] = field.Value
+	}
+	logger.entries = append(logger.entries, entry)
+}
+
+// findVerb returns the trace entries for the specified MQI call.
+func (logger *recordingLogger) findVerb(verb string) []loggedEntry {
+	logger.lock.Lock()
+	defer logger.lock.Unlock()
+
+	var found []loggedEntry
+	for _, entry := range logger.entries {
+		if entry.fields[mqjms.LogFieldVerb] == verb {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+/*
+ * Test that the calls to the queue manager are traced to the Logger that is
+ * set on the ConnectionFactory, and that warnings are sent there too.
+ */
+func TestLoggerAndMQITrace(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	logger := &recordingLogger{}
+	cf.Logger = logger
+	cf.TraceMQI = true
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	connEntries := logger.findVerb("MQCONNX")
+	assert.Equal(t, 1, len(connEntries))
+	assert.Equal(t, mqjms.LogLevelTrace, connEntries[0].level)
+	assert.Equal(t, cf.QMName, connEntries[0].fields[mqjms.LogFieldQueueManager])
+	assert.Equal(t, int32(0), connEntries[0].fields[mqjms.LogFieldMQRC])
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	errSend := context.CreateProducer().SendString(queue, "TracedMsg")
+	assert.Nil(t, errSend)
+
+	putEntries := logger.findVerb("MQPUT1")
+	assert.Equal(t, 1, len(putEntries))
+	assert.Equal(t, "DEV.QUEUE.1", putEntries[0].fields[mqjms.LogFieldQueue])
+
+	rcvBody, errRcv := consumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, errRcv)
+	assert.Equal(t, "TracedMsg", *rcvBody)
+
+	// The second MQGET finds no message, which is traced with its reason code.
+	rcvBody, errRcv = consumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, errRcv)
+	assert.Nil(t, rcvBody)
+
+	getEntries := logger.findVerb("MQGET")
+	assert.Equal(t, 2, len(getEntries))
+	assert.Equal(t, int32(0), getEntries[0].fields[mqjms.LogFieldMQRC])
+	assert.Equal(t, int32(2033), getEntries[1].fields[mqjms.LogFieldMQRC])
+
+	// An invalid setting is written to the logger rather than the console.
+	context.CreateProducer().SetPriority(-1)
+
+	logger.lock.Lock()
+	lastEntry := logger.entries[len(logger.entries)-1]
+	logger.lock.Unlock()
+
+	assert.Equal(t, mqjms.LogLevelWarn, lastEntry.level)
+	assert.Equal(t, "Invalid Priority specified", lastEntry.msg)
+	assert.Equal(t, -1, lastEntry.fields[mqjms.LogFieldValue])
+
+}
+
+// otherDestination is a Destination that is not provided by the mqjms package.
+type otherDestination struct {
+	jms20subset.Queue
+}
+
+/*
+ * Test that unexpected types of destination are reported as an error, rather
+ * than ending the process.
+ */
+func TestUnexpectedDestinationType(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	msg := context.CreateTextMessage()
+	errReplyTo := msg.SetJMSReplyTo(otherDestination{})
+	assert.NotNil(t, errReplyTo)
+	assert.Equal(t, "UnexpectedDestinationType", errReplyTo.GetErrorCode())
+
+}
+
+/*
+ * Test the format of the messages written by the standard logger.
+ */
+func TestStandardLogger(t *testing.T) {
+
+	var buf bytes.Buffer
+	logger := mqjms.NewStandardLogger(log.New(&buf, "", 0), mqjms.LogLevelInfo)
+
+	// Messages below the minimum level are discarded.
+	logger.Log(mqjms.LogLevelDebug, "Not written")
+	assert.Equal(t, "", buf.String())
+
+	logger.Log(mqjms.LogLevelWarn, "Something happened",
+		mqjms.LogField{Key: mqjms.LogFieldQueue, Value: "DEV.QUEUE.1"},
+		mqjms.LogField{Key: mqjms.LogFieldMQRC, Value: 2085})
+	assert.Equal(t, "WARN Something happened queue=DEV.QUEUE.1 mqrc=2085\n", buf.String())
+
+}
@@ -0,0 +1,66 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package memjms provides an in-memory implementation of the JMS style Golang
+// interfaces, so that applications can be tested without a queue manager.
+package memjms
+
+import (
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// BrowserImpl represents the JMS QueueBrowser object that allows applications
+// to peek at messages on a queue without destructively consuming them.
+type BrowserImpl struct {
+	cursor       *storedMessage // Last message that was browsed, or nil to start at the beginning
+	ConsumerImpl                // Browser is a specialized form of consumer
+}
+
+// GetEnumeration returns an iterator for browsing the current
+// queue messages in the order they would be received.
+//
+// In this implementation there is exactly one Enumeration per
+// QueueBrowser. If an application wants to browse two independent
+// copies of the messages it must create two QueueBrowsers.
+func (browser *BrowserImpl) GetEnumeration() (jms20subset.MessageIterator, jms20subset.JMSException) {
+
+	// A browser is just an alternative view of a Consumer that
+	// presents slightly different functions + behaviour.
+	return browser, nil
+
+}
+
+// GetNext returns the next Message that is available
+// or else nil if no messages are available.
+//
+// In the same way as for IBM MQ, the browser moves along the queue as it goes,
+// so it returns messages that arrive later in the queue than the last message
+// it returned, but not those that arrive ahead of it.
+func (browser *BrowserImpl) GetNext() (jms20subset.Message, jms20subset.JMSException) {
+
+	qm := browser.ctx.qm
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	if browser.ctx.state.closed {
+		return nil, createContextClosedException()
+	}
+
+	if browser.state.closed {
+		return nil, jms20subset.CreateIllegalStateException(ConsumerImpl_CONSUMER_CLOSED_REASON, ContextImpl_ILLEGAL_STATE_CODE, nil)
+	}
+
+	stored := qm.find(browser.queueName, browser.selector, browser.cursor)
+	if stored == nil {
+		return nil, nil
+	}
+
+	browser.cursor = stored
+
+	return stored.msg.createJMSMessage(nil)
+}
@@ -0,0 +1,49 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package memjms provides an in-memory implementation of the JMS style Golang
+// interfaces, so that applications can be tested without a queue manager.
+package memjms
+
+// BytesMessageImpl is the in-memory representation of a message that carries
+// a slice of bytes.
+type BytesMessageImpl struct {
+	bodyBytes   *[]byte
+	MessageImpl // embed the "parent" message object that defines the basic behaviour
+}
+
+// ReadBytes returns the slice of bytes that is contained in this BytesMessage.
+func (msg *BytesMessageImpl) ReadBytes() *[]byte {
+
+	if msg.bodyBytes == nil {
+		return &[]byte{}
+	}
+	return msg.bodyBytes
+
+}
+
+// WriteBytes stores the supplied slice of bytes so that it can be transmitted as part
+// of this BytesMessage.
+func (msg *BytesMessageImpl) WriteBytes(bytes []byte) {
+
+	msg.bodyBytes = &bytes
+
+}
+
+// GetBodyLength returns the length of the bytes that are stored in this message
+func (msg *BytesMessageImpl) GetBodyLength() int {
+
+	length := 0
+
+	if msg.bodyBytes != nil {
+		length = len(*msg.bodyBytes)
+	}
+
+	return length
+
+}
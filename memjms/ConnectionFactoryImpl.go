@@ -0,0 +1,63 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package memjms provides an in-memory implementation of the JMS style Golang
+// interfaces, so that applications can be tested without a queue manager.
+package memjms
+
+import (
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// ConnectionFactoryImpl creates JMSContexts that send and receive messages
+// using the queues of an in-memory QueueManager.
+//
+// The fields are defined as Public so that the struct can be initialised
+// programmatically, for example so that several factories share a QueueManager.
+type ConnectionFactoryImpl struct {
+	QueueManager *QueueManager
+}
+
+// NewConnectionFactory creates a ConnectionFactory with a new, empty
+// QueueManager.
+func NewConnectionFactory() ConnectionFactoryImpl {
+
+	return ConnectionFactoryImpl{
+		QueueManager: NewQueueManager(QueueManager_DEFAULT_NAME),
+	}
+}
+
+// CreateContext creates a JMSContext that uses the QueueManager of this factory,
+// with the default session mode of JMSContextAUTOACKNOWLEDGE.
+//
+// Connection options are specific to each provider and there are none for the
+// in-memory provider, so any that are supplied are ignored. This allows an
+// application to pass the same options that it uses with IBM MQ.
+func (cf ConnectionFactoryImpl) CreateContext(opts ...jms20subset.ConnectionOption) (jms20subset.JMSContext, jms20subset.JMSException) {
+	return cf.CreateContextWithSessionMode(jms20subset.JMSContextAUTOACKNOWLEDGE, opts...)
+}
+
+// CreateContextWithSessionMode creates a JMSContext that uses the QueueManager of
+// this factory, with the specified session mode.
+func (cf ConnectionFactoryImpl) CreateContextWithSessionMode(sessionMode int, opts ...jms20subset.ConnectionOption) (jms20subset.JMSContext, jms20subset.JMSException) {
+
+	if cf.QueueManager == nil {
+		return nil, jms20subset.CreateJMSException(ConnectionFactoryImpl_NO_QUEUE_MANAGER_REASON,
+			ConnectionFactoryImpl_NO_QUEUE_MANAGER_CODE, nil)
+	}
+
+	return newContext(cf.QueueManager, sessionMode, &transaction{}), nil
+}
+
+// ConnectionFactoryImpl_NO_QUEUE_MANAGER_REASON is the reason used in the JMSException
+// that is returned when a ConnectionFactoryImpl does not have a QueueManager.
+const ConnectionFactoryImpl_NO_QUEUE_MANAGER_REASON string = "MQRC_Q_MGR_NAME_ERROR"
+
+// ConnectionFactoryImpl_NO_QUEUE_MANAGER_CODE is the error code used in the JMSException
+// that is returned when a ConnectionFactoryImpl does not have a QueueManager.
+const ConnectionFactoryImpl_NO_QUEUE_MANAGER_CODE string = "2058"
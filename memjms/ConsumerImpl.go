@@ -0,0 +1,471 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package memjms provides an in-memory implementation of the JMS style Golang
+// interfaces, so that applications can be tested without a queue manager.
+package memjms
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// ConsumerImpl defines a struct that contains the necessary objects for
+// receiving messages from an in-memory queue.
+type ConsumerImpl struct {
+	ctx       ContextImpl
+	queueName string
+	selector  selector
+	state     *consumerState // Shared by all copies of this ConsumerImpl
+}
+
+// consumerState holds the lifecycle state of a ConsumerImpl or BrowserImpl, which
+// is shared by every copy of the value. The lock of the QueueManager must be held
+// to use it.
+type consumerState struct {
+	closed bool
+}
+
+// selector is the parsed form of a message selector, which in the same way as for
+// the IBM MQ provider can only select on the correlation ID or the message ID.
+type selector struct {
+	fieldName string // Empty if every message is selected
+	value     [24]byte
+}
+
+// ConsumerImpl_CONSUMER_CLOSED_REASON is the reason used in the JMSException that is
+// returned when a JMSConsumer or QueueBrowser is used after it has been closed.
+const ConsumerImpl_CONSUMER_CLOSED_REASON string = "MQJMS_E_CONSUMER_CLOSED"
+
+// ConsumerImpl_TRUNCATED_REASON is the reason used in the JMSException that is
+// returned when a message is too large for the buffer supplied to ReceiveInto.
+const ConsumerImpl_TRUNCATED_REASON string = "MQRC_TRUNCATED_MSG_FAILED"
+
+// ConsumerImpl_TRUNCATED_CODE is the error code used in the JMSException that is
+// returned when a message is too large for the buffer supplied to ReceiveInto.
+const ConsumerImpl_TRUNCATED_CODE string = "2080"
+
+// ConsumerImpl_STREAM_CLOSED_REASON is the reason used in the JMSException that is
+// returned when the reader returned by ReceiveStream is read after it has been closed.
+const ConsumerImpl_STREAM_CLOSED_REASON string = "MQJMS_E_STREAM_CLOSED"
+
+// ReceiveNoWait receives a message from the Destination, or immediately returns
+// a nil Message if there is no available message to be received.
+func (consumer ConsumerImpl) ReceiveNoWait() (jms20subset.Message, jms20subset.JMSException) {
+
+	msg, _, jmsErr := consumer.receiveInternal(nil, consumer.ctx.state.tx)
+	return msg, jmsErr
+
+}
+
+// Receive with waitMillis returns a message if one is available, or otherwise
+// waits for up to the specified number of milliseconds for one to become
+// available. A value of zero or less indicates to wait indefinitely.
+func (consumer ConsumerImpl) Receive(waitMillis int32) (jms20subset.Message, jms20subset.JMSException) {
+	return consumer.receiveWithWait(context.Background(), waitMillis, nil, consumer.ctx.state.tx)
+}
+
+// ReceiveContext returns a message if one is available, or otherwise waits
+// until one becomes available or the supplied Go context is cancelled or
+// reaches its deadline.
+func (consumer ConsumerImpl) ReceiveContext(ctx context.Context) (jms20subset.Message, jms20subset.JMSException) {
+	return consumer.receiveWithWait(ctx, 0, nil, consumer.ctx.state.tx)
+}
+
+// ReceiveInto receives a message in the same way as Receive, but uses the
+// supplied slice as the buffer into which the message is received instead of
+// allocating one.
+//
+// The body of a BytesMessage that is received by this method refers directly to
+// the supplied buffer, so the buffer must not be reused until the application
+// has finished with the message.
+//
+// If the message is larger than the buffer then an error is returned with the
+// reason MQRC_TRUNCATED_MSG_FAILED and the message remains on the queue.
+func (consumer ConsumerImpl) ReceiveInto(buffer []byte, waitMillis int32) (jms20subset.Message, jms20subset.JMSException) {
+
+	if buffer == nil {
+		buffer = []byte{}
+	}
+
+	return consumer.receiveWithWait(context.Background(), waitMillis, buffer, consumer.ctx.state.tx)
+}
+
+// receiveWithWait waits for up to waitMillis milliseconds for a message to
+// become available, or indefinitely if waitMillis is zero or less, returning
+// early with an error if the Go context is cancelled or reaches its deadline.
+// The message is received under the transaction if one is supplied.
+func (consumer ConsumerImpl) receiveWithWait(ctx context.Context, waitMillis int32, buffer []byte, tx *transaction) (jms20subset.Message, jms20subset.JMSException) {
+
+	var timeout <-chan time.Time
+	if waitMillis > 0 {
+		timer := time.NewTimer(time.Duration(waitMillis) * time.Millisecond)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+
+		if jmsErr := checkContextDone(ctx); jmsErr != nil {
+			return nil, jmsErr
+		}
+
+		msg, available, jmsErr := consumer.receiveInternal(buffer, tx)
+		if msg != nil || jmsErr != nil {
+			return msg, jmsErr
+		}
+
+		// Wait until a message might have become available, then look again.
+		select {
+		case <-available:
+		case <-timeout:
+			return nil, nil
+		case <-ctx.Done():
+			return nil, createContextDoneException(ctx.Err())
+		}
+	}
+
+}
+
+// ReceiveChannel starts a goroutine that repeatedly receives messages from this
+// consumer and delivers them on the returned message channel, until the Go
+// context is cancelled or a receive call fails.
+//
+// Messages are only received while there is space in the channel buffer, which
+// provides backpressure towards the queue. Note that a message that has already
+// been received when the context is cancelled is not delivered on the channel,
+// so applications that must not lose such a message should use a transacted
+// JMSContext so that it is rolled back.
+func (consumer ConsumerImpl) ReceiveChannel(ctx context.Context, bufferSize int) (<-chan jms20subset.Message, <-chan jms20subset.JMSException) {
+
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+
+	msgChan := make(chan jms20subset.Message, bufferSize)
+
+	// Only one error is ever delivered, so buffer it to allow the goroutine
+	// to exit even if the application is not currently reading errors.
+	errChan := make(chan jms20subset.JMSException, 1)
+
+	go func() {
+
+		defer close(msgChan)
+		defer close(errChan)
+
+		for {
+
+			msg, jmsErr := consumer.ReceiveContext(ctx)
+
+			if jmsErr != nil {
+
+				// Cancellation of the context is the normal way to stop the loop,
+				// so only report errors that have some other cause.
+				if ctx.Err() == nil {
+					errChan <- jmsErr
+				}
+				return
+			}
+
+			// Wait for space in the channel, which is the point at which the
+			// backpressure applies.
+			select {
+			case msgChan <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+	}()
+
+	return msgChan, errChan
+
+}
+
+// receiveInternal receives the first available message that matches the selector,
+// into the buffer if one is supplied. If there is no such message then it returns
+// a channel that is closed when one might have become available. The message is
+// received under the transaction if one is supplied.
+func (consumer ConsumerImpl) receiveInternal(buffer []byte, tx *transaction) (jms20subset.Message, <-chan struct{}, jms20subset.JMSException) {
+
+	qm := consumer.ctx.qm
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	if consumer.ctx.state.closed {
+		return nil, nil, createContextClosedException()
+	}
+
+	if consumer.state.closed {
+		return nil, nil, jms20subset.CreateIllegalStateException(ConsumerImpl_CONSUMER_CLOSED_REASON, ContextImpl_ILLEGAL_STATE_CODE, nil)
+	}
+
+	// Look at the message without taking it first, so that it can be left on the
+	// queue if it does not fit in the buffer.
+	stored := qm.find(consumer.queueName, consumer.selector, nil)
+	if stored == nil {
+		return nil, qm.available, nil
+	}
+
+	msg, jmsErr := stored.msg.createJMSMessage(buffer)
+	if jmsErr != nil {
+		return nil, nil, jmsErr
+	}
+
+	qm.take(stored, tx)
+
+	return msg, nil, nil
+}
+
+// createJMSMessage creates the message that is given to the application when
+// this message is received, which shares nothing with the copy on the queue.
+// If a buffer is supplied then the body is received into it.
+func (stored *message) createJMSMessage(buffer []byte) (jms20subset.Message, jms20subset.JMSException) {
+
+	bodyLen := len(stored.bodyBytes)
+	if stored.bodyStr != nil {
+		bodyLen = len(*stored.bodyStr)
+	}
+
+	if buffer != nil && bodyLen > len(buffer) {
+		return nil, jms20subset.CreateJMSException(ConsumerImpl_TRUNCATED_REASON, ConsumerImpl_TRUNCATED_CODE, nil)
+	}
+
+	msgImpl := stored.copyMessageImpl()
+
+	if stored.isText {
+
+		// An empty body is received as a nil string, as with IBM MQ.
+		var bodyStr *string
+		if stored.bodyStr != nil {
+			strContent := *stored.bodyStr
+			bodyStr = &strContent
+		}
+
+		return &TextMessageImpl{
+			bodyStr:     bodyStr,
+			MessageImpl: msgImpl,
+		}, nil
+	}
+
+	var bodyBytes []byte
+	if buffer != nil && bodyLen > 0 {
+		bodyBytes = buffer[0:bodyLen]
+	} else {
+		bodyBytes = make([]byte, bodyLen)
+	}
+	copy(bodyBytes, stored.bodyBytes)
+
+	return &BytesMessageImpl{
+		bodyBytes:   &bodyBytes,
+		MessageImpl: msgImpl,
+	}, nil
+}
+
+// ReceiveStringBodyNoWait receives a message from the Destination and returns
+// its body as a string.
+//
+// If no message is immediately available to be returned then a nil is returned.
+func (consumer ConsumerImpl) ReceiveStringBodyNoWait() (*string, jms20subset.JMSException) {
+
+	msg, jmsErr := consumer.ReceiveNoWait()
+	return getStringBody(msg, jmsErr)
+
+}
+
+// ReceiveStringBody receives a message from the Destination and returns its
+// body as a string.
+//
+// If no message is available the method blocks up to the specified number
+// of milliseconds for one to become available.
+func (consumer ConsumerImpl) ReceiveStringBody(waitMillis int32) (*string, jms20subset.JMSException) {
+
+	msg, jmsErr := consumer.Receive(waitMillis)
+	return getStringBody(msg, jmsErr)
+
+}
+
+// ReceiveBytesBodyNoWait receives a message from the Destination and returns
+// its body as a slice of bytes.
+//
+// If no message is immediately available to be returned then a nil is returned.
+func (consumer ConsumerImpl) ReceiveBytesBodyNoWait() (*[]byte, jms20subset.JMSException) {
+
+	msg, jmsErr := consumer.ReceiveNoWait()
+	return getBytesBody(msg, jmsErr)
+
+}
+
+// ReceiveBytesBody receives a message from the Destination and returns its
+// body as a slice of bytes.
+//
+// If no message is available the method blocks up to the specified number
+// of milliseconds for one to become available.
+func (consumer ConsumerImpl) ReceiveBytesBody(waitMillis int32) (*[]byte, jms20subset.JMSException) {
+
+	msg, jmsErr := consumer.Receive(waitMillis)
+	return getBytesBody(msg, jmsErr)
+
+}
+
+// ReceiveStream receives data that was sent using SendStream, or any other
+// message, waiting for up to waitMillis milliseconds for it to become available,
+// and returns a reader for the body of the message. A nil reader is returned if
+// no message is available in that time.
+//
+// If the JMSContext is transacted then the message is received under the
+// transaction. Otherwise it is received under a transaction of its own, which is
+// committed once the whole body has been read, when the reader returns io.EOF or
+// is closed, and rolled back if the reader is closed before then, in the same
+// way as the IBM MQ provider.
+func (consumer ConsumerImpl) ReceiveStream(waitMillis int32) (io.ReadCloser, jms20subset.JMSException) {
+
+	tx := consumer.ctx.state.tx
+	if tx == nil {
+		tx = &transaction{}
+	}
+
+	msg, jmsErr := consumer.receiveWithWait(context.Background(), waitMillis, nil, tx)
+	if msg == nil || jmsErr != nil {
+		return nil, jmsErr
+	}
+
+	reader := &streamReader{
+		qm: consumer.ctx.qm,
+	}
+
+	// Only a transaction of the stream's own is completed by the reader.
+	if tx != consumer.ctx.state.tx {
+		reader.tx = tx
+	}
+
+	switch typedMsg := msg.(type) {
+	case jms20subset.TextMessage:
+		if typedMsg.GetText() != nil {
+			reader.body = []byte(*typedMsg.GetText())
+		}
+
+	case jms20subset.BytesMessage:
+		reader.body = *typedMsg.ReadBytes()
+	}
+
+	return reader, nil
+}
+
+// getStringBody returns the body of a received message, which must be a TextMessage.
+func getStringBody(msg jms20subset.Message, jmsErr jms20subset.JMSException) (*string, jms20subset.JMSException) {
+
+	if jmsErr != nil || msg == nil {
+		return nil, jmsErr
+	}
+
+	textMsg, isText := msg.(jms20subset.TextMessage)
+	if !isText {
+		return nil, jms20subset.CreateMessageFormatException("MQJMS_DIR_MIN_NOTTEXT", "MQJMS6068", nil)
+	}
+
+	return textMsg.GetText(), nil
+}
+
+// getBytesBody returns the body of a received message, which must be a BytesMessage.
+func getBytesBody(msg jms20subset.Message, jmsErr jms20subset.JMSException) (*[]byte, jms20subset.JMSException) {
+
+	if jmsErr != nil || msg == nil {
+		return nil, jmsErr
+	}
+
+	bytesMsg, isBytes := msg.(jms20subset.BytesMessage)
+	if !isBytes {
+		return nil, jms20subset.CreateMessageFormatException("MQJMS_DIR_MIN_NOTBYTES", "MQJMS6068", nil)
+	}
+
+	return bytesMsg.ReadBytes(), nil
+}
+
+// parseSelector converts the JMS style selector string into the fields that are
+// matched against messages. The same selectors are supported as by the IBM MQ
+// provider, which are equality on JMSCorrelationID or JMSMessageID.
+func parseSelector(selectorStr string) (selector, error) {
+
+	if selectorStr == "" {
+		// No selector is provided, so every message matches.
+		return selector{}, nil
+	}
+
+	// looking for something like
+	//   "JMSCorrelationID = '01020304050607'"
+	//   "JMSMessageID = '4d454d204d454d514d2020202020202000000000000001'"
+	clauseSplits := strings.Split(selectorStr, "=")
+
+	if len(clauseSplits) != 2 {
+		return selector{}, errors.New("Unable to parse selector " + selectorStr)
+	}
+
+	selectorFieldName := strings.TrimSpace(clauseSplits[0])
+
+	if selectorFieldName != "JMSCorrelationID" &&
+		selectorFieldName != "JMSMessageID" {
+		return selector{}, errors.New("Only selectors on JMSCorrelationID and JMSMessageID are currently supported")
+	}
+
+	// Check for a quote delimited value for the selector clause.
+	value := strings.TrimSpace(clauseSplits[1])
+
+	if !strings.HasPrefix(value, "'") || !strings.HasSuffix(value, "'") {
+		return selector{}, errors.New("Unable to parse quoted string from " + selectorStr)
+	}
+
+	selectorValue := strings.Split(value, "'")[1]
+
+	// For CorrelID and MsgID there is typically an "ID:" prefix on the
+	// selector value that needs to be trimmed off before we convert it.
+	selectorValue = strings.TrimPrefix(selectorValue, "ID:")
+
+	if selectorValue == "" {
+		return selector{}, errors.New("No value was found for selector string")
+	}
+
+	return selector{
+		fieldName: selectorFieldName,
+		value:     convertStringToID(selectorValue),
+	}, nil
+}
+
+// matches returns true if the message is selected by this selector.
+func (sel selector) matches(msg *message) bool {
+
+	switch sel.fieldName {
+	case "JMSCorrelationID":
+		return msg.correlID == sel.value
+	case "JMSMessageID":
+		return msg.messageID == sel.value
+	}
+
+	return true
+}
+
+// Close closes the JMSConsumer. Closing a consumer that is already closed has
+// no effect.
+func (consumer ConsumerImpl) Close() {
+
+	qm := consumer.ctx.qm
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	consumer.state.closed = true
+	delete(consumer.ctx.state.consumers, consumer.state)
+
+	// Wake up any receives that are waiting, so that they see that the consumer
+	// has been closed.
+	qm.notifyAvailable()
+}
@@ -0,0 +1,326 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package memjms provides an in-memory implementation of the JMS style Golang
+// interfaces, so that applications can be tested without a queue manager.
+package memjms
+
+import (
+	"context"
+
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// ContextImpl encapsulates the objects necessary to use the queues of an
+// in-memory QueueManager.
+type ContextImpl struct {
+	qm          *QueueManager
+	sessionMode int
+	connTx      *transaction  // Unit of work shared with the contexts created using CreateContext
+	state       *contextState // Shared by all copies of this ContextImpl
+}
+
+// contextState holds the lifecycle and transaction state of a ContextImpl, which
+// is shared by every copy of the value. The lock of the QueueManager must be held
+// to use it.
+type contextState struct {
+	closed    bool
+	tx        *transaction // Current unit of work, or nil if the context is not transacted
+	consumers map[*consumerState]bool
+}
+
+// newContext creates a JMSContext for the QueueManager using the specified
+// session mode. If it is transacted then its work is done in the specified
+// unit of work, which is shared by every context of the same connection.
+func newContext(qm *QueueManager, sessionMode int, connTx *transaction) ContextImpl {
+
+	state := &contextState{
+		consumers: make(map[*consumerState]bool),
+	}
+
+	if sessionMode == jms20subset.JMSContextSESSIONTRANSACTED {
+		state.tx = connTx
+	}
+
+	return ContextImpl{
+		qm:          qm,
+		sessionMode: sessionMode,
+		connTx:      connTx,
+		state:       state,
+	}
+}
+
+// CreateContext creates a new JMSContext that uses the same QueueManager as this
+// JMSContext, with the specified session mode.
+//
+// The new JMSContext shares the transaction scope of this JMSContext, in the same
+// way as the contexts of IBM MQ that share a connection handle, so that Commit
+// and Rollback on a transacted JMSContext apply to the messages sent or received
+// under the transaction of any of them.
+func (ctx ContextImpl) CreateContext(sessionMode int) (jms20subset.JMSContext, jms20subset.JMSException) {
+
+	ctx.qm.lock.Lock()
+	closed := ctx.state.closed
+	ctx.qm.lock.Unlock()
+
+	if closed {
+		return nil, createContextClosedException()
+	}
+
+	return newContext(ctx.qm, sessionMode, ctx.connTx), nil
+}
+
+// CreateQueue creates an object representing the named queue. The queue is
+// defined in the QueueManager when it is first used.
+func (ctx ContextImpl) CreateQueue(queueName string) jms20subset.Queue {
+
+	queue := QueueImpl{
+		queueName:       queueName,
+		putAsyncAllowed: jms20subset.Destination_PUT_ASYNC_ALLOWED_AS_DEST,
+	}
+
+	return queue
+}
+
+// CreateProducer creates a JMSProducer object that allows messages to be sent
+// to queues.
+func (ctx ContextImpl) CreateProducer() jms20subset.JMSProducer {
+
+	producer := ProducerImpl{
+		ctx:          ctx,
+		deliveryMode: jms20subset.DeliveryMode_PERSISTENT,
+		priority:     jms20subset.Priority_DEFAULT,
+	}
+
+	return &producer
+}
+
+// CreateConsumer creates a consumer object that allows an application to
+// receive messages from the specified Destination.
+func (ctx ContextImpl) CreateConsumer(dest jms20subset.Destination) (jms20subset.JMSConsumer, jms20subset.JMSException) {
+	return ctx.CreateConsumerWithSelector(dest, "")
+}
+
+// CreateConsumerWithSelector creates a consumer object that allows an application to
+// receive messages that match the specified selector from the given Destination.
+func (ctx ContextImpl) CreateConsumerWithSelector(dest jms20subset.Destination, selector string) (jms20subset.JMSConsumer, jms20subset.JMSException) {
+
+	sel, selectorErr := parseSelector(selector)
+	if selectorErr != nil {
+		return nil, jms20subset.CreateInvalidSelectorException("Invalid selector syntax", "MQJMS0004", selectorErr)
+	}
+
+	state, jmsErr := ctx.openConsumer(dest)
+	if jmsErr != nil {
+		return nil, jmsErr
+	}
+
+	consumer := ConsumerImpl{
+		ctx:       ctx,
+		queueName: dest.GetDestinationName(),
+		selector:  sel,
+		state:     state,
+	}
+
+	return consumer, nil
+}
+
+// CreateBrowser creates a consumer for the specified Destination so that
+// an application can look at messages without removing them.
+func (ctx ContextImpl) CreateBrowser(dest jms20subset.Destination) (jms20subset.QueueBrowser, jms20subset.JMSException) {
+
+	state, jmsErr := ctx.openConsumer(dest)
+	if jmsErr != nil {
+		return nil, jmsErr
+	}
+
+	browser := &BrowserImpl{
+		ConsumerImpl: ConsumerImpl{
+			ctx:       ctx,
+			queueName: dest.GetDestinationName(),
+			state:     state,
+		},
+	}
+
+	return browser, nil
+}
+
+// openConsumer checks that a consumer or browser can be created for the
+// Destination, and records it so that it is closed with the context.
+func (ctx ContextImpl) openConsumer(dest jms20subset.Destination) (*consumerState, jms20subset.JMSException) {
+
+	ctx.qm.lock.Lock()
+	defer ctx.qm.lock.Unlock()
+
+	if ctx.state.closed {
+		return nil, createContextClosedException()
+	}
+
+	if dest == nil || dest.GetDestinationName() == "" {
+		return nil, jms20subset.CreateInvalidDestinationException(ContextImpl_UNKNOWN_OBJECT_NAME_REASON,
+			ContextImpl_UNKNOWN_OBJECT_NAME_CODE, nil)
+	}
+
+	state := &consumerState{}
+	ctx.state.consumers[state] = true
+
+	return state, nil
+}
+
+// CreateTextMessage creates a message object that is used to send a string
+// from one application to another.
+func (ctx ContextImpl) CreateTextMessage() jms20subset.TextMessage {
+	return &TextMessageImpl{
+		MessageImpl: newMessageImpl(),
+	}
+}
+
+// CreateTextMessageWithString creates a message object that is used to send a string
+// from one application to another, with the specified body.
+func (ctx ContextImpl) CreateTextMessageWithString(txt string) jms20subset.TextMessage {
+	return &TextMessageImpl{
+		bodyStr:     &txt,
+		MessageImpl: newMessageImpl(),
+	}
+}
+
+// CreateBytesMessage creates a message object that is used to send a slice
+// of bytes from one application to another.
+func (ctx ContextImpl) CreateBytesMessage() jms20subset.BytesMessage {
+	return &BytesMessageImpl{
+		MessageImpl: newMessageImpl(),
+	}
+}
+
+// CreateBytesMessageWithBytes creates a message object that is used to send a slice
+// of bytes from one application to another, with the specified body.
+func (ctx ContextImpl) CreateBytesMessageWithBytes(bytes []byte) jms20subset.BytesMessage {
+	return &BytesMessageImpl{
+		bodyBytes:   &bytes,
+		MessageImpl: newMessageImpl(),
+	}
+}
+
+// Commit confirms all messages that were sent and received under this transaction.
+// It has no effect if the context is not transacted.
+func (ctx ContextImpl) Commit() jms20subset.JMSException {
+
+	ctx.qm.lock.Lock()
+	defer ctx.qm.lock.Unlock()
+
+	if ctx.state.closed {
+		return createContextClosedException()
+	}
+
+	if ctx.state.tx != nil {
+		ctx.qm.commit(ctx.state.tx)
+	}
+
+	return nil
+}
+
+// Rollback discards all messages that were sent under this transaction, and
+// makes the messages that were received under it available again. It has no
+// effect if the context is not transacted.
+func (ctx ContextImpl) Rollback() jms20subset.JMSException {
+
+	ctx.qm.lock.Lock()
+	defer ctx.qm.lock.Unlock()
+
+	if ctx.state.closed {
+		return createContextClosedException()
+	}
+
+	if ctx.state.tx != nil {
+		ctx.qm.rollback(ctx.state.tx)
+	}
+
+	return nil
+}
+
+// Close rolls back any transaction that is in progress, and closes the consumers
+// and browsers that were created from this context. Any further use of the context
+// or the objects created from it returns an error. Closing a context that is
+// already closed has no effect.
+func (ctx ContextImpl) Close() {
+
+	ctx.qm.lock.Lock()
+	defer ctx.qm.lock.Unlock()
+
+	if ctx.state.closed {
+		return
+	}
+
+	// JMS semantics are to roll back an active transaction on Close.
+	if ctx.state.tx != nil {
+		ctx.qm.rollback(ctx.state.tx)
+	}
+
+	for state := range ctx.state.consumers {
+		state.closed = true
+	}
+	ctx.state.consumers = make(map[*consumerState]bool)
+
+	ctx.state.closed = true
+
+	// Wake up any receives that are waiting, so that they see that the context
+	// has been closed.
+	ctx.qm.notifyAvailable()
+}
+
+// The reasons and error codes in this provider are the same as those of the IBM
+// MQ provider, so that applications can check for them in the same way.
+
+// ContextImpl_CONTEXT_DONE_REASON is the reason used in the JMSException that is
+// returned when a Go context is cancelled or reaches its deadline during a call.
+const ContextImpl_CONTEXT_DONE_REASON string = "MQJMS_E_CONTEXT_DONE"
+
+// ContextImpl_CONTEXT_DONE_CODE is the error code used in the JMSException that is
+// returned when a Go context is cancelled or reaches its deadline during a call.
+const ContextImpl_CONTEXT_DONE_CODE string = "ContextDone"
+
+// createContextDoneException returns the error for a call that ended early
+// because its Go context is done, linking the error from the Go context.
+func createContextDoneException(ctxErr error) jms20subset.JMSException {
+	return jms20subset.CreateJMSException(ContextImpl_CONTEXT_DONE_REASON, ContextImpl_CONTEXT_DONE_CODE, ctxErr)
+}
+
+// ContextImpl_CONTEXT_CLOSED_REASON is the reason used in the JMSException that is
+// returned when a JMSContext, or an object created from it, is used after the
+// JMSContext has been closed.
+const ContextImpl_CONTEXT_CLOSED_REASON string = "MQJMS_E_CONTEXT_CLOSED"
+
+// ContextImpl_ILLEGAL_STATE_CODE is the error code used in the JMSException that is
+// returned when a JMSContext, or an object created from it, is used in a state
+// that does not allow it.
+const ContextImpl_ILLEGAL_STATE_CODE string = "IllegalState"
+
+// createContextClosedException returns the error for an operation on a JMSContext
+// that has been closed.
+func createContextClosedException() jms20subset.JMSException {
+	return jms20subset.CreateIllegalStateException(ContextImpl_CONTEXT_CLOSED_REASON, ContextImpl_ILLEGAL_STATE_CODE, nil)
+}
+
+// ContextImpl_UNKNOWN_OBJECT_NAME_REASON is the reason used in the JMSException that
+// is returned when a Destination does not have a name.
+const ContextImpl_UNKNOWN_OBJECT_NAME_REASON string = "MQRC_UNKNOWN_OBJECT_NAME"
+
+// ContextImpl_UNKNOWN_OBJECT_NAME_CODE is the error code used in the JMSException that
+// is returned when a Destination does not have a name.
+const ContextImpl_UNKNOWN_OBJECT_NAME_CODE string = "2085"
+
+// checkContextDone returns an error if the Go context has been cancelled or
+// has reached its deadline.
+func checkContextDone(ctx context.Context) jms20subset.JMSException {
+
+	if ctx.Err() != nil {
+		return createContextDoneException(ctx.Err())
+	}
+
+	return nil
+}
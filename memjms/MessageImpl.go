@@ -0,0 +1,456 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package memjms provides an in-memory implementation of the JMS style Golang
+// interfaces, so that applications can be tested without a queue manager.
+package memjms
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// The reason and error code for a failure to convert a property value are the
+// same as those of the IBM MQ provider.
+const MessageImpl_PROPERTY_CONVERT_FAILED_REASON string = "MQJMS_E_BAD_TYPE"
+const MessageImpl_PROPERTY_CONVERT_FAILED_CODE string = "1055"
+
+// MessageImpl_RELEASED_REASON is the reason used in the JMSException that is
+// returned when the properties of a message are used after it has been released,
+// which is the same as the IBM MQ provider.
+const MessageImpl_RELEASED_REASON string = "MQRC_HMSG_ERROR"
+
+// MessageImpl_RELEASED_CODE is the error code used in the JMSException that is
+// returned when the properties of a message are used after it has been released.
+const MessageImpl_RELEASED_CODE string = "2460"
+
+// MessageImpl contains the attributes that are common to all types of message.
+type MessageImpl struct {
+	messageID    [24]byte // All zeros until the message has been sent
+	correlID     [24]byte
+	timestamp    int64
+	expiration   int64
+	deliveryMode int
+	priority     int
+	replyTo      string
+	properties   []property // In the order in which they were first set
+	released     bool
+}
+
+// property is a single named message property. The value is a string, int64,
+// float64 or bool, as for the properties of an IBM MQ message.
+type property struct {
+	name  string
+	value interface{}
+}
+
+// newMessageImpl returns the attributes of a message that has not yet been sent.
+func newMessageImpl() MessageImpl {
+
+	return MessageImpl{
+		deliveryMode: jms20subset.DeliveryMode_PERSISTENT,
+		priority:     jms20subset.Priority_DEFAULT,
+	}
+}
+
+// Release discards the properties of this message. There is no resource to give
+// back in the in-memory provider, but the properties cannot be used once the
+// message has been released, in the same way as for the IBM MQ provider.
+// Calling Release more than once has no further effect.
+func (msg *MessageImpl) Release() {
+
+	msg.released = true
+	msg.properties = nil
+
+}
+
+// GetJMSDeliveryMode returns the delivery mode with which this message was sent.
+func (msg *MessageImpl) GetJMSDeliveryMode() int {
+	return msg.deliveryMode
+}
+
+// GetJMSPriority returns the priority with which this message was sent.
+func (msg *MessageImpl) GetJMSPriority() int {
+	return msg.priority
+}
+
+// GetJMSMessageID returns the message ID that was assigned when this message was
+// sent, as a string of hex characters, or an empty string if it has not been sent.
+func (msg *MessageImpl) GetJMSMessageID() string {
+
+	if msg.messageID == [24]byte{} {
+		return ""
+	}
+
+	return hex.EncodeToString(msg.messageID[:])
+}
+
+// SetJMSReplyTo stores the Destination to which replies to this message
+// should be sent.
+func (msg *MessageImpl) SetJMSReplyTo(dest jms20subset.Destination) jms20subset.JMSException {
+
+	switch typedDest := dest.(type) {
+	case QueueImpl:
+		msg.replyTo = typedDest.queueName
+
+	default:
+		// Destinations from other providers are not supported, in the same way
+		// as for the IBM MQ provider.
+		return jms20subset.CreateInvalidDestinationException("UnexpectedDestinationType", "UnexpectedDestinationType", nil)
+	}
+
+	return nil
+}
+
+// GetJMSReplyTo returns the Destination to which replies to this message should
+// be sent, or nil if there is none.
+func (msg *MessageImpl) GetJMSReplyTo() jms20subset.Destination {
+
+	if msg.replyTo == "" {
+		return nil
+	}
+
+	return QueueImpl{
+		queueName:       msg.replyTo,
+		putAsyncAllowed: jms20subset.Destination_PUT_ASYNC_ALLOWED_AS_DEST,
+	}
+}
+
+// SetJMSCorrelationID stores the correlation ID of this message.
+//
+// The correlation ID is held in 24 bytes in the same way as by the IBM MQ provider,
+// so that it behaves identically. A string of hex characters is stored as the bytes
+// that it represents, whereas other text is stored in its hex encoded form, which
+// means that only the first 12 characters of plain text are kept.
+func (msg *MessageImpl) SetJMSCorrelationID(correlID string) jms20subset.JMSException {
+
+	msg.correlID = convertStringToID(correlID)
+	return nil
+
+}
+
+// convertStringToID converts a string which is either plain text or hex encoded
+// bytes into the 24 bytes that are used for a correlation ID or message ID.
+func convertStringToID(strText string) [24]byte {
+
+	// First try to decode the hex string
+	idBytes, err := hex.DecodeString(strText)
+
+	if err != nil {
+		// Failed to decode hex string, so assume it is plain text and hex encode it
+		// into bytes.
+		idBytes = make([]byte, hex.EncodedLen(len(strText)))
+		hex.Encode(idBytes, []byte(strText))
+	}
+
+	var id [24]byte
+	copy(id[:], idBytes)
+
+	return id
+}
+
+// GetJMSCorrelationID returns the correlation ID of this message, in the form
+// that the application originally supplied it if possible.
+func (msg *MessageImpl) GetJMSCorrelationID() string {
+
+	// Trim off any padding zero bytes so that we can try to turn the ID back into
+	// the original string.
+	realLength := len(msg.correlID)
+	for realLength > 0 && msg.correlID[realLength-1] == 0 {
+		realLength--
+	}
+
+	// Attempt to decode the content back into a plain text string, otherwise
+	// encode the bytes themselves.
+	dst := make([]byte, hex.DecodedLen(realLength))
+	n, err := hex.Decode(dst, msg.correlID[0:realLength])
+	if err != nil {
+		return hex.EncodeToString(msg.correlID[0:realLength])
+	}
+
+	return string(dst[:n])
+}
+
+// GetJMSTimestamp returns the time at which the message was sent, in
+// milliseconds since the epoch, or zero if it has not been sent.
+func (msg *MessageImpl) GetJMSTimestamp() int64 {
+	return msg.timestamp
+}
+
+// GetJMSExpiration returns the time at which the message is due to expire, in
+// milliseconds since the epoch, or zero if the message does not expire.
+func (msg *MessageImpl) GetJMSExpiration() int64 {
+	return msg.expiration
+}
+
+// SetStringProperty enables an application to set a string-type message property.
+//
+// value is *string which allows a nil value to be specified, to unset an individual
+// property.
+func (msg *MessageImpl) SetStringProperty(name string, value *string) jms20subset.JMSException {
+
+	if value == nil {
+		return msg.deleteProperty(name)
+	}
+
+	return msg.setProperty(name, *value)
+}
+
+// GetStringProperty returns the string value of a named message property.
+// Returns nil if the named property is not set.
+func (msg *MessageImpl) GetStringProperty(name string) (*string, jms20subset.JMSException) {
+
+	value, retErr := msg.getProperty(name)
+	if value == nil || retErr != nil {
+		return nil, retErr
+	}
+
+	var valueStr string
+
+	switch valueTyped := value.(type) {
+	case string:
+		valueStr = valueTyped
+	case int64:
+		valueStr = strconv.FormatInt(valueTyped, 10)
+	case bool:
+		valueStr = strconv.FormatBool(valueTyped)
+	case float64:
+		valueStr = fmt.Sprintf("%g", valueTyped)
+	}
+
+	return &valueStr, nil
+}
+
+// SetIntProperty enables an application to set a int-type message property.
+func (msg *MessageImpl) SetIntProperty(name string, value int) jms20subset.JMSException {
+	return msg.setProperty(name, int64(value))
+}
+
+// GetIntProperty returns the int value of a named message property.
+// Returns 0 if the named property is not set.
+func (msg *MessageImpl) GetIntProperty(name string) (int, jms20subset.JMSException) {
+
+	value, retErr := msg.getProperty(name)
+	if value == nil || retErr != nil {
+		return 0, retErr
+	}
+
+	var valueRet int
+	var parseErr error
+
+	switch valueTyped := value.(type) {
+	case int64:
+		valueRet = int(valueTyped)
+	case string:
+		valueRet, parseErr = strconv.Atoi(valueTyped)
+	case bool:
+		if valueTyped {
+			valueRet = 1
+		}
+	case float64:
+		s := fmt.Sprintf("%.0f", valueTyped)
+		valueRet, parseErr = strconv.Atoi(s)
+	}
+
+	if parseErr != nil {
+		return 0, jms20subset.CreateMessageFormatException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
+			MessageImpl_PROPERTY_CONVERT_FAILED_CODE, parseErr)
+	}
+
+	return valueRet, nil
+}
+
+// SetDoubleProperty enables an application to set a double-type (float64) message property.
+func (msg *MessageImpl) SetDoubleProperty(name string, value float64) jms20subset.JMSException {
+	return msg.setProperty(name, value)
+}
+
+// GetDoubleProperty returns the double (float64) value of a named message property.
+// Returns 0 if the named property is not set.
+func (msg *MessageImpl) GetDoubleProperty(name string) (float64, jms20subset.JMSException) {
+
+	value, retErr := msg.getProperty(name)
+	if value == nil || retErr != nil {
+		return 0, retErr
+	}
+
+	var valueRet float64
+	var parseErr error
+
+	switch valueTyped := value.(type) {
+	case float64:
+		valueRet = valueTyped
+	case string:
+		valueRet, parseErr = strconv.ParseFloat(valueTyped, 64)
+	case int64:
+		valueRet = float64(valueTyped)
+	case bool:
+		if valueTyped {
+			valueRet = 1
+		}
+	}
+
+	if parseErr != nil {
+		return 0, jms20subset.CreateMessageFormatException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
+			MessageImpl_PROPERTY_CONVERT_FAILED_CODE, parseErr)
+	}
+
+	return valueRet, nil
+}
+
+// SetBooleanProperty enables an application to set a bool-type message property.
+func (msg *MessageImpl) SetBooleanProperty(name string, value bool) jms20subset.JMSException {
+	return msg.setProperty(name, value)
+}
+
+// GetBooleanProperty returns the bool value of a named message property.
+// Returns false if the named property is not set.
+func (msg *MessageImpl) GetBooleanProperty(name string) (bool, jms20subset.JMSException) {
+
+	value, retErr := msg.getProperty(name)
+	if value == nil || retErr != nil {
+		return false, retErr
+	}
+
+	var valueRet bool
+	var parseErr error
+
+	switch valueTyped := value.(type) {
+	case bool:
+		valueRet = valueTyped
+	case string:
+		valueRet, parseErr = strconv.ParseBool(valueTyped)
+	case int64:
+		// Conversion from int to bool is true iff n=1
+		valueRet = valueTyped == 1
+	case float64:
+		// Conversion from float64 to bool is true iff n=1
+		valueRet = valueTyped == 1
+	}
+
+	if parseErr != nil {
+		return false, jms20subset.CreateMessageFormatException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
+			MessageImpl_PROPERTY_CONVERT_FAILED_CODE, parseErr)
+	}
+
+	return valueRet, nil
+}
+
+// PropertyExists returns true if the named message property exists on this message.
+func (msg *MessageImpl) PropertyExists(name string) (bool, jms20subset.JMSException) {
+
+	value, retErr := msg.getProperty(name)
+	return value != nil, retErr
+
+}
+
+// GetPropertyNames returns a slice of strings containing the name of every message
+// property on this message.
+// Returns a zero length slice if no message properties are set.
+func (msg *MessageImpl) GetPropertyNames() ([]string, jms20subset.JMSException) {
+
+	if msg.released {
+		return nil, createReleasedException()
+	}
+
+	propNames := make([]string, 0, len(msg.properties))
+	for _, prop := range msg.properties {
+		propNames = append(propNames, prop.name)
+	}
+
+	return propNames, nil
+}
+
+// ClearProperties removes all message properties from this message.
+func (msg *MessageImpl) ClearProperties() jms20subset.JMSException {
+
+	if msg.released {
+		return createReleasedException()
+	}
+
+	msg.properties = nil
+	return nil
+}
+
+// setProperty sets the value of the named property, replacing any existing value.
+func (msg *MessageImpl) setProperty(name string, value interface{}) jms20subset.JMSException {
+
+	if msg.released {
+		return createReleasedException()
+	}
+
+	for i := range msg.properties {
+		if msg.properties[i].name == name {
+			msg.properties[i].value = value
+			return nil
+		}
+	}
+
+	msg.properties = append(msg.properties, property{name: name, value: value})
+	return nil
+}
+
+// getProperty returns the value of the named property, or nil if it is not set.
+func (msg *MessageImpl) getProperty(name string) (interface{}, jms20subset.JMSException) {
+
+	if msg.released {
+		return nil, createReleasedException()
+	}
+
+	for _, prop := range msg.properties {
+		if prop.name == name {
+			return prop.value, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// deleteProperty removes the named property, if it is set.
+func (msg *MessageImpl) deleteProperty(name string) jms20subset.JMSException {
+
+	if msg.released {
+		return createReleasedException()
+	}
+
+	for i, prop := range msg.properties {
+		if prop.name == name {
+			msg.properties = append(msg.properties[:i], msg.properties[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// copyMessageImpl returns a copy of the attributes of a message that shares
+// nothing with the original, so that changes to one do not affect the other.
+func (msg *MessageImpl) copyMessageImpl() MessageImpl {
+
+	msgCopy := *msg
+	msgCopy.properties = append([]property(nil), msg.properties...)
+
+	return msgCopy
+}
+
+// createReleasedException returns the error for a message that has been released.
+func createReleasedException() jms20subset.JMSException {
+	return jms20subset.CreateIllegalStateException(MessageImpl_RELEASED_REASON, MessageImpl_RELEASED_CODE, nil)
+}
+
+// message is the copy of a message that is held on a queue, which is never
+// modified once it has been sent.
+type message struct {
+	MessageImpl
+	isText    bool
+	bodyStr   *string
+	bodyBytes []byte
+}
@@ -0,0 +1,210 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package memjms provides an in-memory implementation of the JMS style Golang
+// interfaces, so that applications can be tested without a queue manager.
+package memjms
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// ProducerImpl defines a struct that contains the necessary objects for
+// sending messages to an in-memory queue.
+type ProducerImpl struct {
+	ctx          ContextImpl
+	deliveryMode int
+	timeToLive   int
+	priority     int
+}
+
+// ProducerImpl_MAX_PRIORITY is the highest priority that a message can have,
+// which is the same as IBM MQ. Messages sent with a higher priority are given
+// this priority instead.
+const ProducerImpl_MAX_PRIORITY int = 9
+
+// SendString sends a TextMessage with the specified body to the specified Destination
+// using any message options that are defined on this JMSProducer.
+func (producer ProducerImpl) SendString(dest jms20subset.Destination, bodyStr string) jms20subset.JMSException {
+
+	msg := producer.ctx.CreateTextMessageWithString(bodyStr)
+	return producer.Send(dest, msg)
+
+}
+
+// SendBytes sends a BytesMessage with the specified body to the specified Destination
+// using any message options that are defined on this JMSProducer.
+func (producer ProducerImpl) SendBytes(dest jms20subset.Destination, body []byte) jms20subset.JMSException {
+
+	msg := producer.ctx.CreateBytesMessageWithBytes(body)
+	return producer.Send(dest, msg)
+
+}
+
+// ProducerImpl_STREAM_READ_REASON is the reason used in the JMSException that is
+// returned by SendStream when the stream cannot be read, which links to the error
+// that was returned by the stream.
+const ProducerImpl_STREAM_READ_REASON string = "MQJMS_E_STREAM_READ_FAILED"
+
+// ProducerImpl_STREAM_READ_CODE is the error code used in the JMSException that is
+// returned by SendStream when the stream cannot be read.
+const ProducerImpl_STREAM_READ_CODE string = "StreamReadFailed"
+
+// SendStream sends the data that is read from the stream to the specified
+// Destination, so that it can be received using ReceiveStream. The messages of
+// an in-memory queue are all held in memory, so unlike the IBM MQ provider the
+// whole stream is read and then sent as a single BytesMessage, which means that
+// nothing is sent if the stream cannot be read.
+func (producer ProducerImpl) SendStream(dest jms20subset.Destination, stream io.Reader) jms20subset.JMSException {
+
+	body, readErr := io.ReadAll(stream)
+	if readErr != nil {
+		return jms20subset.CreateJMSException(ProducerImpl_STREAM_READ_REASON, ProducerImpl_STREAM_READ_CODE, readErr)
+	}
+
+	return producer.SendBytes(dest, body)
+
+}
+
+// Send a message to the specified Destination, using any message options that
+// are defined on this JMSProducer.
+func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.Message) jms20subset.JMSException {
+	return producer.SendContext(context.Background(), dest, msg)
+}
+
+// SendContext sends a message in the same way as Send, unless the supplied Go
+// context is already cancelled or has reached its deadline, in which case an
+// error is returned and the message is not sent.
+func (producer ProducerImpl) SendContext(ctx context.Context, dest jms20subset.Destination, msg jms20subset.Message) jms20subset.JMSException {
+
+	if jmsErr := checkContextDone(ctx); jmsErr != nil {
+		return jmsErr
+	}
+
+	qm := producer.ctx.qm
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	if producer.ctx.state.closed {
+		return createContextClosedException()
+	}
+
+	if dest == nil || dest.GetDestinationName() == "" {
+		return jms20subset.CreateInvalidDestinationException(ContextImpl_UNKNOWN_OBJECT_NAME_REASON,
+			ContextImpl_UNKNOWN_OBJECT_NAME_CODE, nil)
+	}
+
+	// Find the attributes that are common to all types of message, so that the
+	// ones that are assigned when the message is sent can be set.
+	var msgImpl *MessageImpl
+	stored := message{}
+
+	switch typedMsg := msg.(type) {
+	case *TextMessageImpl:
+		msgImpl = &typedMsg.MessageImpl
+		stored.isText = true
+		if typedMsg.bodyStr != nil && *typedMsg.bodyStr != "" {
+			bodyStr := *typedMsg.bodyStr
+			stored.bodyStr = &bodyStr
+		}
+
+	case *BytesMessageImpl:
+		msgImpl = &typedMsg.MessageImpl
+		stored.bodyBytes = append([]byte{}, *typedMsg.ReadBytes()...)
+
+	default:
+		// Messages from other providers cannot be sent, in the same way as for the
+		// IBM MQ provider.
+		return jms20subset.CreateMessageFormatException("UnexpectedMessageType", "UnexpectedMessageType-send1", nil)
+	}
+
+	if msgImpl.released {
+		return createReleasedException()
+	}
+
+	seq, msgID := qm.nextMessageID()
+
+	msgImpl.messageID = msgID
+	msgImpl.timestamp = time.Now().UnixNano() / 1000000
+	msgImpl.deliveryMode = producer.deliveryMode
+
+	msgImpl.priority = producer.priority
+	if msgImpl.priority > ProducerImpl_MAX_PRIORITY {
+		msgImpl.priority = ProducerImpl_MAX_PRIORITY
+	}
+
+	// IBM MQ holds the expiry in tenths of a second, so round the time to live
+	// down in the same way, with a time to live below that meaning no expiry.
+	msgImpl.expiration = 0
+	if ttlTenths := producer.timeToLive / 100; ttlTenths > 0 {
+		msgImpl.expiration = msgImpl.timestamp + int64(ttlTenths*100)
+	}
+
+	// Take a copy of the message, so that changes the application makes to it
+	// after it has been sent do not affect the message on the queue.
+	stored.MessageImpl = msgImpl.copyMessageImpl()
+
+	qm.put(dest.GetDestinationName(), seq, stored, producer.ctx.state.tx)
+
+	return nil
+}
+
+// SetDeliveryMode stores the specified delivery mode so that it can be applied
+// when sending messages using this Producer. Invalid values are ignored.
+func (producer *ProducerImpl) SetDeliveryMode(mode int) jms20subset.JMSProducer {
+
+	if mode == jms20subset.DeliveryMode_PERSISTENT || mode == jms20subset.DeliveryMode_NON_PERSISTENT {
+		producer.deliveryMode = mode
+	}
+
+	return producer
+}
+
+// GetDeliveryMode returns the current delivery mode that is set on this
+// Producer.
+func (producer *ProducerImpl) GetDeliveryMode() int {
+	return producer.deliveryMode
+}
+
+// SetTimeToLive stores the specified time to live in milliseconds so that it
+// can be applied when sending messages using this Producer. Negative values
+// are ignored.
+func (producer *ProducerImpl) SetTimeToLive(timeToLive int) jms20subset.JMSProducer {
+
+	if timeToLive >= 0 {
+		producer.timeToLive = timeToLive
+	}
+
+	return producer
+}
+
+// GetTimeToLive returns the current time to live that is set on this
+// Producer.
+func (producer *ProducerImpl) GetTimeToLive() int {
+	return producer.timeToLive
+}
+
+// SetPriority stores the specified priority so that it can be applied when
+// sending messages using this Producer. Negative values are ignored.
+func (producer *ProducerImpl) SetPriority(priority int) jms20subset.JMSProducer {
+
+	if priority >= 0 {
+		producer.priority = priority
+	}
+
+	return producer
+}
+
+// GetPriority returns the priority for all messages sent by this producer.
+func (producer *ProducerImpl) GetPriority() int {
+	return producer.priority
+}
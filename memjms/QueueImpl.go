@@ -0,0 +1,58 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package memjms provides an in-memory implementation of the JMS style Golang
+// interfaces, so that applications can be tested without a queue manager.
+package memjms
+
+import (
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// QueueImpl represents a queue of the in-memory provider.
+type QueueImpl struct {
+	queueName       string
+	putAsyncAllowed int
+}
+
+// GetQueueName returns the name of the queue that is represented by this object.
+func (queue QueueImpl) GetQueueName() string {
+
+	return queue.queueName
+
+}
+
+// GetDestinationName returns the name of the destination represented by this
+// object.
+func (queue QueueImpl) GetDestinationName() string {
+
+	return queue.queueName
+
+}
+
+// SetPutAsyncAllowed allows the async allowed setting to be updated. Messages
+// are always sent synchronously by the in-memory provider, but the setting is
+// stored so that applications see the same behaviour as with IBM MQ.
+func (queue QueueImpl) SetPutAsyncAllowed(paa int) jms20subset.Queue {
+
+	// Check that the specified paa parameter is one of the values that we permit,
+	// and if so store that value inside queue. Other values are ignored.
+	if paa == jms20subset.Destination_PUT_ASYNC_ALLOWED_ENABLED ||
+		paa == jms20subset.Destination_PUT_ASYNC_ALLOWED_DISABLED ||
+		paa == jms20subset.Destination_PUT_ASYNC_ALLOWED_AS_DEST {
+
+		queue.putAsyncAllowed = paa
+	}
+
+	return queue
+}
+
+// GetPutAsyncAllowed returns the current setting for async put.
+func (queue QueueImpl) GetPutAsyncAllowed() int {
+	return queue.putAsyncAllowed
+}
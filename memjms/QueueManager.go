@@ -0,0 +1,280 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package memjms provides an in-memory implementation of the JMS style Golang
+// interfaces, so that applications can be tested without a queue manager.
+package memjms
+
+import (
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+)
+
+// QueueManager_DEFAULT_NAME is the name of the QueueManager that is created by
+// NewConnectionFactory, which appears in the message IDs that it assigns.
+const QueueManager_DEFAULT_NAME string = "MEMQM"
+
+// QueueManager holds the queues and messages of the in-memory provider. Each
+// QueueManager is independent of the others, so separate tests can use
+// separate instances without seeing each other's messages.
+//
+// Queues are defined automatically the first time that they are used, and
+// exist for as long as the QueueManager.
+type QueueManager struct {
+	name      string
+	lock      sync.Mutex // Held for all access to the queues and the contexts that use them
+	queues    map[string]*queue
+	nextSeq   uint64
+	available chan struct{} // Closed and replaced whenever messages may have become available
+}
+
+// queue holds the messages of a single queue, in the order in which they are
+// received, which is highest priority first and then the order they were sent.
+type queue struct {
+	name     string
+	messages []*storedMessage
+}
+
+// storedMessage is a message on a queue, together with the transactions (if any)
+// that have sent or received it and that are not yet complete.
+type storedMessage struct {
+	seq   uint64
+	queue *queue
+	msg   message      // Copy of the message that was sent, which is never modified
+	putTx *transaction // Transaction that sent the message, until it is committed
+	getTx *transaction // Transaction that received the message, until it is completed
+}
+
+// transaction records the messages that have been sent and received in the
+// current unit of work of a transacted JMSContext.
+type transaction struct {
+	puts []*storedMessage
+	gets []*storedMessage
+}
+
+// NewQueueManager creates an empty QueueManager with the specified name.
+func NewQueueManager(name string) *QueueManager {
+
+	return &QueueManager{
+		name:      name,
+		queues:    make(map[string]*queue),
+		available: make(chan struct{}),
+	}
+}
+
+// GetName returns the name of this QueueManager.
+func (qm *QueueManager) GetName() string {
+	return qm.name
+}
+
+// Depth returns the number of messages on the named queue that are available to
+// be received, which excludes messages that have expired and messages that are
+// part of a transaction that is not yet complete.
+func (qm *QueueManager) Depth(queueName string) int {
+
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	depth := 0
+
+	if q, ok := qm.queues[queueName]; ok {
+		now := time.Now()
+		for _, stored := range q.messages {
+			if stored.isAvailable(now) {
+				depth++
+			}
+		}
+	}
+
+	return depth
+}
+
+// getQueue returns the named queue, defining it if this is the first time that
+// it has been used. The lock must be held.
+func (qm *QueueManager) getQueue(queueName string) *queue {
+
+	q, ok := qm.queues[queueName]
+	if !ok {
+		q = &queue{name: queueName}
+		qm.queues[queueName] = q
+	}
+
+	return q
+}
+
+// nextMessageID assigns a unique message ID, which in the same way as IBM MQ
+// is made up of an eye catcher, the name of the queue manager and a counter.
+// The lock must be held.
+func (qm *QueueManager) nextMessageID() (uint64, [24]byte) {
+
+	qm.nextSeq++
+
+	var msgID [24]byte
+	copy(msgID[0:4], "MEM ")
+	copy(msgID[4:16], qm.name+"            ")
+	binary.BigEndian.PutUint64(msgID[16:24], qm.nextSeq)
+
+	return qm.nextSeq, msgID
+}
+
+// put adds a message to the named queue, under the transaction if one is
+// supplied. The lock must be held.
+func (qm *QueueManager) put(queueName string, seq uint64, msg message, tx *transaction) {
+
+	q := qm.getQueue(queueName)
+
+	stored := &storedMessage{
+		seq:   seq,
+		queue: q,
+		msg:   msg,
+		putTx: tx,
+	}
+
+	// Insert the message after every message of the same or higher priority.
+	pos := sort.Search(len(q.messages), func(i int) bool {
+		return q.messages[i].msg.priority < msg.priority
+	})
+	q.messages = append(q.messages, nil)
+	copy(q.messages[pos+1:], q.messages[pos:])
+	q.messages[pos] = stored
+
+	if tx != nil {
+		tx.puts = append(tx.puts, stored)
+	} else {
+		qm.notifyAvailable()
+	}
+}
+
+// find returns the first message on the named queue that is available and
+// matches the selector, or nil if there is no such message, without removing it.
+//
+// If after is not nil then only messages that are later in the queue than it are
+// considered, which is how a browser moves along the queue. The lock must be held.
+func (qm *QueueManager) find(queueName string, sel selector, after *storedMessage) *storedMessage {
+
+	q := qm.getQueue(queueName)
+	now := time.Now()
+
+	for i := 0; i < len(q.messages); i++ {
+
+		stored := q.messages[i]
+
+		// Discard expired messages as they are found, as IBM MQ does.
+		if stored.isExpired(now) && stored.putTx == nil && stored.getTx == nil {
+			q.remove(stored)
+			i--
+			continue
+		}
+
+		if !stored.isAvailable(now) || !sel.matches(&stored.msg) {
+			continue
+		}
+
+		if after != nil && !stored.isAfter(after) {
+			continue
+		}
+
+		return stored
+	}
+
+	return nil
+}
+
+// take removes a message that was returned by find from its queue, or locks it
+// under the transaction if one is supplied. The lock must be held.
+func (qm *QueueManager) take(stored *storedMessage, tx *transaction) {
+
+	if tx != nil {
+		stored.getTx = tx
+		tx.gets = append(tx.gets, stored)
+	} else {
+		stored.queue.remove(stored)
+	}
+}
+
+// commit completes the transaction, so that the messages that were sent become
+// available and the messages that were received are removed. The lock must be held.
+func (qm *QueueManager) commit(tx *transaction) {
+
+	for _, stored := range tx.puts {
+		stored.putTx = nil
+	}
+
+	for _, stored := range tx.gets {
+		stored.queue.remove(stored)
+	}
+
+	if len(tx.puts) > 0 {
+		qm.notifyAvailable()
+	}
+
+	tx.puts = nil
+	tx.gets = nil
+}
+
+// rollback backs out the transaction, so that the messages that were sent are
+// discarded and the messages that were received become available again. The
+// lock must be held.
+func (qm *QueueManager) rollback(tx *transaction) {
+
+	for _, stored := range tx.puts {
+		stored.queue.remove(stored)
+	}
+
+	for _, stored := range tx.gets {
+		stored.getTx = nil
+	}
+
+	if len(tx.gets) > 0 {
+		qm.notifyAvailable()
+	}
+
+	tx.puts = nil
+	tx.gets = nil
+}
+
+// notifyAvailable wakes up the goroutines that are waiting for a message to
+// become available, so that they check the queues again. The lock must be held.
+func (qm *QueueManager) notifyAvailable() {
+	close(qm.available)
+	qm.available = make(chan struct{})
+}
+
+// remove takes the specified message off the queue.
+func (q *queue) remove(stored *storedMessage) {
+
+	for i, thisMsg := range q.messages {
+		if thisMsg == stored {
+			q.messages = append(q.messages[:i], q.messages[i+1:]...)
+			return
+		}
+	}
+}
+
+// isExpired returns true if the time to live of the message has passed.
+func (stored *storedMessage) isExpired(now time.Time) bool {
+	return stored.msg.expiration != 0 && now.UnixNano()/1000000 >= stored.msg.expiration
+}
+
+// isAvailable returns true if the message can be received, because it has
+// not expired and it is not part of an incomplete transaction.
+func (stored *storedMessage) isAvailable(now time.Time) bool {
+	return stored.putTx == nil && stored.getTx == nil && !stored.isExpired(now)
+}
+
+// isAfter returns true if this message is later in the queue than the other.
+func (stored *storedMessage) isAfter(other *storedMessage) bool {
+
+	if stored.msg.priority != other.msg.priority {
+		return stored.msg.priority < other.msg.priority
+	}
+
+	return stored.seq > other.seq
+}
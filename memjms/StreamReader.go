@@ -0,0 +1,77 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package memjms provides an in-memory implementation of the JMS style Golang
+// interfaces, so that applications can be tested without a queue manager.
+package memjms
+
+import (
+	"io"
+
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// streamReader is the io.ReadCloser that is returned by ConsumerImpl.ReceiveStream.
+type streamReader struct {
+	qm     *QueueManager
+	tx     *transaction // Transaction of the stream, or nil if the JMSContext is transacted
+	body   []byte       // Part of the body that has not been read
+	eof    bool
+	closed bool
+}
+
+// Read reads data from the body of the message. io.EOF is returned once all of
+// it has been read, at which point the message is removed from the queue if the
+// JMSContext is not transacted.
+func (reader *streamReader) Read(p []byte) (int, error) {
+
+	if reader.closed {
+		return 0, jms20subset.CreateIllegalStateException(ConsumerImpl_STREAM_CLOSED_REASON, ContextImpl_ILLEGAL_STATE_CODE, nil)
+	}
+
+	if len(reader.body) == 0 {
+
+		if !reader.eof && reader.tx != nil {
+			reader.qm.lock.Lock()
+			reader.qm.commit(reader.tx)
+			reader.qm.lock.Unlock()
+		}
+
+		reader.eof = true
+		return 0, io.EOF
+	}
+
+	n := copy(p, reader.body)
+	reader.body = reader.body[n:]
+
+	return n, nil
+}
+
+// Close closes the reader. If the JMSContext is not transacted then the message
+// is removed from the queue if the whole body has been read, even if Read has not
+// yet returned io.EOF, and is otherwise left on the queue. Closing a reader that
+// is already closed has no effect.
+func (reader *streamReader) Close() error {
+
+	if reader.closed {
+		return nil
+	}
+	reader.closed = true
+
+	if !reader.eof && reader.tx != nil {
+		reader.qm.lock.Lock()
+		if len(reader.body) == 0 {
+			reader.qm.commit(reader.tx)
+		} else {
+			reader.qm.rollback(reader.tx)
+		}
+		reader.qm.lock.Unlock()
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+package memjms
+
+import (
+	"testing"
+
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset/conformance"
+)
+
+/*
+ * Test that the in-memory provider passes the provider conformance suite.
+ */
+func TestConformance(t *testing.T) {
+
+	conformance.Run(t, func() (jms20subset.ConnectionFactory, error) {
+		return NewConnectionFactory(), nil
+	})
+
+}
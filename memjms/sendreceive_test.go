@@ -0,0 +1,356 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+package memjms
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+/*
+ * Test sending and receiving text and bytes messages, including their headers
+ * and properties.
+ */
+func TestSendReceive(t *testing.T) {
+
+	cf := NewConnectionFactory()
+
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	defer context.Close()
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	defer consumer.Close()
+
+	// Nothing has been sent yet.
+	msg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, msg)
+
+	txtMsg := context.CreateTextMessageWithString("Hello in memory")
+	txtMsg.SetJMSCorrelationID("Hello World")
+	txtMsg.SetJMSReplyTo(context.CreateQueue("DEV.QUEUE.2"))
+	txtMsg.SetIntProperty("count", 42)
+	strValue := "value"
+	txtMsg.SetStringProperty("name", &strValue)
+
+	assert.Equal(t, "", txtMsg.GetJMSMessageID())
+	sendErr := context.CreateProducer().SetDeliveryMode(jms20subset.DeliveryMode_NON_PERSISTENT).Send(queue, txtMsg)
+	assert.Nil(t, sendErr)
+	assert.Equal(t, 48, len(txtMsg.GetJMSMessageID()))
+	assert.NotEqual(t, int64(0), txtMsg.GetJMSTimestamp())
+
+	// Changes after sending have no effect on the message on the queue.
+	txtMsg.SetText("changed")
+	txtMsg.SetIntProperty("count", 43)
+	assert.Equal(t, 1, cf.QueueManager.Depth("DEV.QUEUE.1"))
+
+	msg, rcvErr = consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	switch rcvMsg := msg.(type) {
+	case jms20subset.TextMessage:
+		assert.Equal(t, "Hello in memory", *rcvMsg.GetText())
+	default:
+		assert.Fail(t, "Got something other than a text message")
+	}
+	assert.Equal(t, txtMsg.GetJMSMessageID(), msg.GetJMSMessageID())
+	assert.Equal(t, txtMsg.GetJMSTimestamp(), msg.GetJMSTimestamp())
+	assert.Equal(t, "Hello World", msg.GetJMSCorrelationID())
+	assert.Equal(t, "DEV.QUEUE.2", msg.GetJMSReplyTo().GetDestinationName())
+	assert.Equal(t, jms20subset.DeliveryMode_NON_PERSISTENT, msg.GetJMSDeliveryMode())
+	assert.Equal(t, jms20subset.Priority_DEFAULT, msg.GetJMSPriority())
+	assert.Equal(t, int64(0), msg.GetJMSExpiration())
+
+	countValue, propErr := msg.GetIntProperty("count")
+	assert.Nil(t, propErr)
+	assert.Equal(t, 42, countValue)
+	propNames, propErr := msg.GetPropertyNames()
+	assert.Nil(t, propErr)
+	assert.Equal(t, []string{"count", "name"}, propNames)
+
+	// Bytes messages, including an empty body.
+	sendErr = context.CreateProducer().SendBytes(queue, []byte{1, 2, 3})
+	assert.Nil(t, sendErr)
+	sendErr = context.CreateProducer().SendBytes(queue, nil)
+	assert.Nil(t, sendErr)
+
+	bytesBody, rcvErr := consumer.ReceiveBytesBodyNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Equal(t, []byte{1, 2, 3}, *bytesBody)
+	bytesBody, rcvErr = consumer.ReceiveBytesBodyNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Equal(t, 0, len(*bytesBody))
+
+	// An empty text body is received as nil, and the wrong type of body is an error.
+	context.CreateProducer().SendString(queue, "")
+	context.CreateProducer().SendBytes(queue, []byte("not text"))
+
+	strBody, rcvErr := consumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, strBody)
+	strBody, rcvErr = consumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, strBody)
+	assert.NotNil(t, rcvErr)
+	assert.Equal(t, "MQJMS_DIR_MIN_NOTTEXT", rcvErr.GetReason())
+	assert.True(t, errors.Is(rcvErr, jms20subset.MessageFormatException{}))
+
+}
+
+/*
+ * Test the conversion of property values between types, which is the same as
+ * for the IBM MQ provider.
+ */
+func TestPropertyConversion(t *testing.T) {
+
+	context, ctxErr := NewConnectionFactory().CreateContext()
+	assert.Nil(t, ctxErr)
+	defer context.Close()
+
+	msg := context.CreateTextMessage()
+	intStr := "245"
+	notNumberStr := "notANumber"
+	msg.SetStringProperty("intStr", &intStr)
+	msg.SetStringProperty("notNumberStr", &notNumberStr)
+	msg.SetDoubleProperty("double", 2.5)
+	msg.SetBooleanProperty("bool", true)
+	msg.SetIntProperty("one", 1)
+
+	intValue, propErr := msg.GetIntProperty("intStr")
+	assert.Nil(t, propErr)
+	assert.Equal(t, 245, intValue)
+
+	intValue, propErr = msg.GetIntProperty("notNumberStr")
+	assert.Equal(t, 0, intValue)
+	assert.NotNil(t, propErr)
+	assert.Equal(t, "MQJMS_E_BAD_TYPE", propErr.GetReason())
+	assert.Equal(t, "1055", propErr.GetErrorCode())
+
+	strValue, propErr := msg.GetStringProperty("double")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "2.5", *strValue)
+
+	intValue, propErr = msg.GetIntProperty("bool")
+	assert.Nil(t, propErr)
+	assert.Equal(t, 1, intValue)
+
+	boolValue, propErr := msg.GetBooleanProperty("one")
+	assert.Nil(t, propErr)
+	assert.True(t, boolValue)
+
+	strValue, propErr = msg.GetStringProperty("notSet")
+	assert.Nil(t, propErr)
+	assert.Nil(t, strValue)
+
+	// Unset a property, then clear them all.
+	msg.SetStringProperty("intStr", nil)
+	exists, propErr := msg.PropertyExists("intStr")
+	assert.Nil(t, propErr)
+	assert.False(t, exists)
+
+	assert.Nil(t, msg.ClearProperties())
+	propNames, propErr := msg.GetPropertyNames()
+	assert.Nil(t, propErr)
+	assert.Equal(t, 0, len(propNames))
+
+	// The properties cannot be used once the message has been released.
+	msg.Release()
+	propErr = msg.SetIntProperty("one", 1)
+	assert.NotNil(t, propErr)
+	assert.Equal(t, MessageImpl_RELEASED_REASON, propErr.GetReason())
+
+}
+
+/*
+ * Test that messages are received in priority order, and that correlation ID
+ * and message ID selectors pick out the right message.
+ */
+func TestPriorityAndSelectors(t *testing.T) {
+
+	context, ctxErr := NewConnectionFactory().CreateContext()
+	assert.Nil(t, ctxErr)
+	defer context.Close()
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	producer := context.CreateProducer()
+
+	producer.SetPriority(2).SendString(queue, "low")
+	producer.SetPriority(7).SendString(queue, "high 1")
+	producer.SetPriority(4).SendString(queue, "default")
+	producer.SetPriority(7).SendString(queue, "high 2")
+
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	defer consumer.Close()
+
+	for _, expected := range []string{"high 1", "high 2", "default", "low"} {
+		body, rcvErr := consumer.ReceiveStringBodyNoWait()
+		assert.Nil(t, rcvErr)
+		assert.Equal(t, expected, *body)
+	}
+
+	// Select by correlation ID, with and without the ID: prefix.
+	for _, correlID := range []string{"first", "second", "010203040506"} {
+		msg := context.CreateTextMessageWithString(correlID)
+		msg.SetJMSCorrelationID(correlID)
+		assert.Nil(t, producer.Send(queue, msg))
+	}
+
+	correlConsumer, conErr := context.CreateConsumerWithSelector(queue, "JMSCorrelationID = 'ID:010203040506'")
+	assert.Nil(t, conErr)
+	body, rcvErr := correlConsumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Equal(t, "010203040506", *body)
+	correlConsumer.Close()
+
+	correlConsumer, conErr = context.CreateConsumerWithSelector(queue, "JMSCorrelationID = 'second'")
+	assert.Nil(t, conErr)
+	msg, rcvErr := correlConsumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Equal(t, "second", msg.GetJMSCorrelationID())
+	correlConsumer.Close()
+
+	// Select the remaining message by its message ID.
+	browser, brErr := context.CreateBrowser(queue)
+	assert.Nil(t, brErr)
+	iter, _ := browser.GetEnumeration()
+	browsed, _ := iter.GetNext()
+	browser.Close()
+
+	msgIDConsumer, conErr := context.CreateConsumerWithSelector(queue, "JMSMessageID = '"+browsed.GetJMSMessageID()+"'")
+	assert.Nil(t, conErr)
+	msg, rcvErr = msgIDConsumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Equal(t, browsed.GetJMSMessageID(), msg.GetJMSMessageID())
+	msgIDConsumer.Close()
+
+	// Only the same selectors are supported as by the IBM MQ provider.
+	_, conErr = context.CreateConsumerWithSelector(queue, "JMSPriority = 4")
+	assert.NotNil(t, conErr)
+	assert.Equal(t, "MQJMS0004", conErr.GetErrorCode())
+	assert.True(t, errors.Is(conErr, jms20subset.InvalidSelectorException{}))
+
+	_, conErr = context.CreateConsumerWithSelector(queue, "JMSCorrelationID = ''")
+	assert.NotNil(t, conErr)
+
+}
+
+/*
+ * Test that messages expire once their time to live has passed.
+ */
+func TestTimeToLive(t *testing.T) {
+
+	cf := NewConnectionFactory()
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	defer context.Close()
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	producer := context.CreateProducer().SetTimeToLive(200)
+
+	msg := context.CreateTextMessageWithString("short lived")
+	assert.Nil(t, producer.Send(queue, msg))
+	assert.Equal(t, msg.GetJMSTimestamp()+200, msg.GetJMSExpiration())
+	assert.Equal(t, 1, cf.QueueManager.Depth("DEV.QUEUE.1"))
+
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, 0, cf.QueueManager.Depth("DEV.QUEUE.1"))
+
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	defer consumer.Close()
+
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+}
+
+/*
+ * Test the receive calls that wait for a message to arrive.
+ */
+func TestReceiveWithWait(t *testing.T) {
+
+	context, ctxErr := NewConnectionFactory().CreateContext()
+	assert.Nil(t, ctxErr)
+	defer context.Close()
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+
+	// Time out when there is no message.
+	start := time.Now()
+	msg, rcvErr := consumer.Receive(100)
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, msg)
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+
+	// Wake up when a message is sent by another goroutine.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		context.CreateProducer().SendString(queue, "arrived")
+	}()
+	body, rcvErr := consumer.ReceiveStringBody(5000)
+	assert.Nil(t, rcvErr)
+	assert.Equal(t, "arrived", *body)
+
+	// Return when the Go context is done.
+	goCtx, cancel := contextWithTimeout(50 * time.Millisecond)
+	defer cancel()
+	msg, rcvErr = consumer.ReceiveContext(goCtx)
+	assert.Nil(t, msg)
+	assert.NotNil(t, rcvErr)
+	assert.Equal(t, ContextImpl_CONTEXT_DONE_REASON, rcvErr.GetReason())
+
+	// Deliver messages on a channel.
+	chanCtx, chanCancel := contextWithTimeout(5 * time.Second)
+	msgChan, errChan := consumer.ReceiveChannel(chanCtx, 1)
+	context.CreateProducer().SendString(queue, "on channel")
+	rcvMsg := <-msgChan
+	assert.Equal(t, "on channel", *rcvMsg.(jms20subset.TextMessage).GetText())
+	chanCancel()
+	for range msgChan {
+	}
+	assert.Nil(t, <-errChan)
+
+	// A message that is larger than the buffer stays on the queue.
+	context.CreateProducer().SendBytes(queue, []byte("0123456789"))
+	msg, rcvErr = consumer.ReceiveInto(make([]byte, 5), 0)
+	assert.Nil(t, msg)
+	assert.NotNil(t, rcvErr)
+	assert.Equal(t, "2080", rcvErr.GetErrorCode())
+
+	buffer := make([]byte, 20)
+	msg, rcvErr = consumer.ReceiveInto(buffer, 0)
+	assert.Nil(t, rcvErr)
+	assert.Equal(t, []byte("0123456789"), *msg.(jms20subset.BytesMessage).ReadBytes())
+	assert.Equal(t, byte('0'), buffer[0])
+
+	// Closing the consumer wakes up a receive that is waiting.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		consumer.Close()
+	}()
+	msg, rcvErr = consumer.Receive(0)
+	assert.Nil(t, msg)
+	assert.NotNil(t, rcvErr)
+	assert.Equal(t, ConsumerImpl_CONSUMER_CLOSED_REASON, rcvErr.GetReason())
+
+}
+
+// contextWithTimeout returns a Go context that is done after the timeout.
+func contextWithTimeout(timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), timeout)
+}
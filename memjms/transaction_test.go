@@ -0,0 +1,197 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+package memjms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+/*
+ * Test that messages sent and received under a transaction are only confirmed
+ * when it is committed, and are backed out when it is rolled back.
+ */
+func TestLocalTransaction(t *testing.T) {
+
+	cf := NewConnectionFactory()
+
+	txContext, ctxErr := cf.CreateContextWithSessionMode(jms20subset.JMSContextSESSIONTRANSACTED)
+	assert.Nil(t, ctxErr)
+	defer txContext.Close()
+
+	otherContext, ctxErr := txContext.CreateContext(jms20subset.JMSContextAUTOACKNOWLEDGE)
+	assert.Nil(t, ctxErr)
+	defer otherContext.Close()
+
+	queue := txContext.CreateQueue("DEV.QUEUE.1")
+	otherConsumer, conErr := otherContext.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+
+	// A message that is sent is not visible until it is committed.
+	assert.Nil(t, txContext.CreateProducer().SendString(queue, "rolled back"))
+	msg, rcvErr := otherConsumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, msg)
+
+	assert.Nil(t, txContext.Rollback())
+	assert.Equal(t, 0, cf.QueueManager.Depth("DEV.QUEUE.1"))
+
+	assert.Nil(t, txContext.CreateProducer().SendString(queue, "committed"))
+	assert.Nil(t, txContext.Commit())
+	assert.Equal(t, 1, cf.QueueManager.Depth("DEV.QUEUE.1"))
+
+	// A message that is received is put back on the queue by a rollback.
+	txConsumer, conErr := txContext.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+
+	body, rcvErr := txConsumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Equal(t, "committed", *body)
+	assert.Equal(t, 0, cf.QueueManager.Depth("DEV.QUEUE.1"))
+
+	assert.Nil(t, txContext.Rollback())
+	assert.Equal(t, 1, cf.QueueManager.Depth("DEV.QUEUE.1"))
+
+	body, rcvErr = txConsumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Equal(t, "committed", *body)
+	assert.Nil(t, txContext.Commit())
+	assert.Equal(t, 0, cf.QueueManager.Depth("DEV.QUEUE.1"))
+
+	// Closing the context rolls back the transaction.
+	assert.Nil(t, txContext.CreateProducer().SendString(queue, "closed"))
+	txContext.Close()
+	assert.Equal(t, 0, cf.QueueManager.Depth("DEV.QUEUE.1"))
+
+	// Commit has no effect on a context that is not transacted.
+	assert.Nil(t, otherContext.CreateProducer().SendString(queue, "auto"))
+	assert.Equal(t, 1, cf.QueueManager.Depth("DEV.QUEUE.1"))
+	assert.Nil(t, otherContext.Rollback())
+	assert.Equal(t, 1, cf.QueueManager.Depth("DEV.QUEUE.1"))
+
+}
+
+/*
+ * Test that the transacted contexts created from the same context share its
+ * transaction scope.
+ */
+func TestSharedTransaction(t *testing.T) {
+
+	cf := NewConnectionFactory()
+
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	defer context.Close()
+
+	txContext1, ctxErr := context.CreateContext(jms20subset.JMSContextSESSIONTRANSACTED)
+	assert.Nil(t, ctxErr)
+	defer txContext1.Close()
+
+	txContext2, ctxErr := txContext1.CreateContext(jms20subset.JMSContextSESSIONTRANSACTED)
+	assert.Nil(t, ctxErr)
+	defer txContext2.Close()
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+
+	// Work done using one context is committed or rolled back by the other.
+	assert.Nil(t, txContext1.CreateProducer().SendString(queue, "rolled back"))
+	assert.Nil(t, txContext2.Rollback())
+	assert.Nil(t, txContext1.Commit())
+	assert.Equal(t, 0, cf.QueueManager.Depth("DEV.QUEUE.1"))
+
+	assert.Nil(t, txContext2.CreateProducer().SendString(queue, "committed"))
+	assert.Nil(t, context.Commit())
+	assert.Equal(t, 0, cf.QueueManager.Depth("DEV.QUEUE.1"))
+	assert.Nil(t, txContext1.Commit())
+	assert.Equal(t, 1, cf.QueueManager.Depth("DEV.QUEUE.1"))
+
+}
+
+/*
+ * Test that a closed context and the objects created from it return errors.
+ */
+func TestClosedContext(t *testing.T) {
+
+	context, ctxErr := NewConnectionFactory().CreateContext()
+	assert.Nil(t, ctxErr)
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	producer := context.CreateProducer()
+
+	context.Close()
+	context.Close() // Has no further effect
+
+	_, rcvErr := consumer.ReceiveNoWait()
+	assert.NotNil(t, rcvErr)
+	assert.Equal(t, ContextImpl_CONTEXT_CLOSED_REASON, rcvErr.GetReason())
+	assert.True(t, errors.Is(rcvErr, jms20subset.IllegalStateException{}))
+
+	sendErr := producer.SendString(queue, "too late")
+	assert.NotNil(t, sendErr)
+	assert.Equal(t, ContextImpl_CONTEXT_CLOSED_REASON, sendErr.GetReason())
+
+	_, conErr = context.CreateConsumer(queue)
+	assert.NotNil(t, conErr)
+	assert.NotNil(t, context.Commit())
+
+	_, ctxErr = context.CreateContext(jms20subset.JMSContextAUTOACKNOWLEDGE)
+	assert.NotNil(t, ctxErr)
+
+}
+
+/*
+ * Test that a browser sees messages in the order they would be received,
+ * without removing them.
+ */
+func TestQueueBrowser(t *testing.T) {
+
+	cf := NewConnectionFactory()
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	defer context.Close()
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	producer := context.CreateProducer()
+	producer.SendString(queue, "first")
+	producer.SetPriority(6).SendString(queue, "urgent")
+
+	browser, brErr := context.CreateBrowser(queue)
+	assert.Nil(t, brErr)
+
+	iter, iterErr := browser.GetEnumeration()
+	assert.Nil(t, iterErr)
+
+	msg, brErr := iter.GetNext()
+	assert.Nil(t, brErr)
+	assert.Equal(t, "urgent", *msg.(jms20subset.TextMessage).GetText())
+
+	// A message that arrives later in the queue than the browser is seen.
+	producer.SetPriority(4).SendString(queue, "second")
+
+	for _, expected := range []string{"first", "second"} {
+		msg, brErr = iter.GetNext()
+		assert.Nil(t, brErr)
+		assert.Equal(t, expected, *msg.(jms20subset.TextMessage).GetText())
+	}
+
+	msg, brErr = iter.GetNext()
+	assert.Nil(t, brErr)
+	assert.Nil(t, msg)
+	assert.Equal(t, 3, cf.QueueManager.Depth("DEV.QUEUE.1"))
+
+	browser.Close()
+	_, brErr = iter.GetNext()
+	assert.NotNil(t, brErr)
+	assert.Equal(t, ConsumerImpl_CONSUMER_CLOSED_REASON, brErr.GetReason())
+
+}
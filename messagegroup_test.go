@@ -13,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
 	"github.com/zemlya25/mq-golang-jms20/mqjms"
 )
 
@@ -84,7 +85,8 @@ func TestMessageGroup(t *testing.T) {
 		 * Setting these properties requires an MQMD V2 header and is also
 		 * not supported for PUT1 operations so there is some more extensive
 		 * implementation work required in order to enable the "set" scenarios
-		 * for these Group properties.
+		 * for these Group properties. Applications can send groups using
+		 * mqjms.GroupProducer instead, as shown in TestGroupProducer below.
 
 		// Create a TextMessage and check that we can populate it
 		txtMsg1 := context.CreateTextMessage()
@@ -150,3 +152,75 @@ func TestMessageGroup(t *testing.T) {
 	*/
 
 }
+
+/*
+ * Test sending message groups using a GroupProducer, which has the queue
+ * manager assign the group properties, and receiving them as a whole.
+ */
+func TestGroupProducer(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// Set up objects for send/receive
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+	assert.Nil(t, errCons)
+
+	groupProducer, errProd := mqjms.NewGroupProducer(context, queue)
+	assert.Nil(t, errProd)
+	defer groupProducer.Close()
+
+	// Send the first two messages of the group, which cannot be received as a
+	// group until the last message has been sent.
+	msgBodies := []string{"first", "second", "third"}
+
+	errSend := groupProducer.Send(context.CreateTextMessageWithString(msgBodies[0]))
+	assert.Nil(t, errSend)
+	errSend = groupProducer.Send(context.CreateTextMessageWithString(msgBodies[1]))
+	assert.Nil(t, errSend)
+
+	groupID := groupProducer.GetGroupID()
+	assert.NotEqual(t, "", groupID)
+
+	group, errRcv := consumer.(mqjms.ConsumerImpl).ReceiveGroup(100)
+	assert.Nil(t, errRcv)
+	assert.Nil(t, group)
+
+	errSend = groupProducer.SendLast(context.CreateTextMessageWithString(msgBodies[2]))
+	assert.Nil(t, errSend)
+	assert.Equal(t, groupID, groupProducer.GetGroupID())
+
+	// Receive the whole group, and check its messages are in order.
+	group, errRcv = consumer.(mqjms.ConsumerImpl).ReceiveGroup(1000)
+	assert.Nil(t, errRcv)
+	assert.Equal(t, len(msgBodies), len(group))
+
+	for i, rcvMsg := range group {
+
+		assert.Equal(t, msgBodies[i], *rcvMsg.(jms20subset.TextMessage).GetText())
+
+		gotGroupIDValue, gotErr := rcvMsg.GetStringProperty("JMSXGroupID")
+		assert.Nil(t, gotErr)
+		assert.Equal(t, groupID, *gotGroupIDValue)
+		gotSeqValue, gotErr := rcvMsg.GetIntProperty("JMSXGroupSeq")
+		assert.Nil(t, gotErr)
+		assert.Equal(t, i+1, gotSeqValue)
+		gotLastMsgValue, gotErr := rcvMsg.GetBooleanProperty("JMS_IBM_Last_Msg_In_Group")
+		assert.Nil(t, gotErr)
+		assert.Equal(t, i == len(msgBodies)-1, gotLastMsgValue)
+	}
+
+}
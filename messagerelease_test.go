@@ -0,0 +1,235 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+/*
+ * Test that the properties of a message cannot be used once it has been released.
+ */
+func TestMessageRelease(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// Check no message on the queue to start with
+	testMsg, err := consumer.ReceiveNoWait()
+	assert.Nil(t, err)
+	assert.Nil(t, testMsg)
+
+	propName := "myProperty"
+	propValue := "myValue"
+
+	txtMsg := context.CreateTextMessageWithString("ReleaseMsg")
+	propErr := txtMsg.SetStringProperty(propName, &propValue)
+	assert.Nil(t, propErr)
+
+	errSend := context.CreateProducer().Send(queue, txtMsg)
+	assert.Nil(t, errSend)
+
+	// The body and header fields are still available after a message is
+	// released, but the properties are not.
+	txtMsg.Release()
+	assert.Equal(t, "ReleaseMsg", *txtMsg.GetText())
+
+	_, propErr = txtMsg.GetStringProperty(propName)
+	assert.NotNil(t, propErr)
+	assert.Equal(t, "2460", propErr.GetErrorCode())
+	assert.Equal(t, "MQRC_HMSG_ERROR", propErr.GetReason())
+
+	// A released message cannot be sent again.
+	errSend = context.CreateProducer().Send(queue, txtMsg)
+	assert.NotNil(t, errSend)
+	assert.Equal(t, "MQRC_HMSG_ERROR", errSend.GetReason())
+
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	gotValue, propErr := rcvMsg.GetStringProperty(propName)
+	assert.Nil(t, propErr)
+	assert.Equal(t, propValue, *gotValue)
+
+	rcvMsg.Release()
+
+	_, propErr = rcvMsg.GetPropertyNames()
+	assert.NotNil(t, propErr)
+	assert.Equal(t, "MQRC_HMSG_ERROR", propErr.GetReason())
+
+	// Releasing a message more than once has no further effect.
+	rcvMsg.Release()
+
+	// Nothing else is left on the queue.
+	rcvMsg, errRcv = consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.Nil(t, rcvMsg)
+
+}
+
+/*
+ * Test that message handles that are reused from the pool do not carry over
+ * the properties of the message that held them before.
+ */
+func TestMessageHandlePool(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	cf.MessageHandlePoolSize = 2
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	producer := context.CreateProducer()
+
+	for i := 0; i < 10; i++ {
+
+		txtMsg := context.CreateTextMessageWithString("PoolMsg" + strconv.Itoa(i))
+
+		// Only some of the messages have a property set, so that a leftover
+		// property from an earlier message would be noticed.
+		if i%2 == 0 {
+			propErr := txtMsg.SetIntProperty("MessageNumber", i)
+			assert.Nil(t, propErr)
+		}
+
+		errSend := producer.Send(queue, txtMsg)
+		assert.Nil(t, errSend)
+		txtMsg.Release()
+
+		rcvMsg, errRcv := consumer.ReceiveNoWait()
+		assert.Nil(t, errRcv)
+		assert.NotNil(t, rcvMsg)
+
+		switch msg := rcvMsg.(type) {
+		case jms20subset.TextMessage:
+			assert.Equal(t, "PoolMsg"+strconv.Itoa(i), *msg.GetText())
+		default:
+			assert.Fail(t, "Got something other than a text message")
+		}
+
+		exists, propErr := rcvMsg.PropertyExists("MessageNumber")
+		assert.Nil(t, propErr)
+		assert.Equal(t, i%2 == 0, exists)
+
+		rcvMsg.Release()
+	}
+
+}
+
+/*
+ * Benchmark sending and receiving messages with and without a message handle
+ * pool, for messages with no properties, a few properties, and more properties
+ * than are cleared from a handle for it to be reused.
+ */
+func BenchmarkMessageHandlePool(b *testing.B) {
+
+	for _, poolSize := range []int{0, 10} {
+		for _, properties := range []int{0, 4, 20} {
+
+			name := "pool=" + strconv.Itoa(poolSize) + "/properties=" + strconv.Itoa(properties)
+			b.Run(name, func(b *testing.B) {
+				benchmarkMessageHandlePool(b, poolSize, properties)
+			})
+		}
+	}
+
+}
+
+func benchmarkMessageHandlePool(b *testing.B, poolSize int, properties int) {
+
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(b, cfErr)
+
+	cf.MessageHandlePoolSize = poolSize
+
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(b, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(b, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	producer := context.CreateProducer()
+
+	propNames := make([]string, properties)
+	for i := range propNames {
+		propNames[i] = "Property" + strconv.Itoa(i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+
+		txtMsg := context.CreateTextMessageWithString("PoolMsg")
+		for j, propName := range propNames {
+			if propErr := txtMsg.SetIntProperty(propName, j); propErr != nil {
+				b.Fatal("Failed to set property", propErr)
+			}
+		}
+
+		if errSend := producer.Send(queue, txtMsg); errSend != nil {
+			b.Fatal("Failed to send message", errSend)
+		}
+		txtMsg.Release()
+
+		rcvMsg, errRcv := consumer.ReceiveNoWait()
+		if errRcv != nil || rcvMsg == nil {
+			b.Fatal("Failed to receive message", errRcv)
+		}
+		rcvMsg.Release()
+	}
+
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+/*
+ * Test that sends, receives, transactions and errors are reported to the
+ * metrics, and written out in the Prometheus text format.
+ */
+func TestMetrics(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	metrics := mqjms.NewPrometheusMetrics()
+	cf.Metrics = metrics
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContextWithSessionMode(jms20subset.JMSContextSESSIONTRANSACTED)
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// Send two messages and receive them again.
+	producer := context.CreateProducer()
+	errSend := producer.SendString(queue, "Metrics1")
+	assert.Nil(t, errSend)
+	errSend = producer.SendBytes(queue, []byte{1, 2, 3, 4})
+	assert.Nil(t, errSend)
+	errCommit := context.Commit()
+	assert.Nil(t, errCommit)
+
+	for i := 0; i < 2; i++ {
+		rcvMsg, errRcv := consumer.ReceiveNoWait()
+		assert.Nil(t, errRcv)
+		assert.NotNil(t, rcvMsg)
+	}
+
+	// Finding no message is not counted as an error.
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.Nil(t, rcvMsg)
+
+	errRollback := context.Rollback()
+	assert.Nil(t, errRollback)
+
+	// Sending to a queue that does not exist is counted as an error.
+	errSend = producer.SendString(context.CreateQueue("DOESNT.EXIST"), "Metrics3")
+	assert.NotNil(t, errSend)
+
+	// Tidy up the messages that were rolled back onto the queue.
+	for i := 0; i < 2; i++ {
+		consumer.ReceiveNoWait()
+	}
+	context.Commit()
+
+	var out bytes.Buffer
+	_, writeErr := metrics.WriteTo(&out)
+	assert.Nil(t, writeErr)
+	text := out.String()
+
+	assert.Contains(t, text, "# TYPE mqjms_messages_sent_total counter\n")
+	assert.Contains(t, text, "mqjms_messages_sent_total{queue=\"DEV.QUEUE.1\"} 2\n")
+	assert.Contains(t, text, "mqjms_message_bytes_sent_total{queue=\"DEV.QUEUE.1\"} 12\n")
+	assert.Contains(t, text, "mqjms_messages_received_total{queue=\"DEV.QUEUE.1\"} 4\n")
+	assert.Contains(t, text, "mqjms_message_bytes_received_total{queue=\"DEV.QUEUE.1\"} 24\n")
+	assert.Contains(t, text, "mqjms_mqi_errors_total{verb=\"MQPUT1\",mqrc=\"2085\",reason=\"MQRC_UNKNOWN_OBJECT_NAME\"} 1\n")
+	assert.NotContains(t, text, "mqrc=\"2033\"")
+	assert.Contains(t, text, "mqjms_commits_total 2\n")
+	assert.Contains(t, text, "mqjms_rollbacks_total 1\n")
+	assert.Contains(t, text, "# TYPE mqjms_put_latency_seconds histogram\n")
+	assert.Contains(t, text, "mqjms_put_latency_seconds_count{queue=\"DEV.QUEUE.1\"} 2\n")
+	assert.Contains(t, text, "mqjms_put_latency_seconds_count{queue=\"DOESNT.EXIST\"} 1\n")
+	assert.Contains(t, text, "mqjms_get_latency_seconds_count{queue=\"DEV.QUEUE.1\"} 4\n")
+
+}
+
+/*
+ * Test the Prometheus text format written by PrometheusMetrics, without
+ * needing a queue manager.
+ */
+func TestPrometheusMetricsFormat(t *testing.T) {
+
+	metrics := mqjms.NewPrometheusMetrics(0.25, 1)
+
+	metrics.MessageSent("Q\"1", 10)
+	metrics.AsyncPutStatus(1, 2)
+	metrics.GetLatency("Q2", 125*time.Millisecond)
+	metrics.GetLatency("Q2", 500*time.Millisecond)
+	metrics.GetLatency("Q2", 2*time.Second)
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+	text := recorder.Body.String()
+
+	assert.Contains(t, text, "mqjms_messages_sent_total{queue=\"Q\\\"1\"} 1\n")
+	assert.Contains(t, text, "mqjms_async_put_warnings_total 1\n")
+	assert.Contains(t, text, "mqjms_async_put_failures_total 2\n")
+	assert.Contains(t, text, "mqjms_get_latency_seconds_bucket{queue=\"Q2\",le=\"0.25\"} 1\n")
+	assert.Contains(t, text, "mqjms_get_latency_seconds_bucket{queue=\"Q2\",le=\"1\"} 2\n")
+	assert.Contains(t, text, "mqjms_get_latency_seconds_bucket{queue=\"Q2\",le=\"+Inf\"} 3\n")
+	assert.Contains(t, text, "mqjms_get_latency_seconds_sum{queue=\"Q2\"} 2.625\n")
+	assert.Contains(t, text, "mqjms_get_latency_seconds_count{queue=\"Q2\"} 3\n")
+
+}
@@ -10,6 +10,8 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
@@ -29,11 +31,11 @@ func TestMQConnectionOptions(t *testing.T) {
 		// Ensure that the options were applied when setting connection options on Context creation
 		msg := "options were not applied"
 		context, ctxErr := cf.CreateContext(
-			jms20subset.WithMaxMsgLength(2000),
-			func(cno *ibmmq.MQCNO) {
+			mqjms.WithMaxMsgLength(2000),
+			mqjms.MQCNOOption(func(cno *ibmmq.MQCNO) {
 				assert.Equal(t, int32(2000), cno.ClientConn.MaxMsgLength)
 				msg = "options applied"
-			},
+			}),
 		)
 		assert.Nil(t, ctxErr)
 
@@ -64,7 +66,7 @@ func TestMQConnectionOptions(t *testing.T) {
 		// create consumer with low msg length
 		rContext, ctxErr := cf.CreateContextWithSessionMode(
 			jms20subset.JMSContextAUTOACKNOWLEDGE,
-			jms20subset.WithMaxMsgLength(20),
+			mqjms.WithMaxMsgLength(20),
 		)
 		assert.Nil(t, ctxErr)
 		if rContext != nil {
@@ -91,4 +93,117 @@ func TestMQConnectionOptions(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, gotMsg)
 	})
+
+	t.Run("Channel tuning options are applied to the connection", func(t *testing.T) {
+		// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+		cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+		assert.Nil(t, cfErr)
+
+		var gotCNO ibmmq.MQCNO
+		var gotCD ibmmq.MQCD
+		context, ctxErr := cf.CreateContext(
+			mqjms.WithHeartbeatInterval(30),
+			mqjms.WithKeepAliveInterval(-1),
+			mqjms.WithSharingConversations(1),
+			mqjms.WithHeaderCompression(mqjms.Compress_SYSTEM, mqjms.Compress_NONE),
+			mqjms.WithMessageCompression(mqjms.Compress_LZ4FAST, mqjms.Compress_NONE),
+			mqjms.WithReconnect(mqjms.Reconnect_Q_MGR),
+			mqjms.WithApplName("options-test"),
+			mqjms.MQCNOOption(func(cno *ibmmq.MQCNO) {
+				gotCNO = *cno
+				gotCD = *cno.ClientConn
+			}),
+		)
+		assert.Nil(t, ctxErr)
+		if context != nil {
+			defer context.Close()
+		}
+
+		assert.Equal(t, int32(30), gotCD.HeartbeatInterval)
+		assert.Equal(t, ibmmq.MQKAI_AUTO, gotCD.KeepAliveInterval)
+		assert.Equal(t, int32(1), gotCD.SharingConversations)
+		assert.Equal(t, [2]int32{ibmmq.MQCOMPRESS_SYSTEM, ibmmq.MQCOMPRESS_NONE}, gotCD.HdrCompList)
+		assert.Equal(t, ibmmq.MQCOMPRESS_LZ4FAST, gotCD.MsgCompList[0])
+		assert.Equal(t, ibmmq.MQCOMPRESS_NONE, gotCD.MsgCompList[1])
+		assert.Equal(t, ibmmq.MQCOMPRESS_NOT_AVAILABLE, gotCD.MsgCompList[2])
+		assert.Equal(t, ibmmq.MQCNO_RECONNECT_Q_MGR, gotCNO.Options&ibmmq.MQCNO_RECONNECT_Q_MGR)
+		assert.Equal(t, "options-test", gotCNO.ApplName)
+	})
+
+	t.Run("Options that are not valid are rejected", func(t *testing.T) {
+		// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+		cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+		assert.Nil(t, cfErr)
+
+		invalidOptions := []mqjms.MQOptions{
+			mqjms.WithMaxMsgLength(-1),
+			mqjms.WithHeartbeatInterval(-5),
+			mqjms.WithKeepAliveInterval(100000),
+			mqjms.WithSharingConversations(-1),
+			mqjms.WithHeaderCompression(mqjms.Compress_LZ4HIGH),
+			mqjms.WithMessageCompression(),
+			mqjms.WithReconnect(99),
+			mqjms.WithApplName("a name that is much too long for MQ"),
+			mqjms.WithCCDTURL(""),
+		}
+
+		for _, option := range invalidOptions {
+			context, ctxErr := cf.CreateContext(option)
+			assert.Nil(t, context)
+			assert.NotNil(t, ctxErr)
+			if ctxErr != nil {
+				assert.Equal(t, mqjms.ConnectionFactoryImpl_INVALID_OPTION_REASON, ctxErr.GetReason())
+
+				var optErr *mqjms.MQOptionsError
+				assert.True(t, errors.As(ctxErr, &optErr))
+			}
+		}
+	})
+
+	t.Run("Client options can be applied to a bindings connection", func(t *testing.T) {
+		cno := ibmmq.NewMQCNO()
+		assert.Nil(t, mqjms.WithMaxMsgLength(2000).Apply(cno))
+		assert.Nil(t, mqjms.WithHeartbeatInterval(30).Apply(cno))
+		assert.Nil(t, mqjms.WithApplName("bindings-app").Apply(cno))
+		assert.Nil(t, cno.ClientConn)
+		assert.Equal(t, "bindings-app", cno.ApplName)
+	})
+
+	t.Run("Invalid options report the problem when they are applied", func(t *testing.T) {
+		cno := ibmmq.NewMQCNO()
+		cno.ClientConn = ibmmq.NewMQCD()
+		cno.ClientConn.MaxMsgLength = 1234
+
+		err := mqjms.WithMaxMsgLength(-1).Apply(cno)
+		var optErr *mqjms.MQOptionsError
+		assert.True(t, errors.As(err, &optErr))
+		if optErr != nil {
+			assert.Equal(t, "WithMaxMsgLength", optErr.Option)
+		}
+		assert.Equal(t, int32(1234), cno.ClientConn.MaxMsgLength)
+
+		err = mqjms.WithMaxMsgLength(2000).Apply(ibmmq.NewMQCD())
+		assert.True(t, errors.As(err, &optErr))
+		assert.Equal(t, int32(1234), cno.ClientConn.MaxMsgLength)
+	})
+
+	t.Run("Options for other providers are rejected", func(t *testing.T) {
+		// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+		cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+		assert.Nil(t, cfErr)
+
+		context, ctxErr := cf.CreateContext(otherProviderOption{})
+		assert.Nil(t, context)
+		assert.NotNil(t, ctxErr)
+		if ctxErr != nil {
+			assert.Equal(t, mqjms.ConnectionFactoryImpl_INVALID_OPTION_REASON, ctxErr.GetReason())
+		}
+	})
+}
+
+// otherProviderOption is a connection option for a provider other than IBM MQ.
+type otherProviderOption struct{}
+
+func (o otherProviderOption) Apply(target interface{}) error {
+	return fmt.Errorf("cannot be applied to %T", target)
 }
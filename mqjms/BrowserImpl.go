@@ -10,6 +10,9 @@
 package mqjms
 
 import (
+	"context"
+	"time"
+
 	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
 	"github.com/zemlya25/mq-golang-jms20/jms20subset"
 )
@@ -43,7 +46,7 @@ func (browser *BrowserImpl) GetNext() (jms20subset.Message, jms20subset.JMSExcep
 	gmo := ibmmq.NewMQGMO()
 	gmo.Options |= *browser.browseOption
 
-	msg, err := browser.receiveInternal(gmo)
+	msg, err := browser.receiveInternal(context.Background(), time.Now(), gmo, nil, nil)
 
 	if err == nil {
 		// After we have browsed the first message successfully we move on to asking
@@ -0,0 +1,385 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileEnv is the environment variable that names the configuration file
+// to be read by LoadConnectionFactory if the application does not specify one.
+const ConfigFileEnv = "MQ_CONFIG_FILE"
+
+// configSecretFileSuffix is added to the name of a secret setting in the
+// configuration file (for example passwordFile), and configSecretEnvSuffix to
+// its environment variable (for example MQ_PASSWORD_FILE), to give the path of
+// a file from which the value is read, such as a mounted Kubernetes secret.
+const (
+	configSecretFileSuffix = "File"
+	configSecretEnvSuffix  = "_FILE"
+)
+
+// configSetting describes one of the attributes of a ConnectionFactoryImpl that
+// can be set by LoadConnectionFactory.
+type configSetting struct {
+	key    string // Name of the setting in the configuration file
+	env    string // Name of the environment variable
+	secret bool   // Whether the value can also be read from a file
+	set    func(cf *ConnectionFactoryImpl, value string) error
+}
+
+// configSettings is the list of the settings that are understood by
+// LoadConnectionFactory. The Logger, Tracer, Metrics and CredentialsProvider
+// attributes are objects supplied by the application, so they cannot be set
+// from configuration.
+var configSettings = []configSetting{
+	{key: "queueManager", env: "MQ_QMGR", set: func(cf *ConnectionFactoryImpl, v string) error {
+		cf.QMName = v
+		return nil
+	}},
+	{key: "hostname", env: "MQ_HOSTNAME", set: func(cf *ConnectionFactoryImpl, v string) error {
+		cf.Hostname = v
+		return nil
+	}},
+	{key: "port", env: "MQ_PORT", set: func(cf *ConnectionFactoryImpl, v string) error {
+		return setConfigInt(&cf.PortNumber, v)
+	}},
+	{key: "channel", env: "MQ_CHANNEL", set: func(cf *ConnectionFactoryImpl, v string) error {
+		cf.ChannelName = v
+		return nil
+	}},
+	{key: "username", env: "MQ_USERNAME", set: func(cf *ConnectionFactoryImpl, v string) error {
+		cf.UserName = v
+		return nil
+	}},
+	{key: "password", env: "MQ_PASSWORD", secret: true, set: func(cf *ConnectionFactoryImpl, v string) error {
+		cf.Password = v
+		return nil
+	}},
+	{key: "token", env: "MQ_TOKEN", secret: true, set: func(cf *ConnectionFactoryImpl, v string) error {
+		cf.Token = v
+		return nil
+	}},
+	{key: "transportType", env: "MQ_TRANSPORT_TYPE", set: func(cf *ConnectionFactoryImpl, v string) error {
+		switch strings.ToUpper(v) {
+		case "CLIENT":
+			cf.TransportType = TransportType_CLIENT
+		case "BINDINGS":
+			cf.TransportType = TransportType_BINDINGS
+		default:
+			return errors.New("must be CLIENT or BINDINGS")
+		}
+		return nil
+	}},
+	{key: "tlsCipherSpec", env: "MQ_TLS_CIPHER_SPEC", set: func(cf *ConnectionFactoryImpl, v string) error {
+		cf.TLSCipherSpec = v
+		return nil
+	}},
+	{key: "tlsClientAuth", env: "MQ_TLS_CLIENT_AUTH", set: func(cf *ConnectionFactoryImpl, v string) error {
+		switch strings.ToUpper(v) {
+		case TLSClientAuth_NONE, TLSClientAuth_REQUIRED:
+			cf.TLSClientAuth = strings.ToUpper(v)
+		default:
+			return errors.New("must be " + TLSClientAuth_NONE + " or " + TLSClientAuth_REQUIRED)
+		}
+		return nil
+	}},
+	{key: "keyRepository", env: "MQ_KEY_REPOSITORY", set: func(cf *ConnectionFactoryImpl, v string) error {
+		cf.KeyRepository = v
+		return nil
+	}},
+	{key: "certificateLabel", env: "MQ_CERTIFICATE_LABEL", set: func(cf *ConnectionFactoryImpl, v string) error {
+		cf.CertificateLabel = v
+		return nil
+	}},
+	{key: "keyRepoPassword", env: "MQ_KEY_REPO_PASSWORD", secret: true, set: func(cf *ConnectionFactoryImpl, v string) error {
+		cf.KeyRepoPassword = v
+		return nil
+	}},
+	{key: "tlsPeerName", env: "MQ_TLS_PEER_NAME", set: func(cf *ConnectionFactoryImpl, v string) error {
+		cf.TLSPeerName = v
+		return nil
+	}},
+	{key: "tlsKeyResetCount", env: "MQ_TLS_KEY_RESET_COUNT", set: func(cf *ConnectionFactoryImpl, v string) error {
+		return setConfigInt(&cf.TLSKeyResetCount, v)
+	}},
+	{key: "tlsFipsRequired", env: "MQ_TLS_FIPS_REQUIRED", set: func(cf *ConnectionFactoryImpl, v string) error {
+		return setConfigBool(&cf.TLSFipsRequired, v)
+	}},
+	{key: "tlsCertificateValPolicy", env: "MQ_TLS_CERTIFICATE_VAL_POLICY", set: func(cf *ConnectionFactoryImpl, v string) error {
+		switch strings.ToUpper(v) {
+		case TLSCertificateValPolicy_ANY, TLSCertificateValPolicy_RFC5280:
+			cf.TLSCertificateValPolicy = strings.ToUpper(v)
+		default:
+			return errors.New("must be " + TLSCertificateValPolicy_ANY + " or " + TLSCertificateValPolicy_RFC5280)
+		}
+		return nil
+	}},
+	{key: "applName", env: "MQ_APPL_NAME", set: func(cf *ConnectionFactoryImpl, v string) error {
+		cf.ApplName = v
+		return nil
+	}},
+	{key: "receiveBufferSize", env: "MQ_RECEIVE_BUFFER_SIZE", set: func(cf *ConnectionFactoryImpl, v string) error {
+		return setConfigInt(&cf.ReceiveBufferSize, v)
+	}},
+	{key: "segmentSize", env: "MQ_SEGMENT_SIZE", set: func(cf *ConnectionFactoryImpl, v string) error {
+		return setConfigInt(&cf.SegmentSize, v)
+	}},
+	{key: "sendCheckCount", env: "MQ_SEND_CHECK_COUNT", set: func(cf *ConnectionFactoryImpl, v string) error {
+		return setConfigInt(&cf.SendCheckCount, v)
+	}},
+	{key: "receiveWaitSlice", env: "MQ_RECEIVE_WAIT_SLICE", set: func(cf *ConnectionFactoryImpl, v string) error {
+		return setConfigInt(&cf.ReceiveWaitSlice, v)
+	}},
+	{key: "producerHandleCacheSize", env: "MQ_PRODUCER_HANDLE_CACHE_SIZE", set: func(cf *ConnectionFactoryImpl, v string) error {
+		return setConfigInt(&cf.ProducerHandleCacheSize, v)
+	}},
+	{key: "messageHandlePoolSize", env: "MQ_MESSAGE_HANDLE_POOL_SIZE", set: func(cf *ConnectionFactoryImpl, v string) error {
+		return setConfigInt(&cf.MessageHandlePoolSize, v)
+	}},
+	{key: "traceMQI", env: "MQ_TRACE_MQI", set: func(cf *ConnectionFactoryImpl, v string) error {
+		return setConfigBool(&cf.TraceMQI, v)
+	}},
+}
+
+// LoadConnectionFactory creates a ConnectionFactoryImpl whose attributes are
+// read from a JSON or YAML configuration file and from MQ_* environment
+// variables, so that the same application can be configured for different
+// environments without changing its code.
+//
+// The configuration file is named by the configFile parameter, or by the
+// MQ_CONFIG_FILE environment variable if the parameter is empty. Its format is
+// chosen by the extension of the file name (.json, .yaml or .yml). If neither
+// is set then the attributes are only read from environment variables.
+//
+// Each setting is applied in the following order, so that later sources take
+// precedence over earlier ones;
+//   - the value in the configuration file, for example "port": 1414
+//   - the environment variable, for example MQ_PORT=1414
+//
+// Secret settings (password and token) can instead be read from a file, such as a mounted
+// Kubernetes secret, by giving its path in the configuration file with "File"
+// appended to the name of the setting (for example "passwordFile"), or in an
+// environment variable with _FILE appended (for example MQ_PASSWORD_FILE). The
+// value is only read from the file if the setting itself is not given by the
+// same source. Trailing newlines are removed from the contents of the file, and
+// settings with an empty value are ignored.
+//
+// See config-samples/connection_factory.yaml for all of the settings and the
+// names of their environment variables. An error is returned if the file
+// contains a setting that is not recognised, or if a value is not valid.
+func LoadConnectionFactory(configFile string) (ConnectionFactoryImpl, error) {
+
+	cf := ConnectionFactoryImpl{}
+
+	if configFile == "" {
+		configFile = os.Getenv(ConfigFileEnv)
+	}
+
+	// Apply the values from the configuration file first, so that they can be
+	// overridden by environment variables.
+	if configFile != "" {
+
+		values, err := readConfigFile(configFile)
+		if err != nil {
+			return ConnectionFactoryImpl{}, err
+		}
+
+		err = applyConfigSettings(&cf, mapLookup(values), false, configFile)
+		if err != nil {
+			return ConnectionFactoryImpl{}, err
+		}
+	}
+
+	err := applyConfigSettings(&cf, os.LookupEnv, true, "environment")
+	if err != nil {
+		return ConnectionFactoryImpl{}, err
+	}
+
+	return cf, nil
+}
+
+// applyConfigSettings sets the attributes of the ConnectionFactoryImpl from one
+// source of configuration. The lookup function is called with the name of the
+// setting in the configuration file, or with its environment variable if isEnv
+// is true. Settings with an empty value are treated as not being set.
+func applyConfigSettings(cf *ConnectionFactoryImpl, lookup func(string) (string, bool), isEnv bool, source string) error {
+
+	for _, setting := range configSettings {
+
+		name := setting.key
+		fileName := setting.key + configSecretFileSuffix
+		if isEnv {
+			name = setting.env
+			fileName = setting.env + configSecretEnvSuffix
+		}
+
+		value, _ := lookup(name)
+
+		if value == "" && setting.secret {
+			path, _ := lookup(fileName)
+
+			if path != "" {
+				content, err := ioutil.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("Unable to read %s from %s: %v", fileName, source, err)
+				}
+				value = strings.TrimRight(string(content), "\r\n")
+			}
+		}
+
+		if value == "" {
+			continue
+		}
+
+		err := setting.set(cf, value)
+		if err != nil {
+			return fmt.Errorf("Invalid value %q for %s in %s: %v", value, name, source, err)
+		}
+	}
+
+	return nil
+}
+
+// mapLookup returns a lookup function for applyConfigSettings that finds the
+// settings in a map.
+func mapLookup(values map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
+
+// readConfigFile reads the settings from a JSON or YAML configuration file,
+// converting each value to a string.
+func readConfigFile(configFile string) (map[string]string, error) {
+
+	raw, err := unmarshalConfigFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return configValues(raw, connectionFactoryKeys(), configFile)
+}
+
+// unmarshalConfigFile reads a JSON or YAML file, choosing the format from the
+// extension of the file name.
+func unmarshalConfigFile(configFile string) (map[string]interface{}, error) {
+
+	content, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+
+	switch strings.ToLower(filepath.Ext(configFile)) {
+	case ".json":
+		err = json.Unmarshal(content, &raw)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &raw)
+	default:
+		return nil, errors.New("Unable to determine the format of " + configFile + ", expected .json, .yaml or .yml")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failure during unmarshalling %s: %v", configFile, err)
+	}
+
+	return raw, nil
+}
+
+// connectionFactoryKeys returns the names of the settings of a ConnectionFactoryImpl
+// that can be given in a file.
+func connectionFactoryKeys() map[string]bool {
+
+	known := make(map[string]bool)
+	for _, setting := range configSettings {
+		known[setting.key] = true
+		if setting.secret {
+			known[setting.key+configSecretFileSuffix] = true
+		}
+	}
+
+	return known
+}
+
+// configValues converts the values of a set of settings that were read from a
+// file into strings, returning an error if there are any settings that are
+// not in the known set.
+func configValues(raw map[string]interface{}, known map[string]bool, source string) (map[string]string, error) {
+
+	values := make(map[string]string, len(raw))
+	var unknown []string
+
+	for key, value := range raw {
+
+		if !known[key] {
+			unknown = append(unknown, key)
+			continue
+		}
+
+		switch typed := value.(type) {
+		case nil:
+			// A setting with no value is treated as not being set.
+		case string:
+			values[key] = typed
+		case float64:
+			// JSON numbers are decoded as float64, which must not be formatted
+			// using an exponent.
+			values[key] = strconv.FormatFloat(typed, 'f', -1, 64)
+		case int, bool:
+			values[key] = fmt.Sprint(typed)
+		default:
+			return nil, fmt.Errorf("Invalid value for %s in %s", key, source)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("Unrecognised settings in %s: %s", source, strings.Join(unknown, ", "))
+	}
+
+	return values, nil
+}
+
+// setConfigInt parses an integer setting.
+func setConfigInt(target *int, value string) error {
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return errors.New("must be a whole number")
+	}
+
+	*target = i
+	return nil
+}
+
+// setConfigBool parses the value of a true/false setting.
+func setConfigBool(target *bool, value string) error {
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return errors.New("must be true or false")
+	}
+
+	*target = b
+	return nil
+}
@@ -31,6 +31,19 @@ type ConnectionFactoryImpl struct {
 	UserName    string
 	Password    string
 
+	// Token is a JSON Web Token (JWT) that is used to authenticate the connection
+	// instead of UserName and Password, if it is set. Requires an IBM MQ 9.3.4 (or
+	// later) client and a queue manager that is configured to trust the issuer.
+	Token string
+
+	// CredentialsProvider supplies the UserName, Password or Token each time a
+	// JMSContext is created, in place of the values above. This allows tokens
+	// and passwords that change over time to be used without restarting the
+	// application; see FileCredentialsProvider.
+	//
+	// Default of nil means that the values above are used.
+	CredentialsProvider CredentialsProvider
+
 	TransportType int // Default to TransportType_CLIENT (0)
 
 	// Equivalent to SSLCipherSpec and SSLClientAuth in the MQI client, however
@@ -39,15 +52,68 @@ type ConnectionFactoryImpl struct {
 	TLSCipherSpec string
 	TLSClientAuth string // Default to TLSClientAuth_NONE
 
+	// KeyRepository is the location of the key store holding the certificates
+	// used for TLS. For a CMS key database give the path without the .kdb
+	// extension, for example "./tls-samples/anon-tls", in which case the
+	// password is read from the matching .sth stash file. An MQ 9.3 (or later)
+	// client also accepts a PKCS#12 (.p12) or PEM (.pem) file, given as the
+	// full path including the extension.
 	KeyRepository    string
 	CertificateLabel string
 
+	// KeyRepoPassword is the password of the key repository, for key stores that
+	// do not have a stash file such as a PKCS#12 file or a PEM file with an
+	// encrypted private key.
+	KeyRepoPassword string
+
+	// TLSPeerName restricts the queue manager certificates that are accepted to
+	// those whose distinguished name matches this filter, for example
+	// "CN=QM1,O=My Company,C=GB". Equivalent to SSLPEER on the client channel
+	// definition; the connection fails with MQRC_SSL_PEER_NAME_MISMATCH if the
+	// certificate does not match.
+	TLSPeerName string
+
+	// TLSKeyResetCount is the number of bytes sent and received by the channel
+	// after which the secret key is renegotiated. Default of 0 (zero) means that
+	// the key is not renegotiated.
+	TLSKeyResetCount int
+
+	// TLSFipsRequired indicates that only FIPS-certified cryptography is to be
+	// used for the TLS connection.
+	TLSFipsRequired bool
+
+	// TLSCertificateValPolicy is the policy used to validate the certificates
+	// of the queue manager; one of TLSCertificateValPolicy_ANY (the default)
+	// or TLSCertificateValPolicy_RFC5280.
+	//
+	// Revocation checking using OCSP or CRLs can't be configured through the
+	// ConnectionFactory. The MQI configures it using authentication information
+	// records (MQAIR) in the MQSCO, but the ibmmq package always connects with
+	// no MQAIR records and has no field to supply them. Instead set it in the
+	// SSL stanza of the mqclient.ini file (OCSPAuthentication,
+	// OCSPCheckExtensions and CDPCheckExtensions), or in the AUTHINFO objects
+	// named by a client channel definition table.
+	TLSCertificateValPolicy string
+
 	// Allthough only available per MQ 9.1.2 it looks like a good idea to have this present in MQ-JMS
 	ApplName string
 
 	// Controls the size of the buffer used when receiving a message (default is 32kb if not set)
 	ReceiveBufferSize int
 
+	// SegmentSize enables segmentation of large messages by this library, for queue
+	// managers and channels that cannot carry them whole. Messages that are larger
+	// than this number of bytes are sent as a series of segments of at most this
+	// size, and segmented messages are reassembled by the library when they are
+	// received, so that the receive buffer (see ReceiveBufferSize) only needs to
+	// hold a single segment.
+	//
+	// SegmentSize_QUEUE_MANAGER allows messages that are too large for a queue to
+	// be segmented by the queue manager instead, and reassembled by it when they
+	// are received. Default of 0 (zero) means that messages are not segmented, so
+	// a message that is too large for a queue is rejected.
+	SegmentSize int
+
 	// SetCheckCount defines the number of messages that will be asynchronously put using
 	// this Context between checks for errors. For example a value of 10 will cause an error
 	// check to be triggered once for every 10 messages.
@@ -56,17 +122,76 @@ type ConnectionFactoryImpl struct {
 	//
 	// Default of 0 (zero) means that no checks are made for asynchronous put calls.
 	SendCheckCount int
+
+	// ReceiveWaitSlice is the longest time in milliseconds that a receive with a wait
+	// will spend inside a single MQGET call. Longer waits are made up of a series of
+	// these calls, and other goroutines are able to use the same JMSContext between
+	// them, so a smaller value reduces the time for which a waiting receive can hold
+	// up a send or commit, at the cost of more frequent calls to the queue manager.
+	//
+	// Default of 0 (zero) means ConsumerImpl_DEFAULT_RECEIVE_WAIT_SLICE_MILLIS.
+	ReceiveWaitSlice int
+
+	// ProducerHandleCacheSize defines the number of destinations that each JMSContext
+	// will keep open for sending messages. Sends to a destination that is held open use
+	// MQPUT on the existing handle instead of MQPUT1, which avoids opening and closing
+	// the queue for every message. When the limit is reached the least recently used
+	// destination is closed. All the destinations are closed when the JMSContext is closed.
+	//
+	// Default of 0 (zero) means that no destinations are held open, and MQPUT1 is used.
+	ProducerHandleCacheSize int
+
+	// MessageHandlePoolSize defines the number of message handles that each JMSContext
+	// will keep for reuse once the messages that held them have been released by calling
+	// Message.Release. Reusing a handle avoids the cost of creating a new handle for each
+	// message that is created or received. The handle of a message with more than a few
+	// properties is deleted rather than reused, as clearing the properties from it would
+	// cost more than creating a new handle.
+	//
+	// Default of 0 (zero) means that handles are deleted as soon as their message is released.
+	MessageHandlePoolSize int
+
+	// Logger receives the diagnostic messages written by the JMSContexts that are created
+	// from this ConnectionFactory, and the objects created from them.
+	//
+	// Default of nil means that warnings and errors are written to stderr.
+	Logger Logger
+
+	// TraceMQI enables a log message at LogLevelTrace for every call that is made to
+	// the queue manager, including the queue name and MQ reason code, to help with
+	// problem determination.
+	TraceMQI bool
+
+	// Tracer creates spans for the messages that are sent and received, and for
+	// commit and rollback, and carries the trace context between applications in
+	// the message properties named by TraceParentProperty and TraceStateProperty.
+	//
+	// Default of nil means that tracing is not enabled.
+	Tracer Tracer
+
+	// Metrics receives measurements of the messages sent and received, the latency
+	// of put and get calls, transactions and errors for the JMSContexts that are
+	// created from this ConnectionFactory. See NewPrometheusMetrics.
+	//
+	// Default of nil means that no measurements are taken.
+	Metrics Metrics
 }
 
 // CreateContext implements the JMS method to create a connection to an IBM MQ
 // queue manager.
-func (cf ConnectionFactoryImpl) CreateContext(mqos ...jms20subset.MQOptions) (jms20subset.JMSContext, jms20subset.JMSException) {
+func (cf ConnectionFactoryImpl) CreateContext(mqos ...jms20subset.ConnectionOption) (jms20subset.JMSContext, jms20subset.JMSException) {
 	return cf.CreateContextWithSessionMode(jms20subset.JMSContextAUTOACKNOWLEDGE, mqos...)
 }
 
 // CreateContextWithSessionMode implements the JMS method to create a connection to an IBM MQ
 // queue manager using the specified session mode.
-func (cf ConnectionFactoryImpl) CreateContextWithSessionMode(sessionMode int, mqos ...jms20subset.MQOptions) (jms20subset.JMSContext, jms20subset.JMSException) {
+func (cf ConnectionFactoryImpl) CreateContextWithSessionMode(sessionMode int, mqos ...jms20subset.ConnectionOption) (jms20subset.JMSContext, jms20subset.JMSException) {
+	return cf.createContextInternal(sessionMode, mqos)
+}
+
+// createMQCNO populates the MQI connection options from the attributes of this
+// ConnectionFactory, and then applies any MQOptions supplied by the application.
+func (cf ConnectionFactoryImpl) createMQCNO(mqos []jms20subset.ConnectionOption) (*ibmmq.MQCNO, jms20subset.JMSException) {
 
 	// Allocate the internal structures required to create an connection to IBM MQ.
 	cno := ibmmq.NewMQCNO()
@@ -97,15 +222,39 @@ func (cf ConnectionFactoryImpl) CreateContextWithSessionMode(sessionMode int, mq
 			cd.SSLClientAuth = -1 // Trigger an error message
 		}
 
-		// Set up the reference to the key repository file, if it has been specified.
-		if cf.KeyRepository != "" {
+		if cf.TLSPeerName != "" {
+			cd.SSLPeerName = cf.TLSPeerName
+		}
+
+		// Set up the reference to the key repository file, and the other TLS
+		// configuration options, if any of them have been specified.
+		if cf.KeyRepository != "" || cf.KeyRepoPassword != "" || cf.TLSKeyResetCount != 0 ||
+			cf.TLSFipsRequired || cf.TLSCertificateValPolicy != "" {
+
 			sco := ibmmq.NewMQSCO()
 			sco.KeyRepository = cf.KeyRepository
+			sco.KeyRepoPassword = cf.KeyRepoPassword
 
 			if cf.CertificateLabel != "" {
 				sco.CertificateLabel = cf.CertificateLabel
 			}
 
+			if cf.TLSKeyResetCount != 0 {
+				sco.KeyResetCount = int32(cf.TLSKeyResetCount)
+			}
+
+			sco.FipsRequired = cf.TLSFipsRequired
+
+			switch cf.TLSCertificateValPolicy {
+			case TLSCertificateValPolicy_ANY:
+				sco.CertificateValPolicy = ibmmq.MQ_CERT_VAL_POLICY_ANY
+			case TLSCertificateValPolicy_RFC5280:
+				sco.CertificateValPolicy = ibmmq.MQ_CERT_VAL_POLICY_RFC5280
+			case "":
+			default:
+				sco.CertificateValPolicy = -1 // Trigger an error message
+			}
+
 			cno.SSLConfig = sco
 
 		}
@@ -120,21 +269,76 @@ func (cf ConnectionFactoryImpl) CreateContextWithSessionMode(sessionMode int, mq
 
 	}
 
-	if cf.UserName != "" {
+	creds := Credentials{
+		UserName: cf.UserName,
+		Password: cf.Password,
+		Token:    cf.Token,
+	}
+
+	// Ask the provider for the current credentials, as they may have changed
+	// since the last connection was made.
+	if cf.CredentialsProvider != nil {
+		var err error
+		creds, err = cf.CredentialsProvider.Credentials()
+		if err != nil {
+			return nil, jms20subset.CreateSecurityException(ConnectionFactoryImpl_CREDENTIALS_REASON, "Security", err)
+		}
+	}
+
+	if creds.Token != "" {
+
+		// Authenticate using the token rather than a user ID and password.
+		csp := ibmmq.NewMQCSP()
+		csp.AuthenticationType = ibmmq.MQCSP_AUTH_ID_TOKEN
+		csp.Token = creds.Token
+		cno.SecurityParms = csp
+
+	} else if creds.UserName != "" {
 
 		// Store the user credentials in an MQCSP, which ensures that long passwords
 		// can be used.
 		csp := ibmmq.NewMQCSP()
 		csp.AuthenticationType = ibmmq.MQCSP_AUTH_USER_ID_AND_PWD
-		csp.UserId = cf.UserName
-		csp.Password = cf.Password
+		csp.UserId = creds.UserName
+		csp.Password = creds.Password
 		cno.SecurityParms = csp
 
 	}
 
 	// Apply options
-	for _, mqo := range mqos {
-		mqo(cno)
+	optErr := applyMQOptions(cno, mqos)
+	if optErr != nil {
+		return nil, jms20subset.CreateJMSException(ConnectionFactoryImpl_INVALID_OPTION_REASON, ConnectionFactoryImpl_INVALID_OPTION_CODE, optErr)
+	}
+
+	return cno, nil
+}
+
+// SegmentSize_QUEUE_MANAGER is the SegmentSize that allows the queue manager to
+// segment messages that are too large for a queue.
+const SegmentSize_QUEUE_MANAGER int = -1
+
+// ConnectionFactoryImpl_CREDENTIALS_REASON is the reason of the exception that
+// is returned when the CredentialsProvider fails to supply the credentials.
+const ConnectionFactoryImpl_CREDENTIALS_REASON = "MQJMS_E_CREDENTIALS_UNAVAILABLE"
+
+// ConnectionFactoryImpl_INVALID_OPTION_REASON is the reason of the exception that
+// is returned when one of the MQOptions was given a value that is not valid,
+// or an option for a different provider was supplied.
+const ConnectionFactoryImpl_INVALID_OPTION_REASON = "MQJMS_E_INVALID_OPTION"
+
+// ConnectionFactoryImpl_INVALID_OPTION_CODE is the error code of the exception that
+// is returned when one of the MQOptions was given a value that is not valid,
+// or an option for a different provider was supplied.
+const ConnectionFactoryImpl_INVALID_OPTION_CODE = "InvalidOption"
+
+// createContextInternal connects to the queue manager and wraps the resulting
+// connection handle in a ContextImpl.
+func (cf ConnectionFactoryImpl) createContextInternal(sessionMode int, mqos []jms20subset.ConnectionOption) (jms20subset.JMSContext, jms20subset.JMSException) {
+
+	cno, cnoErr := cf.createMQCNO(mqos)
+	if cnoErr != nil {
+		return nil, cnoErr
 	}
 
 	var ctx jms20subset.JMSContext
@@ -142,38 +346,96 @@ func (cf ConnectionFactoryImpl) CreateContextWithSessionMode(sessionMode int, mq
 
 	// Use the objects that we have configured to create a connection to the
 	// queue manager.
-	qMgr, err := ibmmq.Connx(cf.QMName, cno)
+	qMgr, err := connectMQI(cf.QMName, cno)
 
-	if err == nil {
+	logger := loggerOrDefault(cf.Logger)
+	traceMQICall(logger, cf.TraceMQI, cf.QMName, "MQCONNX", "", err)
 
-		// Initialize the countInc value to 1 so that if CheckCount is enabled (>0)
-		// then an error check will be made after the first message - to catch any
-		// failures quickly.
-		countInc := new(int)
-		*countInc = 1
+	metrics := metricsOrDefault(cf.Metrics)
+	recordMQIError(metrics, "MQCONNX", err)
+
+	if err == nil {
 
 		// Connection was created successfully, so we wrap the MQI object into
 		// a new ContextImpl and return it to the caller.
-		ctx = ContextImpl{
-			qMgr:              qMgr,
-			ctxLock:           &sync.Mutex{},
-			sessionMode:       sessionMode,
-			receiveBufferSize: cf.ReceiveBufferSize,
-			sendCheckCount:    cf.SendCheckCount,
-			sendCheckCountInc: countInc,
-		}
+		ctx = cf.newContext(qMgr, &sync.Mutex{}, &connectionImpl{}, sessionMode, mqos)
 
 	} else {
 
 		// The underlying MQI call returned an error, so extract the relevant
 		// details and pass it back to the caller as a JMSException
-		rcInt := int(err.(*ibmmq.MQReturn).MQRC)
-		errCode := strconv.Itoa(rcInt)
-		reason := ibmmq.MQItoString("RC", rcInt)
-		retErr = jms20subset.CreateJMSException(reason, errCode, err)
+		retErr = createJMSExceptionFromMQReturn(err)
 
 	}
 
 	return ctx, retErr
 
 }
+
+// newContext creates a ContextImpl that uses the specified connection handle,
+// together with the lock that serialises calls to the queue manager using it.
+// The connection handle may be shared with other contexts that were created
+// from the same JMSContext using CreateContext.
+//
+// The context lock must be held if the connection handle is already in use.
+func (cf ConnectionFactoryImpl) newContext(qMgr mqiQueueManager, ctxLock *sync.Mutex, conn *connectionImpl, sessionMode int, mqos []jms20subset.ConnectionOption) ContextImpl {
+
+	// Initialize the countInc value to 1 so that if CheckCount is enabled (>0)
+	// then an error check will be made after the first message - to catch any
+	// failures quickly.
+	countInc := new(int)
+	*countInc = 1
+
+	receiveWaitSlice := ConsumerImpl_DEFAULT_RECEIVE_WAIT_SLICE_MILLIS
+	if cf.ReceiveWaitSlice > 0 {
+		receiveWaitSlice = int32(cf.ReceiveWaitSlice)
+	}
+
+	receiveBufferSize := 32768
+	if cf.ReceiveBufferSize > 0 {
+		receiveBufferSize = cf.ReceiveBufferSize
+	}
+
+	// Buffers used to receive messages are reused from one receive to the
+	// next, rather than allocating a new one for every message.
+	bufferPool := &sync.Pool{
+		New: func() interface{} {
+			buffer := make([]byte, receiveBufferSize)
+			return &buffer
+		},
+	}
+
+	var cache *handleCache
+	if cf.ProducerHandleCacheSize > 0 {
+		cache = newHandleCache(cf.ProducerHandleCacheSize)
+	}
+
+	logger := loggerOrDefault(cf.Logger)
+	handlePool := newMsgHandlePool(cf.MessageHandlePoolSize, ctxLock, logger)
+
+	conn.contexts++
+
+	return ContextImpl{
+		qMgr:              qMgr,
+		ctxLock:           ctxLock,
+		conn:              conn,
+		sessionMode:       sessionMode,
+		receiveBufferSize: receiveBufferSize,
+		bufferPool:        bufferPool,
+		segmentSize:       cf.SegmentSize,
+		receiveWaitSlice:  receiveWaitSlice,
+		sendCheckCount:    cf.SendCheckCount,
+		sendCheckCountInc: countInc,
+		factory:           cf,
+		mqos:              mqos,
+		handleCache:       cache,
+		handlePool:        handlePool,
+		logger:            logger,
+		mqiTrace:          cf.TraceMQI,
+		tracer:            cf.Tracer,
+		metrics:           metricsOrDefault(cf.Metrics),
+		state: &contextState{
+			consumers: make(map[*consumerState]mqiObject),
+		},
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// connectionImpl holds the state of a connection handle that is shared by a
+// JMSContext and every JMSContext that is created from it using CreateContext.
+//
+// An MQ connection handle has a single unit of work, so the contexts sharing it
+// also share their transaction scope. The connection handle is disconnected when
+// the last of the contexts is closed, so they can be closed in any order.
+//
+// The context lock, which is shared by the contexts as well, must be held to use it.
+type connectionImpl struct {
+	contexts    int           // Number of open contexts using the connection handle
+	unitOfWork  *contextState // Context sending or receiving a group in a unit of work of its own, or nil
+	uncommitted bool          // A transacted context may have work that is not yet committed
+}
+
+// checkOwnUnitOfWork is called with the context lock held before an operation on a
+// JMSContext that is not transacted carries out work in a unit of work of its own,
+// which it then commits or backs out. An error is returned if the unit of work of
+// the connection handle already contains other work, which would be completed too.
+func (ctx ContextImpl) checkOwnUnitOfWork() jms20subset.JMSException {
+
+	if ctx.conn.unitOfWork != nil || ctx.conn.uncommitted {
+		return createUnitOfWorkOpenException()
+	}
+
+	return nil
+}
+
+// checkTransactedWork is called with the context lock held before a transacted
+// JMSContext carries out work under its transaction. An error is returned if a
+// group is being sent or received in a unit of work of its own by a JMSContext
+// that shares the connection handle, as that would complete the work of the
+// transaction.
+func (ctx ContextImpl) checkTransactedWork() jms20subset.JMSException {
+
+	if ctx.conn.unitOfWork != nil {
+		return createUnitOfWorkOpenException()
+	}
+
+	return nil
+}
@@ -24,3 +24,27 @@ const TLSClientAuth_NONE string = "NONE"
 // TLSClientAuth_REQUIRED is used to configure the TLSClientAuth property to indicate that a client
 // certificate must be sent to the queue manager, as part of mutual TLS.
 const TLSClientAuth_REQUIRED string = "REQUIRED"
+
+// TLSCertificateValPolicy_ANY is used to configure the TLSCertificateValPolicy property
+// to validate queue manager certificates using any of the policies supported by the
+// MQ client. This is the default.
+const TLSCertificateValPolicy_ANY string = "ANY"
+
+// TLSCertificateValPolicy_RFC5280 is used to configure the TLSCertificateValPolicy property
+// to validate queue manager certificates strictly according to RFC 5280.
+const TLSCertificateValPolicy_RFC5280 string = "RFC5280"
+
+// Destination_AS_PRODUCER is used for the persistence and priority of a QueueImpl
+// to indicate that the setting of the JMSProducer is used, rather than the
+// destination overriding it. This is the default.
+const Destination_AS_PRODUCER int = -1
+
+// TargetClient_JMS is used for the target client of a QueueImpl to indicate that
+// the messages are sent with their JMS properties, for an application that uses
+// JMS or the message properties of MQ. This is the default.
+const TargetClient_JMS int = 0
+
+// TargetClient_MQ is used for the target client of a QueueImpl to indicate that
+// the messages are sent without their properties, for a non-JMS application that
+// expects only the message body.
+const TargetClient_MQ int = 1
@@ -10,12 +10,11 @@
 package mqjms
 
 import (
+	"context"
 	"errors"
-	"fmt"
-	"runtime"
-	"strconv"
+	"io"
 	"strings"
-	"sync"
+	"time"
 
 	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
 	"github.com/zemlya25/mq-golang-jms20/jms20subset"
@@ -25,8 +24,15 @@ import (
 // receiving messages from a queue on an IBM MQ queue manager.
 type ConsumerImpl struct {
 	ctx      ContextImpl
-	qObject  ibmmq.MQObject
+	qObject  mqiObject
 	selector string
+	state    *consumerState // Shared by all copies of this ConsumerImpl
+}
+
+// consumerState holds the lifecycle state of a ConsumerImpl or BrowserImpl, which
+// is shared by every copy of the value. The context lock must be held to use it.
+type consumerState struct {
+	closed bool
 }
 
 // ReceiveNoWait implements the IBM MQ logic necessary to receive a message from
@@ -35,7 +41,7 @@ type ConsumerImpl struct {
 func (consumer ConsumerImpl) ReceiveNoWait() (jms20subset.Message, jms20subset.JMSException) {
 
 	gmo := ibmmq.NewMQGMO()
-	return consumer.receiveInternal(gmo)
+	return consumer.receiveInternal(context.Background(), time.Now(), gmo, nil, nil)
 
 }
 
@@ -43,45 +49,444 @@ func (consumer ConsumerImpl) ReceiveNoWait() (jms20subset.Message, jms20subset.J
 // waits for up to the specified number of milliseconds for one to become
 // available. A value of zero or less indicates to wait indefinitely.
 func (consumer ConsumerImpl) Receive(waitMillis int32) (jms20subset.Message, jms20subset.JMSException) {
+	return consumer.receiveWithWait(context.Background(), waitMillis, nil, nil)
+}
 
-	if waitMillis <= 0 {
-		waitMillis = ibmmq.MQWI_UNLIMITED
+// ReceiveContext returns a message if one is available, or otherwise waits
+// until one becomes available or the supplied Go context is cancelled or
+// reaches its deadline.
+func (consumer ConsumerImpl) ReceiveContext(ctx context.Context) (jms20subset.Message, jms20subset.JMSException) {
+	return consumer.receiveWithWait(ctx, 0, nil, nil)
+}
+
+// ReceiveInto receives a message in the same way as Receive, but uses the
+// supplied slice as the buffer into which the message is received instead of
+// allocating one.
+//
+// The body of a BytesMessage that is received by this method refers directly to
+// the supplied buffer, so the buffer must not be reused until the application
+// has finished with the message. The body of a TextMessage is copied into a new
+// string, so in that case the buffer can be reused immediately.
+//
+// If the message is larger than the buffer then an error is returned with the
+// reason MQRC_TRUNCATED_MSG_FAILED and the message remains on the queue. If the
+// message was segmented using the SegmentSize of the ConnectionFactory and the
+// JMSContext is transacted, then it remains on the queue once the transaction is
+// rolled back.
+func (consumer ConsumerImpl) ReceiveInto(buffer []byte, waitMillis int32) (jms20subset.Message, jms20subset.JMSException) {
+
+	if buffer == nil {
+		buffer = []byte{}
 	}
 
-	gmo := ibmmq.NewMQGMO()
-	gmo.Options |= ibmmq.MQGMO_WAIT
-	gmo.WaitInterval = waitMillis
+	return consumer.receiveWithWait(context.Background(), waitMillis, nil, buffer)
+}
+
+// receiveWithWait waits for up to waitMillis milliseconds for a message to
+// become available, or indefinitely if waitMillis is zero or less, returning
+// early with an error if the Go context is cancelled or reaches its deadline.
+//
+// The context lock must be held while an MQGET call is in progress, and a
+// blocked MQGET cannot be interrupted, so rather than making a single long call
+// the wait is carried out as a series of MQGET calls that each wait for a short
+// slice of time. The lock is released between the slices, which allows other
+// goroutines to send messages or commit using the same JMSContext, and the Go
+// context is checked before each slice.
+//
+// If groupID is not nil then the message is received as part of a message group,
+// as described for receiveInternal.
+func (consumer ConsumerImpl) receiveWithWait(ctx context.Context, waitMillis int32, groupID []byte, buffer []byte) (jms20subset.Message, jms20subset.JMSException) {
+
+	// The latency of the receive includes every slice of the wait.
+	receiveStart := time.Now()
+
+	var waitDeadline time.Time
+	if waitMillis > 0 {
+		waitDeadline = time.Now().Add(time.Duration(waitMillis) * time.Millisecond)
+	}
+
+	for {
+
+		if ctx.Err() != nil {
+			return nil, createContextDoneException(ctx.Err())
+		}
+
+		sliceMillis := consumer.ctx.receiveWaitSlice
+
+		// Don't wait beyond the time requested by the caller.
+		if !waitDeadline.IsZero() {
+
+			remainingMillis := time.Until(waitDeadline).Milliseconds()
+			if remainingMillis <= 0 {
+				// No message arrived during the requested wait.
+				return nil, nil
+			}
+
+			if remainingMillis < int64(sliceMillis) {
+				sliceMillis = int32(remainingMillis)
+			}
+		}
+
+		// Don't wait beyond the deadline of the Go context, if it has one.
+		if ctxDeadline, hasDeadline := ctx.Deadline(); hasDeadline {
+
+			remainingMillis := time.Until(ctxDeadline).Milliseconds()
+			if remainingMillis <= 0 {
+				return nil, createContextDoneException(context.DeadlineExceeded)
+			}
+
+			if remainingMillis < int64(sliceMillis) {
+				sliceMillis = int32(remainingMillis)
+			}
+		}
+
+		gmo := ibmmq.NewMQGMO()
+		gmo.Options |= ibmmq.MQGMO_WAIT
+		gmo.WaitInterval = sliceMillis
+
+		msg, jmsErr := consumer.receiveInternal(ctx, receiveStart, gmo, groupID, buffer)
+
+		// Keep waiting only if this slice completed without finding a message.
+		if msg != nil || jmsErr != nil {
+			return msg, jmsErr
+		}
+	}
+
+}
+
+// ReceiveChannel starts a goroutine that repeatedly receives messages from this
+// consumer and delivers them on the returned message channel, until the Go
+// context is cancelled or a receive call fails.
+//
+// Messages are only received while there is space in the channel buffer, which
+// provides backpressure towards the queue. Note that a message that has already
+// been received when the context is cancelled is not delivered on the channel,
+// so applications that must not lose such a message should use a transacted
+// JMSContext so that it is rolled back.
+func (consumer ConsumerImpl) ReceiveChannel(ctx context.Context, bufferSize int) (<-chan jms20subset.Message, <-chan jms20subset.JMSException) {
+
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+
+	msgChan := make(chan jms20subset.Message, bufferSize)
+
+	// Only one error is ever delivered, so buffer it to allow the goroutine
+	// to exit even if the application is not currently reading errors.
+	errChan := make(chan jms20subset.JMSException, 1)
+
+	go func() {
+
+		defer close(msgChan)
+		defer close(errChan)
+
+		for {
+
+			msg, jmsErr := consumer.ReceiveContext(ctx)
 
-	return consumer.receiveInternal(gmo)
+			if jmsErr != nil {
+
+				// Cancellation of the context is the normal way to stop the loop,
+				// so only report errors that have some other cause.
+				if ctx.Err() == nil {
+					errChan <- jmsErr
+				}
+				return
+			}
+
+			// Wait for space in the channel, which is the point at which the
+			// backpressure applies.
+			select {
+			case msgChan <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+	}()
+
+	return msgChan, errChan
 
 }
 
+// ReceiveGroup receives a whole message group, such as one sent using a
+// GroupProducer, and returns its messages in the order of their sequence numbers.
+//
+// Only a group whose messages are all on the queue is received, and if there is
+// no such group then ReceiveGroup waits for up to waitMillis milliseconds for
+// one to become complete, or indefinitely if waitMillis is zero or less. A nil
+// slice is returned if no complete group is available in that time. A message
+// that is not part of a group is returned on its own, as a group of one.
+//
+// If the JMSContext is transacted then the messages are received as part of its
+// transaction. Otherwise the group is received in a unit of work of its own that
+// is committed once the whole group has been received, so that a failure part
+// way through leaves the whole group on the queue. While it waits for and
+// receives the group, operations of this library on the same JMSContext that
+// need a unit of work of their own return an error with the reason
+// MQJMS_E_UNIT_OF_WORK_OPEN (see ReceiveStream).
+//
+// The queue manager keeps track of the position in the group on the handle of
+// the consumer, so a consumer must only receive one group at a time.
+func (consumer ConsumerImpl) ReceiveGroup(waitMillis int32) ([]jms20subset.Message, jms20subset.JMSException) {
+
+	transacted := consumer.ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED
+
+	if !transacted {
+		if jmsErr := consumer.ctx.beginUnitOfWork(); jmsErr != nil {
+			return nil, jmsErr
+		}
+	}
+
+	// A group ID of all zeros matches the first message of any complete group.
+	anyGroup := make([]byte, ibmmq.MQ_GROUP_ID_LENGTH)
+
+	msg, jmsErr := consumer.receiveWithWait(context.Background(), waitMillis, anyGroup, nil)
+	if msg == nil || jmsErr != nil {
+		if !transacted {
+			consumer.ctx.endUnitOfWork()
+		}
+		return nil, jmsErr
+	}
+
+	group := []jms20subset.Message{msg}
+	groupID := messageMQMD(msg).GroupId
+
+	// The rest of the group is already on the queue, so there is no need to wait.
+	for !isLastInGroup(messageMQMD(msg)) {
+
+		msg, jmsErr = consumer.receiveInternal(context.Background(), time.Now(), ibmmq.NewMQGMO(), groupID, nil)
+
+		if msg == nil && jmsErr == nil {
+			// Part of the group has been received by another consumer.
+			jmsErr = jms20subset.CreateJMSException(ConsumerImpl_GROUP_INCOMPLETE_REASON, ConsumerImpl_GROUP_INCOMPLETE_CODE, nil)
+		}
+
+		if jmsErr != nil {
+			releaseMessages(group)
+			if !transacted {
+				consumer.ctx.completeUnitOfWork(false)
+			}
+			return nil, jmsErr
+		}
+
+		group = append(group, msg)
+	}
+
+	if !transacted {
+		if jmsErr = consumer.ctx.completeUnitOfWork(true); jmsErr != nil {
+			releaseMessages(group)
+			return nil, jmsErr
+		}
+	}
+
+	return group, nil
+}
+
+// ReceiveStream receives data that was sent using SendStream, or a message group
+// sent in some other way, and returns a reader that receives the messages of the
+// group one at a time as the data is read. It waits for a complete group to be
+// available in the same way as ReceiveGroup, and a nil reader is returned if
+// there is none. A message that is not part of a group is received as a stream
+// containing its body.
+//
+// If the JMSContext is transacted then the group is received under the
+// transaction. Otherwise it is received in a unit of work of its own, which is
+// committed once all of the data has been read, when the reader returns io.EOF or
+// is closed, and backed out if the reader is closed before then or fails.
+// Operations that need the unit of work of the connection handle, such as
+// ReceiveGroup, another ReceiveStream, sending or receiving a message that the
+// library segments, or using a transacted JMSContext that shares the connection
+// handle (see CreateContext), return an error with the reason
+// MQJMS_E_UNIT_OF_WORK_OPEN until the reader is complete.
+//
+// The reader must be used by a single goroutine, and must always be closed.
+func (consumer ConsumerImpl) ReceiveStream(waitMillis int32) (io.ReadCloser, jms20subset.JMSException) {
+
+	transacted := consumer.ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED
+
+	if !transacted {
+		if jmsErr := consumer.ctx.beginUnitOfWork(); jmsErr != nil {
+			return nil, jmsErr
+		}
+	}
+
+	// A group ID of all zeros matches the first message of any complete group.
+	anyGroup := make([]byte, ibmmq.MQ_GROUP_ID_LENGTH)
+
+	msg, jmsErr := consumer.receiveWithWait(context.Background(), waitMillis, anyGroup, nil)
+	if msg == nil || jmsErr != nil {
+		if !transacted {
+			consumer.ctx.endUnitOfWork()
+		}
+		return nil, jmsErr
+	}
+
+	reader := &streamReader{consumer: consumer}
+	reader.setMessage(msg)
+
+	return reader, nil
+}
+
+// isLastInGroup returns whether a message is the last of its group, which is
+// also the case for a message that is not part of a group.
+func isLastInGroup(mqmd *ibmmq.MQMD) bool {
+	return mqmd.MsgFlags&ibmmq.MQMF_LAST_MSG_IN_GROUP != 0 ||
+		mqmd.MsgFlags&ibmmq.MQMF_MSG_IN_GROUP == 0
+}
+
+// releaseMessages releases every message in the slice.
+func releaseMessages(msgs []jms20subset.Message) {
+	for _, msg := range msgs {
+		msg.Release()
+	}
+}
+
+// moreSegments returns whether a message is a segment that is followed by
+// further segments of the same message.
+func moreSegments(mqmd *ibmmq.MQMD) bool {
+	return mqmd.MsgFlags&ibmmq.MQMF_SEGMENT != 0 &&
+		mqmd.MsgFlags&ibmmq.MQMF_LAST_SEGMENT == 0
+}
+
+// receiveSegments receives the remaining segments of the message whose first
+// segment has been received into getmqmd and body, and returns the body of the
+// whole message. The segments are received under syncpoint, in logical order if
+// the message is part of a group that is being received in logical order, and
+// otherwise by matching the group ID, sequence number and offset of each one.
+func (consumer ConsumerImpl) receiveSegments(getmqmd *ibmmq.MQMD, logical bool, buffer []byte, datalen int) ([]byte, error) {
+
+	// Copy the first segment out of the buffer, which is reused to receive each
+	// of the following segments.
+	body := append([]byte{}, buffer[:datalen]...)
+
+	segmentmqmd := getmqmd
+
+	for moreSegments(segmentmqmd) {
+
+		nextmqmd := ibmmq.NewMQMD()
+		nextmqmd.Version = ibmmq.MQMD_VERSION_2
+		nextmqmd.GroupId = getmqmd.GroupId
+
+		gmo := ibmmq.NewMQGMO()
+		gmo.Version = ibmmq.MQGMO_VERSION_2
+		gmo.Options = ibmmq.MQGMO_SYNCPOINT | ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_NO_PROPERTIES
+
+		if logical {
+			gmo.Options |= ibmmq.MQGMO_LOGICAL_ORDER
+			gmo.MatchOptions = ibmmq.MQMO_MATCH_GROUP_ID
+		} else {
+			gmo.MatchOptions = ibmmq.MQMO_MATCH_GROUP_ID | ibmmq.MQMO_MATCH_MSG_SEQ_NUMBER | ibmmq.MQMO_MATCH_OFFSET
+			nextmqmd.MsgSeqNumber = getmqmd.MsgSeqNumber
+			nextmqmd.Offset = int32(len(body))
+		}
+
+		datalen, err := consumer.qObject.Get(nextmqmd, gmo, nil, buffer)
+		consumer.ctx.traceMQI("MQGET", consumer.qObject.Name(), err)
+
+		// The segments were all on the queue, so a missing one means that it has
+		// been received by another consumer.
+		if mqret, ok := err.(*ibmmq.MQReturn); ok && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+			err = &ibmmq.MQReturn{MQCC: ibmmq.MQCC_FAILED, MQRC: ibmmq.MQRC_INCOMPLETE_MSG}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		body = append(body, buffer[:datalen]...)
+		segmentmqmd = nextmqmd
+	}
+
+	// The message is described as a whole rather than by its first segment.
+	getmqmd.MsgFlags &^= ibmmq.MQMF_SEGMENT | ibmmq.MQMF_LAST_SEGMENT
+
+	return body, nil
+}
+
 // Internal method to provide common functionality across the different types
 // of receive.
-func (consumer ConsumerImpl) receiveInternal(gmo *ibmmq.MQGMO) (jms20subset.Message, jms20subset.JMSException) {
+//
+// If a buffer is supplied by the caller then the message is received directly
+// into it. Otherwise a buffer is borrowed from the pool belonging to the context
+// and the message body is copied out of it into a slice of the right size, so
+// that the buffer can be returned to the pool and used again by the next receive.
+//
+// If groupID is not nil then the message is received in logical order as part of
+// a message group, under syncpoint so that the whole group can be backed out if
+// it cannot all be received. A groupID of all zeros receives the first message of
+// a group that is complete on the queue, and otherwise the next message of the
+// group with that ID is received.
+//
+// A message that has been segmented by the queue manager is reassembled by it.
+// If the ConnectionFactory has a SegmentSize then the segments of a message are
+// instead received one at a time and reassembled here, under syncpoint so that
+// the message is not removed from the queue unless every segment is received.
+//
+// The latency of a received message is measured from receiveStart, which is the
+// time at which the application started to wait for it.
+func (consumer ConsumerImpl) receiveInternal(ctx context.Context, receiveStart time.Time, gmo *ibmmq.MQGMO, groupID []byte, buffer []byte) (jms20subset.Message, jms20subset.JMSException) {
 
 	// Lock the context while we are making calls to the queue manager so that it
 	// doesn't conflict with the finalizer we use (below) to delete unused MessageHandles.
-	consumer.ctx.ctxLock.Lock()
+	lockErr := lockWithContext(ctx, consumer.ctx.ctxLock)
+	if lockErr != nil {
+		return nil, lockErr
+	}
 	defer consumer.ctx.ctxLock.Unlock()
 
+	if consumer.ctx.state.closed {
+		return nil, createContextClosedException()
+	}
+
+	if consumer.state.closed {
+		return nil, jms20subset.CreateIllegalStateException(ConsumerImpl_CONSUMER_CLOSED_REASON, ContextImpl_ILLEGAL_STATE_CODE, nil)
+	}
+
 	// Prepare objects to be used in receiving the message.
 	var msg jms20subset.Message
 	var jmsErr jms20subset.JMSException
 
+	// The version 2 structures contain the group and segment fields.
 	getmqmd := ibmmq.NewMQMD()
+	getmqmd.Version = ibmmq.MQMD_VERSION_2
 
-	myBufferSize := 32768
+	if gmo.Version < ibmmq.MQGMO_VERSION_2 {
+		gmo.Version = ibmmq.MQGMO_VERSION_2
+	}
+
+	copyBody := false
+	if buffer == nil {
+		pooledBuffer := consumer.ctx.bufferPool.Get().(*[]byte)
+		defer consumer.ctx.bufferPool.Put(pooledBuffer)
 
-	if consumer.ctx.receiveBufferSize > 0 {
-		myBufferSize = consumer.ctx.receiveBufferSize
+		buffer = *pooledBuffer
+		copyBody = true
 	}
 
-	buffer := make([]byte, myBufferSize)
+	// Browsing leaves the message on the queue, so the queue manager is always
+	// asked to reassemble a browsed message.
+	transacted := consumer.ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED
+	browse := gmo.Options&(ibmmq.MQGMO_BROWSE_FIRST|ibmmq.MQGMO_BROWSE_NEXT) != 0
+	reassemble := consumer.ctx.segmentSize > 0 && !browse
+
+	// The segments of a message are received in a unit of work of their own on a
+	// JMSContext that is not transacted, which would also complete any other work
+	// in the unit of work of the connection handle, unless they are part of a
+	// group that is being received in it.
+	if transacted && !browse {
+		if jmsErr := consumer.ctx.checkTransactedWork(); jmsErr != nil {
+			return nil, jmsErr
+		}
+	} else if reassemble && groupID == nil {
+		if jmsErr := consumer.ctx.checkOwnUnitOfWork(); jmsErr != nil {
+			return nil, jmsErr
+		}
+	}
 
 	// Calculate the syncpoint value
 	syncpointSetting := ibmmq.MQGMO_NO_SYNCPOINT
-	if consumer.ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED {
+	if transacted || groupID != nil || reassemble {
 		syncpointSetting = ibmmq.MQGMO_SYNCPOINT
 	}
 
@@ -91,28 +496,92 @@ func (consumer ConsumerImpl) receiveInternal(gmo *ibmmq.MQGMO) (jms20subset.Mess
 
 	// Include the message properties in the msgHandle
 	gmo.Options |= ibmmq.MQGMO_PROPERTIES_IN_HANDLE
-	cmho := ibmmq.NewMQCMHO()
-	thisMsgHandle, _ := consumer.ctx.qMgr.CrtMH(cmho)
-	gmo.MsgHandle = thisMsgHandle
+	thisMsgHandle, err := consumer.ctx.handlePool.getHandle(consumer.ctx.qMgr)
+	if err != nil {
+		return nil, createJMSExceptionFromMQReturn(err)
+	}
 
 	// Apply the selector if one has been specified in the Consumer
-	err := applySelector(consumer.selector, getmqmd, gmo)
+	err = applySelector(consumer.selector, getmqmd, gmo)
 	if err != nil {
-		jmsErr = jms20subset.CreateJMSException("ErrorParsingSelector", "ErrorParsingSelector", err)
+		consumer.ctx.handlePool.putHandle(thisMsgHandle)
+		jmsErr = jms20subset.CreateInvalidSelectorException("ErrorParsingSelector", "ErrorParsingSelector", err)
 		return nil, jmsErr
 	}
 
+	// Receive the first segment of a message whose segments are all on the
+	// queue, or otherwise the whole message.
+	if reassemble {
+		gmo.Options |= ibmmq.MQGMO_ALL_SEGMENTS_AVAILABLE
+
+		if groupID == nil {
+			getmqmd.Offset = 0
+			gmo.MatchOptions |= ibmmq.MQMO_MATCH_OFFSET
+		}
+	} else {
+		gmo.Options |= ibmmq.MQGMO_COMPLETE_MSG
+	}
+
+	// Match on the group ID.
+	if groupID != nil {
+		getmqmd.GroupId = groupID
+		gmo.Options |= ibmmq.MQGMO_LOGICAL_ORDER
+		gmo.MatchOptions |= ibmmq.MQMO_MATCH_GROUP_ID
+
+		if isZeroID(groupID) {
+			gmo.Options |= ibmmq.MQGMO_ALL_MSGS_AVAILABLE
+		}
+	}
+
 	// Use the prepared objects to ask for a message from the queue.
-	datalen, err := consumer.qObject.Get(getmqmd, gmo, buffer)
+	datalen, err := consumer.qObject.Get(getmqmd, gmo, thisMsgHandle, buffer)
+	getLatency := time.Since(receiveStart)
+	consumer.ctx.traceMQI("MQGET", consumer.qObject.Name(), err)
+
+	if err == nil && moreSegments(getmqmd) {
+
+		var body []byte
+		body, err = consumer.receiveSegments(getmqmd, groupID != nil, buffer, datalen)
+
+		// A buffer that was supplied by the caller must hold the whole message.
+		if err == nil && !copyBody {
+			if len(body) > len(buffer) {
+				err = &ibmmq.MQReturn{MQCC: ibmmq.MQCC_WARNING, MQRC: ibmmq.MQRC_TRUNCATED_MSG_FAILED}
+			} else {
+				body = buffer[:copy(buffer, body)]
+			}
+		}
+
+		// The reassembled body is used as it is, rather than being copied again.
+		buffer = body
+		datalen = len(body)
+		copyBody = false
+		getLatency = time.Since(receiveStart)
+	}
+
+	// The segments of a message are received in a unit of work of their own,
+	// unless the JMSContext is transacted, so that they are removed together.
+	if reassemble && groupID == nil && !transacted {
+		if err == nil {
+			err = consumer.ctx.qMgr.Cmit()
+			consumer.ctx.traceMQI("MQCMIT", "", err)
+		} else if mqret, ok := err.(*ibmmq.MQReturn); !ok || mqret.MQRC != ibmmq.MQRC_NO_MSG_AVAILABLE {
+			backErr := consumer.ctx.qMgr.Back()
+			consumer.ctx.traceMQI("MQBACK", "", backErr)
+		}
+	}
 
 	if err == nil {
 
-		// Set a finalizer on the message handle to allow it to be deleted
-		// when it is no longer referenced by an active object, to reduce/prevent
-		// memory leaks.
-		setMessageHandlerFinalizer(thisMsgHandle, consumer.ctx.ctxLock)
+		if transacted && !browse {
+			consumer.ctx.conn.uncommitted = true
+		}
 
 		// Message received successfully (without error).
+		consumer.ctx.metrics.GetLatency(consumer.qObject.Name(), getLatency)
+		consumer.ctx.metrics.MessageReceived(consumer.qObject.Name(), int(datalen))
+		consumer.ctx.recordReceiveSpan(ctx, consumer.qObject.Name(), getmqmd, thisMsgHandle, int(datalen))
+
 		// Determine on the basis of the format field what sort of message to create.
 
 		if getmqmd.Format == ibmmq.MQFMT_STRING {
@@ -127,27 +596,35 @@ func (consumer ConsumerImpl) receiveInternal(gmo *ibmmq.MQGMO) (jms20subset.Mess
 			msg = &TextMessageImpl{
 				bodyStr: msgBodyStr,
 				MessageImpl: MessageImpl{
-					mqmd:      getmqmd,
-					msgHandle: &thisMsgHandle,
-					ctxLock:   consumer.ctx.ctxLock,
+					mqmd:       getmqmd,
+					msgHandle:  thisMsgHandle,
+					handlePool: consumer.ctx.handlePool,
+					ctxLock:    consumer.ctx.ctxLock,
+					logger:     consumer.ctx.logger,
 				},
 			}
 
 		} else {
 
-			if datalen == 0 {
-				buffer = []byte{}
-			}
-
 			trimmedBuffer := buffer[0:datalen]
 
+			// Take a copy of the body if the buffer is about to be returned to
+			// the pool, which also means that the message only keeps alive the
+			// memory it actually needs.
+			if copyBody || datalen == 0 {
+				trimmedBuffer = make([]byte, datalen)
+				copy(trimmedBuffer, buffer[0:datalen])
+			}
+
 			// Not a string, so fall back to BytesMessage
 			msg = &BytesMessageImpl{
 				bodyBytes: &trimmedBuffer,
 				MessageImpl: MessageImpl{
-					mqmd:      getmqmd,
-					msgHandle: &thisMsgHandle,
-					ctxLock:   consumer.ctx.ctxLock,
+					mqmd:       getmqmd,
+					msgHandle:  thisMsgHandle,
+					handlePool: consumer.ctx.handlePool,
+					ctxLock:    consumer.ctx.ctxLock,
+					logger:     consumer.ctx.logger,
 				},
 			}
 		}
@@ -157,10 +634,9 @@ func (consumer ConsumerImpl) receiveInternal(gmo *ibmmq.MQGMO) (jms20subset.Mess
 		// Error code was returned from MQ call.
 		mqret := err.(*ibmmq.MQReturn)
 
-		// Delete the message handle object in-line here now that it is no longer required,
+		// Give back the message handle in-line here now that it is no longer required,
 		// to avoid memory leak
-		dmho := ibmmq.NewMQDMHO()
-		gmo.MsgHandle.DltMH(dmho)
+		consumer.ctx.handlePool.putHandle(thisMsgHandle)
 
 		if mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
 
@@ -172,11 +648,7 @@ func (consumer ConsumerImpl) receiveInternal(gmo *ibmmq.MQGMO) (jms20subset.Mess
 
 			// Parse the details of the error and return it to the caller as
 			// a JMSException
-			rcInt := int(mqret.MQRC)
-			errCode := strconv.Itoa(rcInt)
-			reason := ibmmq.MQItoString("RC", rcInt)
-
-			jmsErr = jms20subset.CreateJMSException(reason, errCode, err)
+			jmsErr = createJMSExceptionForMQRC(mqret.MQRC, err)
 		}
 
 	}
@@ -184,36 +656,6 @@ func (consumer ConsumerImpl) receiveInternal(gmo *ibmmq.MQGMO) (jms20subset.Mess
 	return msg, jmsErr
 }
 
-/*
- * Set a finalizer on the message handle to allow it to be deleted
- * when it is no longer referenced by an active object, to reduce/prevent
- * memory leaks.
- */
-func setMessageHandlerFinalizer(thisMsgHandle ibmmq.MQMessageHandle, ctxLock *sync.Mutex) {
-
-	runtime.SetFinalizer(&thisMsgHandle, func(msgHandle *ibmmq.MQMessageHandle) {
-		ctxLock.Lock()
-		defer ctxLock.Unlock()
-
-		dmho := ibmmq.NewMQDMHO()
-		err := msgHandle.DltMH(dmho)
-		if err != nil {
-
-			mqret := err.(*ibmmq.MQReturn)
-
-			if mqret.MQRC == ibmmq.MQRC_HCONN_ERROR {
-				// Expected if the connection is closed before the finalizer executes
-				// (at which point it should get tidied up automatically by the connection)
-			} else {
-				fmt.Println("DltMH finalizer", err)
-			}
-
-		}
-
-	})
-
-}
-
 // ReceiveStringBodyNoWait implements the IBM MQ logic necessary to receive a
 // message from a Destination and return its body as a string.
 //
@@ -233,7 +675,7 @@ func (consumer ConsumerImpl) ReceiveStringBodyNoWait() (*string, jms20subset.JMS
 		case jms20subset.TextMessage:
 			msgBodyStrPtr = msg.GetText()
 		default:
-			jmsErr = jms20subset.CreateJMSException(
+			jmsErr = jms20subset.CreateMessageFormatException(
 				"MQJMS_DIR_MIN_NOTTEXT", "MQJMS6068", nil)
 		}
 
@@ -263,7 +705,7 @@ func (consumer ConsumerImpl) ReceiveStringBody(waitMillis int32) (*string, jms20
 		case jms20subset.TextMessage:
 			msgBodyStrPtr = msg.GetText()
 		default:
-			jmsErr = jms20subset.CreateJMSException(
+			jmsErr = jms20subset.CreateMessageFormatException(
 				"MQJMS_DIR_MIN_NOTTEXT", "MQJMS6068", nil)
 		}
 
@@ -292,7 +734,7 @@ func (consumer ConsumerImpl) ReceiveBytesBodyNoWait() (*[]byte, jms20subset.JMSE
 		case jms20subset.BytesMessage:
 			msgBodyPtr = msg.ReadBytes()
 		default:
-			jmsErr = jms20subset.CreateJMSException(
+			jmsErr = jms20subset.CreateMessageFormatException(
 				"MQJMS_DIR_MIN_NOTBYTES", "MQJMS6068", nil)
 		}
 
@@ -322,7 +764,7 @@ func (consumer ConsumerImpl) ReceiveBytesBody(waitMillis int32) (*[]byte, jms20s
 		case jms20subset.BytesMessage:
 			msgBodyPtr = msg.ReadBytes()
 		default:
-			jmsErr = jms20subset.CreateJMSException(
+			jmsErr = jms20subset.CreateMessageFormatException(
 				"MQJMS_DIR_MIN_NOTBYTES", "MQJMS6068", nil)
 		}
 
@@ -332,6 +774,29 @@ func (consumer ConsumerImpl) ReceiveBytesBody(waitMillis int32) (*[]byte, jms20s
 
 }
 
+// ConsumerImpl_DEFAULT_RECEIVE_WAIT_SLICE_MILLIS is the default for the longest time in
+// milliseconds that an individual MQGET call will wait while receiving a message with a
+// wait. See also ConnectionFactoryImpl.ReceiveWaitSlice.
+const ConsumerImpl_DEFAULT_RECEIVE_WAIT_SLICE_MILLIS int32 = 250
+
+// ConsumerImpl_CONSUMER_CLOSED_REASON is the reason used in the JMSException that is
+// returned when a JMSConsumer or QueueBrowser is used after it has been closed.
+const ConsumerImpl_CONSUMER_CLOSED_REASON string = "MQJMS_E_CONSUMER_CLOSED"
+
+// ConsumerImpl_GROUP_INCOMPLETE_REASON is the reason used in the JMSException that
+// is returned when ReceiveGroup or ReceiveStream finds that part of a group is no
+// longer on the queue.
+const ConsumerImpl_GROUP_INCOMPLETE_REASON string = "MQJMS_E_GROUP_INCOMPLETE"
+
+// ConsumerImpl_GROUP_INCOMPLETE_CODE is the error code used in the JMSException that
+// is returned when ReceiveGroup or ReceiveStream finds that part of a group is no
+// longer on the queue.
+const ConsumerImpl_GROUP_INCOMPLETE_CODE string = "GroupIncomplete"
+
+// ConsumerImpl_STREAM_CLOSED_REASON is the reason used in the JMSException that is
+// returned when the reader returned by ReceiveStream is read after it has been closed.
+const ConsumerImpl_STREAM_CLOSED_REASON string = "MQJMS_E_STREAM_CLOSED"
+
 // applySelector is responsible for converting the JMS style selector string
 // into the relevant options on the MQI structures so that the correct messages
 // are received by the application.
@@ -402,17 +867,24 @@ func applySelector(selector string, getmqmd *ibmmq.MQMD, gmo *ibmmq.MQGMO) error
 }
 
 // Close closes the JMSConsumer, releasing any resources that were allocated on
-// behalf of that consumer.
+// behalf of that consumer. Closing a consumer that is already closed has no effect.
 func (consumer ConsumerImpl) Close() {
 
-	if (ibmmq.MQObject{}) != consumer.qObject {
+	if consumer.qObject != nil {
 
 		// Lock the context while we are making calls to the queue manager so that it
 		// doesn't conflict with the finalizer we use (below) to delete unused MessageHandles.
 		consumer.ctx.ctxLock.Lock()
 		defer consumer.ctx.ctxLock.Unlock()
 
-		consumer.qObject.Close(0)
+		if consumer.state.closed {
+			return
+		}
+
+		err := consumer.qObject.Close(0)
+		consumer.ctx.traceMQI("MQCLOSE", consumer.qObject.Name(), err)
+		consumer.state.closed = true
+		delete(consumer.ctx.state.consumers, consumer.state)
 	}
 
 	return
@@ -10,8 +10,7 @@
 package mqjms
 
 import (
-	"fmt"
-	"strconv"
+	"context"
 	"sync"
 
 	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
@@ -20,13 +19,144 @@ import (
 
 // ContextImpl encapsulates the objects necessary to maintain an active
 // connection to an IBM MQ queue manager.
+//
+// A ContextImpl and the consumers and producers created from it can be used from
+// multiple goroutines. A connection handle can only process one MQI call at a time,
+// so calls to the queue manager are serialised using a lock that is shared by all
+// of the objects created from the context. A receive with a wait only holds the lock
+// for a short slice of time (see ConnectionFactoryImpl.ReceiveWaitSlice) so that
+// other goroutines are not held up for the whole of the wait.
+//
+// Note that a transaction belongs to the whole context, so Commit and Rollback apply
+// to the work done by every goroutine using it. Use ConnectionFactory.CreateContext
+// to give each goroutine its own transaction scope.
 type ContextImpl struct {
-	qMgr              ibmmq.MQQueueManager
-	ctxLock           *sync.Mutex // Mutex to synchronize MQRC calls to the queue manager
+	qMgr              mqiQueueManager
+	ctxLock           *sync.Mutex     // Mutex to synchronize MQRC calls to the queue manager
+	conn              *connectionImpl // Shared with the contexts created using CreateContext
 	sessionMode       int
 	receiveBufferSize int
+	bufferPool        *sync.Pool // Pool of *[]byte of receiveBufferSize, for receiving messages
+	segmentSize       int        // Largest segment sent by the library, SegmentSize_QUEUE_MANAGER, or zero for no segmentation
+	receiveWaitSlice  int32      // Longest time in milliseconds for a single MQGET wait
 	sendCheckCount    int
-	sendCheckCountInc *int // Internal counter to keep track of async-put messages sent
+	sendCheckCountInc *int                           // Internal counter to keep track of async-put messages sent
+	factory           ConnectionFactoryImpl          // Used to create further contexts with the same settings
+	mqos              []jms20subset.ConnectionOption // Options that were applied when connecting
+	handleCache       *handleCache                   // Queues held open for sending, or nil if not enabled
+	handlePool        *msgHandlePool                 // Message handles released by messages, for reuse
+	state             *contextState                  // Shared by all copies of this ContextImpl
+	logger            Logger
+	mqiTrace          bool   // Whether to log every call to the queue manager
+	tracer            Tracer // Creates spans for messaging operations, or nil if not enabled
+	metrics           Metrics
+}
+
+// contextState holds the parts of a ContextImpl that change during its lifetime.
+// ContextImpl is passed around by value, so this is held by pointer in order that
+// every copy of the ContextImpl (including those held by its consumers and
+// producers) sees the same state. The context lock must be held to use it.
+type contextState struct {
+	closed    bool
+	consumers map[*consumerState]mqiObject // Open consumers and browsers, to close with the context
+}
+
+// beginUnitOfWork records that a group is being sent or received in a unit of
+// work of its own on a JMSContext that is not transacted, by SendStream,
+// ReceiveGroup or ReceiveStream. An error is returned if the unit of work of the
+// connection handle already contains other work, such as another group that is
+// being received, as completing the unit of work of the group would also
+// complete that work.
+func (ctx ContextImpl) beginUnitOfWork() jms20subset.JMSException {
+
+	ctx.ctxLock.Lock()
+	defer ctx.ctxLock.Unlock()
+
+	if ctx.state.closed {
+		return createContextClosedException()
+	}
+
+	if jmsErr := ctx.checkOwnUnitOfWork(); jmsErr != nil {
+		return jmsErr
+	}
+
+	ctx.conn.unitOfWork = ctx.state
+	return nil
+}
+
+// endUnitOfWork records that a group is no longer being sent or received, when
+// nothing has been done in its unit of work.
+func (ctx ContextImpl) endUnitOfWork() {
+
+	ctx.ctxLock.Lock()
+	defer ctx.ctxLock.Unlock()
+
+	if ctx.conn.unitOfWork == ctx.state {
+		ctx.conn.unitOfWork = nil
+	}
+}
+
+// completeUnitOfWork commits or backs out the unit of work in which a group
+// was being sent or received, and records that it is no longer in use. An error
+// is returned if the JMSContext has been closed, which backs out the unit of
+// work, before the group is committed.
+func (ctx ContextImpl) completeUnitOfWork(commit bool) jms20subset.JMSException {
+
+	ctx.ctxLock.Lock()
+	defer ctx.ctxLock.Unlock()
+
+	if ctx.conn.unitOfWork != ctx.state {
+		if commit {
+			return createContextClosedException()
+		}
+		return nil
+	}
+
+	ctx.conn.unitOfWork = nil
+
+	if !commit {
+		err := ctx.qMgr.Back()
+		ctx.traceMQI("MQBACK", "", err)
+		return nil
+	}
+
+	err := ctx.qMgr.Cmit()
+	ctx.traceMQI("MQCMIT", "", err)
+
+	if err != nil {
+		return createJMSExceptionFromMQReturn(err)
+	}
+
+	return nil
+}
+
+// CreateContext creates a new JMSContext that shares the connection handle of
+// this JMSContext, with the specified session mode, so that no further
+// connection to the queue manager (or channel instance) is used.
+//
+// An MQ connection handle has a single unit of work, so the contexts that share
+// it also share a transaction scope. Commit and Rollback on a transacted
+// JMSContext apply to the messages sent and received under syncpoint using any
+// of them, and have no effect on a JMSContext that is not transacted. While a
+// transacted JMSContext has work that is not committed, operations on the other
+// contexts that need a unit of work of their own (such as ReceiveGroup) return
+// an error with the reason MQJMS_E_UNIT_OF_WORK_OPEN. Use
+// ConnectionFactory.CreateContext for a JMSContext with its own transaction scope.
+//
+// The connection handle is disconnected when the last JMSContext that shares it
+// is closed, so the contexts can be closed in any order. Calls to the queue
+// manager are serialised across all of them, in the same way as for the
+// goroutines that use a single JMSContext.
+func (ctx ContextImpl) CreateContext(sessionMode int) (jms20subset.JMSContext, jms20subset.JMSException) {
+
+	ctx.ctxLock.Lock()
+	defer ctx.ctxLock.Unlock()
+
+	if ctx.state.closed {
+		return nil, createContextClosedException()
+	}
+
+	return ctx.factory.newContext(ctx.qMgr, ctx.ctxLock, ctx.conn, sessionMode, ctx.mqos), nil
 }
 
 // CreateQueue implements the logic necessary to create a provider-specific
@@ -37,6 +167,9 @@ func (ctx ContextImpl) CreateQueue(queueName string) jms20subset.Queue {
 	queue := QueueImpl{
 		queueName:       queueName,
 		putAsyncAllowed: jms20subset.Destination_PUT_ASYNC_ALLOWED_AS_DEST,
+		deliveryMode:    Destination_AS_PRODUCER,
+		priority:        Destination_AS_PRODUCER,
+		logger:          ctx.logger,
 	}
 
 	return queue
@@ -72,6 +205,10 @@ func (ctx ContextImpl) CreateConsumerWithSelector(dest jms20subset.Destination,
 	ctx.ctxLock.Lock()
 	defer ctx.ctxLock.Unlock()
 
+	if ctx.state.closed {
+		return nil, createContextClosedException()
+	}
+
 	// First validate the selector string format (we don't make use of it at
 	// runtime until the receive is called)
 	if selector != "" {
@@ -80,7 +217,7 @@ func (ctx ContextImpl) CreateConsumerWithSelector(dest jms20subset.Destination,
 
 		selectorErr := applySelector(selector, getmqmd, gmo)
 		if selectorErr != nil {
-			return nil, jms20subset.CreateJMSException("Invalid selector syntax", "MQJMS0004", selectorErr)
+			return nil, jms20subset.CreateInvalidSelectorException("Invalid selector syntax", "MQJMS0004", selectorErr)
 		}
 	}
 
@@ -97,24 +234,26 @@ func (ctx ContextImpl) CreateConsumerWithSelector(dest jms20subset.Destination,
 
 	// Invoke the MQ command to open the queue.
 	qObject, err := ctx.qMgr.Open(mqod, openOptions)
+	ctx.traceMQI("MQOPEN", mqod.ObjectName, err)
 
 	if err == nil {
 
 		// Success - store the necessary objects away for later use to receive
 		// messages.
+		state := &consumerState{}
+		ctx.state.consumers[state] = qObject
+
 		consumer = ConsumerImpl{
 			ctx:      ctx,
 			qObject:  qObject,
 			selector: selector,
+			state:    state,
 		}
 
 	} else {
 
 		// Error occurred - extract the failure details and return to the caller.
-		rcInt := int(err.(*ibmmq.MQReturn).MQRC)
-		errCode := strconv.Itoa(rcInt)
-		reason := ibmmq.MQItoString("RC", rcInt)
-		retErr = jms20subset.CreateJMSException(reason, errCode, err)
+		retErr = createJMSExceptionFromMQReturn(err)
 
 	}
 
@@ -130,6 +269,10 @@ func (ctx ContextImpl) CreateBrowser(dest jms20subset.Destination) (jms20subset.
 	ctx.ctxLock.Lock()
 	defer ctx.ctxLock.Unlock()
 
+	if ctx.state.closed {
+		return nil, createContextClosedException()
+	}
+
 	// Set up the necessary objects to open the queue
 	mqod := ibmmq.NewMQOD()
 	var openOptions int32
@@ -144,14 +287,19 @@ func (ctx ContextImpl) CreateBrowser(dest jms20subset.Destination) (jms20subset.
 
 	// Invoke the MQ command to open the queue.
 	qObject, err := ctx.qMgr.Open(mqod, openOptions)
+	ctx.traceMQI("MQOPEN", mqod.ObjectName, err)
 
 	if err == nil {
 
 		// Success - store the necessary objects away for later use to receive
 		// messages.
+		state := &consumerState{}
+		ctx.state.consumers[state] = qObject
+
 		consumer := ConsumerImpl{
 			ctx:     ctx,
 			qObject: qObject,
+			state:   state,
 		}
 
 		brse := int32(ibmmq.MQGMO_BROWSE_FIRST)
@@ -164,16 +312,57 @@ func (ctx ContextImpl) CreateBrowser(dest jms20subset.Destination) (jms20subset.
 	} else {
 
 		// Error occurred - extract the failure details and return to the caller.
-		rcInt := int(err.(*ibmmq.MQReturn).MQRC)
-		errCode := strconv.Itoa(rcInt)
-		reason := ibmmq.MQItoString("RC", rcInt)
-		retErr = jms20subset.CreateJMSException(reason, errCode, err)
+		retErr = createJMSExceptionFromMQReturn(err)
 
 	}
 
 	return browser, retErr
 }
 
+// createTemporaryQueueConsumer creates a temporary dynamic queue from the specified
+// model queue, and returns a consumer that receives messages from it together with
+// a Queue that refers to it, so that it can be used as the JMSReplyTo of messages.
+//
+// The queue is opened for exclusive input, and is deleted by the queue manager
+// when the consumer is closed.
+func (ctx ContextImpl) createTemporaryQueueConsumer(modelQueueName string) (jms20subset.JMSConsumer, jms20subset.Queue, jms20subset.JMSException) {
+
+	ctx.ctxLock.Lock()
+	defer ctx.ctxLock.Unlock()
+
+	if ctx.state.closed {
+		return nil, nil, createContextClosedException()
+	}
+
+	// The queue manager replaces the asterisk with a unique suffix to give the
+	// name of the new queue.
+	mqod := ibmmq.NewMQOD()
+	var openOptions int32
+	openOptions = ibmmq.MQOO_FAIL_IF_QUIESCING
+	openOptions |= ibmmq.MQOO_INPUT_EXCLUSIVE
+	mqod.ObjectType = ibmmq.MQOT_Q
+	mqod.ObjectName = modelQueueName
+	mqod.DynamicQName = "AMQ.*"
+
+	qObject, err := ctx.qMgr.Open(mqod, openOptions)
+	ctx.traceMQI("MQOPEN", modelQueueName, err)
+
+	if err != nil {
+		return nil, nil, createJMSExceptionFromMQReturn(err)
+	}
+
+	state := &consumerState{}
+	ctx.state.consumers[state] = qObject
+
+	consumer := ConsumerImpl{
+		ctx:     ctx,
+		qObject: qObject,
+		state:   state,
+	}
+
+	return consumer, ctx.CreateQueue(qObject.Name()), nil
+}
+
 // CreateTextMessage is a JMS standard mechanism for creating a TextMessage.
 func (ctx ContextImpl) CreateTextMessage() jms20subset.TextMessage {
 
@@ -183,34 +372,33 @@ func (ctx ContextImpl) CreateTextMessage() jms20subset.TextMessage {
 	return &TextMessageImpl{
 		bodyStr: bodyStr,
 		MessageImpl: MessageImpl{
-			msgHandle: &thisMsgHandle,
-			ctxLock:   ctx.ctxLock,
+			msgHandle:  thisMsgHandle,
+			handlePool: ctx.handlePool,
+			ctxLock:    ctx.ctxLock,
+			logger:     ctx.logger,
 		},
 	}
 }
 
 // createMsgHandle creates a new message handle object that can be used to
 // store and retrieve message properties.
-func (ctx ContextImpl) createMsgHandle(qMgr ibmmq.MQQueueManager) ibmmq.MQMessageHandle {
+//
+// The handle is reused from an earlier message that has been released if possible.
+func (ctx ContextImpl) createMsgHandle(qMgr mqiQueueManager) mqiMessageHandle {
 
 	// Lock the context while we are making calls to the queue manager so that it
 	// doesn't conflict with the finalizer we use to delete unused MessageHandles.
 	ctx.ctxLock.Lock()
 	defer ctx.ctxLock.Unlock()
 
-	cmho := ibmmq.NewMQCMHO()
-	thisMsgHandle, err := qMgr.CrtMH(cmho)
-
-	// Set a finalizer on the message handle to allow it to be deleted
-	// when it is no longer referenced by an active object, to reduce/prevent
-	// memory leaks.
-	setMessageHandlerFinalizer(thisMsgHandle, ctx.ctxLock)
+	thisMsgHandle, err := ctx.handlePool.getHandle(qMgr)
 
 	if err != nil {
 		// No easy way to pass this error back to the application without
 		// changing the function signature, which could break existing
 		// applications.
-		fmt.Println(err)
+		ctx.logger.Log(LogLevelError, "Failed to create message handle",
+			LogField{Key: LogFieldQueueManager, Value: ctx.factory.QMName}, mqrcField(err))
 	}
 
 	return thisMsgHandle
@@ -226,8 +414,10 @@ func (ctx ContextImpl) CreateTextMessageWithString(txt string) jms20subset.TextM
 	msg := &TextMessageImpl{
 		bodyStr: &txt,
 		MessageImpl: MessageImpl{
-			msgHandle: &thisMsgHandle,
-			ctxLock:   ctx.ctxLock,
+			msgHandle:  thisMsgHandle,
+			handlePool: ctx.handlePool,
+			ctxLock:    ctx.ctxLock,
+			logger:     ctx.logger,
 		},
 	}
 
@@ -243,8 +433,10 @@ func (ctx ContextImpl) CreateBytesMessage() jms20subset.BytesMessage {
 	return &BytesMessageImpl{
 		bodyBytes: thisBodyBytes,
 		MessageImpl: MessageImpl{
-			msgHandle: &thisMsgHandle,
-			ctxLock:   ctx.ctxLock,
+			msgHandle:  thisMsgHandle,
+			handlePool: ctx.handlePool,
+			ctxLock:    ctx.ctxLock,
+			logger:     ctx.logger,
 		},
 	}
 }
@@ -257,25 +449,51 @@ func (ctx ContextImpl) CreateBytesMessageWithBytes(bytes []byte) jms20subset.Byt
 	return &BytesMessageImpl{
 		bodyBytes: &bytes,
 		MessageImpl: MessageImpl{
-			msgHandle: &thisMsgHandle,
-			ctxLock:   ctx.ctxLock,
+			msgHandle:  thisMsgHandle,
+			handlePool: ctx.handlePool,
+			ctxLock:    ctx.ctxLock,
+			logger:     ctx.logger,
 		},
 	}
 }
 
-// Commit confirms all messages that were sent under this transaction.
+// Commit confirms all messages that were sent under this transaction. It has no
+// effect if the context is not transacted.
 func (ctx ContextImpl) Commit() jms20subset.JMSException {
 
 	var retErr jms20subset.JMSException
 
-	if (ibmmq.MQQueueManager{}) != ctx.qMgr {
+	if ctx.qMgr != nil {
 
 		// Lock the context while we are making calls to the queue manager so that it
 		// doesn't conflict with the finalizer we use to delete unused MessageHandles.
 		ctx.ctxLock.Lock()
 		defer ctx.ctxLock.Unlock()
 
+		if ctx.state.closed {
+			return createContextClosedException()
+		}
+
+		// The unit of work of a context that is not transacted may belong to
+		// another context that shares the connection handle.
+		if ctx.sessionMode != jms20subset.JMSContextSESSIONTRANSACTED {
+			return nil
+		}
+
+		if jmsErr := ctx.checkTransactedWork(); jmsErr != nil {
+			return jmsErr
+		}
+
+		span := ctx.startOperationSpan(messagingOperationCommit)
+		defer func() { endSpan(span, retErr) }()
+
 		err := ctx.qMgr.Cmit()
+		ctx.traceMQI("MQCMIT", "", err)
+		ctx.conn.uncommitted = false
+
+		if err == nil {
+			ctx.metrics.Committed()
+		}
 
 		if err != nil {
 
@@ -290,6 +508,7 @@ func (ctx ContextImpl) Commit() jms20subset.JMSException {
 				// Invoke the Stat call agains the queue manager to check for errors.
 				sts := ibmmq.NewMQSTS()
 				statErr := ctx.qMgr.Stat(ibmmq.MQSTAT_TYPE_ASYNC_ERROR, sts)
+				ctx.traceMQI("MQSTAT", "", statErr)
 
 				if statErr != nil {
 
@@ -299,6 +518,8 @@ func (ctx ContextImpl) Commit() jms20subset.JMSException {
 
 				} else {
 
+					ctx.metrics.AsyncPutStatus(sts.PutWarningCount, sts.PutFailureCount)
+
 					// If there are any Warnings or Failures then we have found a problem that
 					// needs to be reported to the user.
 					if sts.PutWarningCount+sts.PutFailureCount > 0 {
@@ -311,10 +532,7 @@ func (ctx ContextImpl) Commit() jms20subset.JMSException {
 
 			}
 
-			rcInt := int(err.(*ibmmq.MQReturn).MQRC)
-			errCode := strconv.Itoa(rcInt)
-			reason := ibmmq.MQItoString("RC", rcInt)
-			retErr = jms20subset.CreateJMSException(reason, errCode, linkedErr)
+			retErr = createJMSExceptionForMQRC(err.(*ibmmq.MQReturn).MQRC, linkedErr)
 
 		}
 
@@ -323,27 +541,44 @@ func (ctx ContextImpl) Commit() jms20subset.JMSException {
 	return retErr
 }
 
-// Rollback releases all messages that were sent under this transaction.
+// Rollback releases all messages that were sent under this transaction. It has
+// no effect if the context is not transacted.
 func (ctx ContextImpl) Rollback() jms20subset.JMSException {
 
 	var retErr jms20subset.JMSException
 
-	if (ibmmq.MQQueueManager{}) != ctx.qMgr {
+	if ctx.qMgr != nil {
 
 		// Lock the context while we are making calls to the queue manager so that it
 		// doesn't conflict with the finalizer we use to delete unused MessageHandles.
 		ctx.ctxLock.Lock()
 		defer ctx.ctxLock.Unlock()
 
+		if ctx.state.closed {
+			return createContextClosedException()
+		}
+
+		if ctx.sessionMode != jms20subset.JMSContextSESSIONTRANSACTED {
+			return nil
+		}
+
+		if jmsErr := ctx.checkTransactedWork(); jmsErr != nil {
+			return jmsErr
+		}
+
+		span := ctx.startOperationSpan(messagingOperationRollback)
+		defer func() { endSpan(span, retErr) }()
+
 		err := ctx.qMgr.Back()
+		ctx.traceMQI("MQBACK", "", err)
+		ctx.conn.uncommitted = false
 
 		if err != nil {
 
-			rcInt := int(err.(*ibmmq.MQReturn).MQRC)
-			errCode := strconv.Itoa(rcInt)
-			reason := ibmmq.MQItoString("RC", rcInt)
-			retErr = jms20subset.CreateJMSException(reason, errCode, err)
+			retErr = createJMSExceptionFromMQReturn(err)
 
+		} else {
+			ctx.metrics.RolledBack()
 		}
 	}
 
@@ -353,23 +588,167 @@ func (ctx ContextImpl) Rollback() jms20subset.JMSException {
 
 // Close this connection to the MQ queue manager, and release any resources
 // that were allocated to support this connection.
+//
+// The consumers and browsers that were created from this context are closed
+// as well, and any further use of the context or the objects created from it
+// returns an error. Closing a context that is already closed has no effect.
+//
+// The connection handle is only disconnected once every context that shares it
+// (see CreateContext) has been closed.
 func (ctx ContextImpl) Close() {
 
-	// JMS semantics are to roll back an active transaction on Close.
-	ctx.Rollback()
+	if ctx.qMgr != nil {
 
-	if (ibmmq.MQQueueManager{}) != ctx.qMgr {
-
-		// Lock the context while we are making calls to the queue manager so that it
-		// doesn't conflict with the finalizer we use to delete unused MessageHandles.
 		ctx.ctxLock.Lock()
-		defer ctx.ctxLock.Unlock()
 
-		ctx.qMgr.Disc()
+		if ctx.state.closed {
+			ctx.ctxLock.Unlock()
+			return
+		}
+
+		ctx.conn.contexts--
+		last := ctx.conn.contexts == 0
+
+		// JMS semantics are to roll back an active transaction on Close, which
+		// is shared with the other contexts using the connection handle, as is
+		// the unit of work of a group that this context is sending or receiving.
+		transacted := ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED
+		var err error
+		if last || ctx.conn.unitOfWork == ctx.state || (transacted && ctx.conn.uncommitted) {
+			err = ctx.qMgr.Back()
+			ctx.traceMQI("MQBACK", "", err)
+			ctx.conn.unitOfWork = nil
+			ctx.conn.uncommitted = false
+		}
+
+		// Close the consumers, browsers and group producers that are still open.
+		for state, qObject := range ctx.state.consumers {
+			err = qObject.Close(0)
+			ctx.traceMQI("MQCLOSE", qObject.Name(), err)
+			state.closed = true
+		}
+		ctx.state.consumers = make(map[*consumerState]mqiObject)
+
+		// Close any queues that are being held open for sending messages.
+		if ctx.handleCache != nil {
+			ctx.handleCache.closeAll()
+		}
+
+		// Delete the message handles that are waiting to be reused.
+		ctx.handlePool.deleteAll()
+
+		ctx.state.closed = true
+
+		// The lock is still held while disconnecting so that it doesn't conflict
+		// with the finalizer we use to delete unused MessageHandles.
+		if last {
+			err = ctx.qMgr.Disc()
+			ctx.traceMQI("MQDISC", "", err)
+		}
+
+		ctx.ctxLock.Unlock()
 	}
 
 }
 
+// traceMQI logs a call to the queue manager and its outcome, if MQI tracing
+// has been enabled using ConnectionFactoryImpl.TraceMQI, and counts the call
+// in the metrics if it failed.
+func (ctx ContextImpl) traceMQI(verb string, queueName string, err error) {
+	traceMQICall(ctx.logger, ctx.mqiTrace, ctx.factory.QMName, verb, queueName, err)
+	recordMQIError(ctx.metrics, verb, err)
+}
+
 // ContextImpl_TRANSACTED_ASYNCPUT_ACTIVE is an internal constant that indicates that
 // a transacted asynchronous put has taken place.
 const ContextImpl_TRANSACTED_ASYNCPUT_ACTIVE int = -100
+
+// ContextImpl_CONTEXT_DONE_REASON is the reason used in the JMSException that is
+// returned when a Go context is cancelled or reaches its deadline during an operation.
+const ContextImpl_CONTEXT_DONE_REASON string = "MQJMS_E_CONTEXT_DONE"
+
+// ContextImpl_CONTEXT_DONE_CODE is the error code used in the JMSException that is
+// returned when a Go context is cancelled or reaches its deadline during an operation.
+const ContextImpl_CONTEXT_DONE_CODE string = "ContextDone"
+
+// createContextDoneException generates a consistent error to describe an operation
+// that was abandoned because its Go context was cancelled or reached its deadline.
+// The context error is linked so that applications can distinguish between the two.
+func createContextDoneException(ctxErr error) jms20subset.JMSException {
+	return jms20subset.CreateJMSException(ContextImpl_CONTEXT_DONE_REASON, ContextImpl_CONTEXT_DONE_CODE, ctxErr)
+}
+
+// ContextImpl_CONTEXT_CLOSED_REASON is the reason used in the JMSException that is
+// returned when a JMSContext, or an object created from it, is used after the
+// JMSContext has been closed.
+const ContextImpl_CONTEXT_CLOSED_REASON string = "MQJMS_E_CONTEXT_CLOSED"
+
+// ContextImpl_UNIT_OF_WORK_OPEN_REASON is the reason used in the JMSException that
+// is returned when an operation that needs a unit of work of its own is attempted
+// on a JMSContext that is not transacted, while a group is being sent or received
+// in another unit of work by SendStream, ReceiveGroup or ReceiveStream, or while a transacted
+// JMSContext that shares the connection handle (see CreateContext) has work that
+// is not committed. Examples are receiving another group, and sending or receiving
+// a message that is segmented by the library (see ConnectionFactoryImpl.SegmentSize).
+//
+// It is also returned when a transacted JMSContext sends, receives, commits or
+// rolls back while another JMSContext sharing its connection handle is sending or
+// receiving a group in a unit of work of its own.
+const ContextImpl_UNIT_OF_WORK_OPEN_REASON string = "MQJMS_E_UNIT_OF_WORK_OPEN"
+
+// createUnitOfWorkOpenException generates a consistent error to describe an
+// operation that cannot be carried out while a group is being received.
+func createUnitOfWorkOpenException() jms20subset.JMSException {
+	return jms20subset.CreateIllegalStateException(ContextImpl_UNIT_OF_WORK_OPEN_REASON, ContextImpl_ILLEGAL_STATE_CODE, nil)
+}
+
+// ContextImpl_ILLEGAL_STATE_CODE is the error code used in the JMSException that is
+// returned when an object is used after it has been closed, in the same way as the
+// IllegalStateException of the Java JMS API.
+const ContextImpl_ILLEGAL_STATE_CODE string = "IllegalState"
+
+// createContextClosedException generates a consistent error to describe an
+// operation that was attempted after the JMSContext was closed.
+func createContextClosedException() jms20subset.JMSException {
+	return jms20subset.CreateIllegalStateException(ContextImpl_CONTEXT_CLOSED_REASON, ContextImpl_ILLEGAL_STATE_CODE, nil)
+}
+
+// lockWithContext acquires the context lock, unless the Go context is cancelled
+// or reaches its deadline first, in which case an error is returned and the
+// lock is not held by the caller.
+func lockWithContext(ctx context.Context, ctxLock *sync.Mutex) jms20subset.JMSException {
+
+	// A context that can never be cancelled (such as context.Background) can
+	// simply wait for the lock in the normal way.
+	if ctx.Done() == nil {
+		ctxLock.Lock()
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		return createContextDoneException(ctx.Err())
+	}
+
+	// sync.Mutex cannot be abandoned part way through a Lock call, so wait for
+	// it on a separate goroutine that signals when the lock has been acquired.
+	acquired := make(chan struct{})
+	go func() {
+		ctxLock.Lock()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		return nil
+
+	case <-ctx.Done():
+		// The caller is no longer waiting, so release the lock on their behalf
+		// as soon as the goroutine above manages to acquire it.
+		go func() {
+			<-acquired
+			ctxLock.Unlock()
+		}()
+		return createContextDoneException(ctx.Err())
+	}
+
+}
@@ -0,0 +1,90 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"io/ioutil"
+	"strings"
+)
+
+// Credentials are the details used to authenticate a connection to the queue
+// manager. If Token is set then token authentication is used, and UserName and
+// Password are ignored.
+type Credentials struct {
+	UserName string
+	Password string
+	Token    string // JSON Web Token (JWT) issued by a token server the queue manager trusts
+}
+
+// CredentialsProvider supplies the credentials for each new connection to the
+// queue manager, so that short-lived tokens and rotated passwords are picked up
+// without restarting the application.
+//
+// Credentials is called every time a JMSContext is created, including by
+// JMSContext.CreateContext, so an application can recover from an expired token
+// by closing the JMSContext and creating a new one.
+//
+// Credentials is not called when the connection is reconnected automatically
+// (see WithReconnect). The reconnection is made by the MQ client library, which
+// always uses the credentials of the original connection and provides no way
+// to replace them, so a reconnection fails with MQRC_NOT_AUTHORIZED once those
+// credentials have expired. An application that uses short-lived tokens should
+// instead leave automatic reconnection disabled, and create a new JMSContext
+// when a failure is retryable (see jms20subset.IsRetryable).
+type CredentialsProvider interface {
+	Credentials() (Credentials, error)
+}
+
+// FileCredentialsProvider is a CredentialsProvider that reads the password or
+// token from a file each time it is called, such as a Kubernetes secret that is
+// mounted into the container and updated when the secret is rotated.
+//
+// Trailing newlines are removed from the contents of the files.
+type FileCredentialsProvider struct {
+	UserName     string
+	PasswordFile string // Path of the file holding the password, if any
+	TokenFile    string // Path of the file holding the token, if any
+}
+
+// Credentials reads the current contents of the password and token files.
+func (provider FileCredentialsProvider) Credentials() (Credentials, error) {
+
+	creds := Credentials{UserName: provider.UserName}
+
+	if provider.PasswordFile != "" {
+		password, err := readSecretFile(provider.PasswordFile)
+		if err != nil {
+			return Credentials{}, err
+		}
+		creds.Password = password
+	}
+
+	if provider.TokenFile != "" {
+		token, err := readSecretFile(provider.TokenFile)
+		if err != nil {
+			return Credentials{}, err
+		}
+		creds.Token = token
+	}
+
+	return creds, nil
+}
+
+// readSecretFile returns the contents of a file that holds a secret value,
+// without any trailing newlines.
+func readSecretFile(path string) (string, error) {
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"strconv"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// createJMSExceptionFromMQReturn converts an error returned by a call to the
+// queue manager into a JMSException, with the error linked so that it can be
+// reached using errors.As.
+func createJMSExceptionFromMQReturn(err error) jms20subset.JMSException {
+	return createJMSExceptionForMQRC(err.(*ibmmq.MQReturn).MQRC, err)
+}
+
+// createJMSExceptionForMQRC generates the JMSException that describes the
+// specified MQ reason code. The error code is the number of the reason code
+// (for example "2085") and the reason is its name (for example
+// "MQRC_UNKNOWN_OBJECT_NAME").
+//
+// The type of the exception depends on the category of the reason code, and
+// reason codes that describe temporary conditions are marked as retryable.
+func createJMSExceptionForMQRC(mqrc int32, linkedErr error) jms20subset.JMSException {
+
+	rcInt := int(mqrc)
+	errCode := strconv.Itoa(rcInt)
+	reason := ibmmq.MQItoString("RC", rcInt)
+
+	switch mqrc {
+	case ibmmq.MQRC_UNKNOWN_OBJECT_NAME,
+		ibmmq.MQRC_UNKNOWN_ALIAS_BASE_Q,
+		ibmmq.MQRC_UNKNOWN_REMOTE_Q_MGR,
+		ibmmq.MQRC_UNKNOWN_OBJECT_Q_MGR,
+		ibmmq.MQRC_OBJECT_TYPE_ERROR,
+		ibmmq.MQRC_OBJECT_NAME_ERROR,
+		ibmmq.MQRC_Q_DELETED:
+		return jms20subset.CreateInvalidDestinationException(reason, errCode, linkedErr)
+
+	case ibmmq.MQRC_SELECTOR_SYNTAX_ERROR:
+		return jms20subset.CreateInvalidSelectorException(reason, errCode, linkedErr)
+
+	case ibmmq.MQRC_FORMAT_ERROR,
+		ibmmq.MQRC_NOT_CONVERTED,
+		ibmmq.MQRC_PROPERTY_TYPE_ERROR:
+		return jms20subset.CreateMessageFormatException(reason, errCode, linkedErr)
+
+	case ibmmq.MQRC_NOT_AUTHORIZED,
+		ibmmq.MQRC_SECURITY_ERROR,
+		ibmmq.MQRC_SSL_INITIALIZATION_ERROR,
+		ibmmq.MQRC_KEY_REPOSITORY_ERROR,
+		ibmmq.MQRC_SSL_PEER_NAME_MISMATCH,
+		ibmmq.MQRC_SSL_CERTIFICATE_REVOKED,
+		ibmmq.MQRC_UNSUPPORTED_CIPHER_SUITE:
+		return jms20subset.CreateSecurityException(reason, errCode, linkedErr)
+
+	case ibmmq.MQRC_BACKED_OUT:
+		return jms20subset.CreateTransactionRolledBackException(reason, errCode, linkedErr)
+
+	case ibmmq.MQRC_HCONN_ERROR,
+		ibmmq.MQRC_HOBJ_ERROR,
+		ibmmq.MQRC_HMSG_ERROR:
+		return jms20subset.CreateIllegalStateException(reason, errCode, linkedErr)
+
+	case ibmmq.MQRC_Q_FULL,
+		ibmmq.MQRC_Q_SPACE_NOT_AVAILABLE,
+		ibmmq.MQRC_STORAGE_NOT_AVAILABLE,
+		ibmmq.MQRC_RESOURCE_PROBLEM,
+		ibmmq.MQRC_MAX_CONNS_LIMIT_REACHED,
+		ibmmq.MQRC_HANDLE_NOT_AVAILABLE,
+		ibmmq.MQRC_SYNCPOINT_LIMIT_REACHED,
+		ibmmq.MQRC_OBJECT_IN_USE:
+		return jms20subset.CreateResourceAllocationException(reason, errCode, linkedErr)
+
+	case ibmmq.MQRC_CONNECTION_BROKEN,
+		ibmmq.MQRC_Q_MGR_NOT_AVAILABLE,
+		ibmmq.MQRC_HOST_NOT_AVAILABLE,
+		ibmmq.MQRC_CHANNEL_NOT_AVAILABLE,
+		ibmmq.MQRC_Q_MGR_QUIESCING,
+		ibmmq.MQRC_Q_MGR_STOPPING,
+		ibmmq.MQRC_CONNECTION_QUIESCING,
+		ibmmq.MQRC_CONNECTION_STOPPING,
+		ibmmq.MQRC_RECONNECTING,
+		ibmmq.MQRC_RECONNECT_FAILED,
+		ibmmq.MQRC_CALL_INTERRUPTED,
+		ibmmq.MQRC_PUT_INHIBITED,
+		ibmmq.MQRC_GET_INHIBITED:
+		return jms20subset.CreateRetryableJMSException(reason, errCode, linkedErr)
+	}
+
+	return jms20subset.CreateJMSException(reason, errCode, linkedErr)
+}
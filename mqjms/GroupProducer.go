@@ -0,0 +1,205 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// GroupProducer sends messages to a queue as message groups, which a consumer
+// can receive as a whole using ConsumerImpl.ReceiveGroup.
+//
+// The queue manager assigns the group ID (JMSXGroupID) of each group, and the
+// sequence number (JMSXGroupSeq) of each message in it, and sets
+// JMS_IBM_Last_Msg_In_Group on the message that is sent using SendLast. The
+// messages of a group only become available to ReceiveGroup once the last one
+// has been sent.
+//
+// The queue is held open by the GroupProducer, because the queue manager keeps
+// track of the group on the handle, so it must be closed when it is no longer
+// needed. Only one group is sent at a time, so a GroupProducer should be used
+// by a single goroutine. If the JMSContext is transacted then every message of a
+// group must be sent in the same transaction.
+type GroupProducer struct {
+	producer      *ProducerImpl
+	dest          jms20subset.Destination
+	qObject       mqiObject
+	state         *consumerState // Closed when the JMSContext is closed
+	lock          sync.Mutex
+	inGroup       bool   // Whether a group has been started and not yet ended
+	groupID       []byte // ID of the current or most recent group
+	ownUnitOfWork bool   // Whether the messages are sent in a unit of work of their own, by SendStream
+	closed        bool
+}
+
+// GroupProducer_CLOSED_REASON is the reason used in the JMSException that is returned
+// when a message is sent using a GroupProducer that has been closed.
+const GroupProducer_CLOSED_REASON string = "MQJMS_E_GROUP_PRODUCER_CLOSED"
+
+// NewGroupProducer creates a GroupProducer that sends message groups to the
+// specified queue using the JMSContext, which must be a connection to IBM MQ.
+func NewGroupProducer(parent jms20subset.JMSContext, dest jms20subset.Destination) (*GroupProducer, jms20subset.JMSException) {
+
+	ctx, ok := parent.(ContextImpl)
+	if !ok {
+		return nil, jms20subset.CreateIllegalStateException("UnexpectedContextType", "UnexpectedContextType", nil)
+	}
+
+	return newGroupProducer(ctx.CreateProducer().(*ProducerImpl), dest)
+}
+
+// newGroupProducer creates a GroupProducer that sends message groups using the
+// settings of the producer.
+func newGroupProducer(producer *ProducerImpl, dest jms20subset.Destination) (*GroupProducer, jms20subset.JMSException) {
+
+	ctx := producer.ctx
+
+	ctx.ctxLock.Lock()
+	defer ctx.ctxLock.Unlock()
+
+	if ctx.state.closed {
+		return nil, createContextClosedException()
+	}
+
+	mqod := ibmmq.NewMQOD()
+	var openOptions int32
+	openOptions = ibmmq.MQOO_FAIL_IF_QUIESCING
+	openOptions |= ibmmq.MQOO_OUTPUT
+	mqod.ObjectType = ibmmq.MQOT_Q
+	mqod.ObjectName = dest.GetDestinationName()
+
+	if queue, isQueueImpl := dest.(QueueImpl); isQueueImpl {
+		mqod.ObjectQMgrName = queue.queueManagerName
+	}
+
+	qObject, err := ctx.qMgr.Open(mqod, openOptions)
+	ctx.traceMQI("MQOPEN", mqod.ObjectName, err)
+
+	if err != nil {
+		return nil, createJMSExceptionFromMQReturn(err)
+	}
+
+	// Register the handle so that it is closed along with the JMSContext.
+	state := &consumerState{}
+	ctx.state.consumers[state] = qObject
+
+	return &GroupProducer{
+		producer: producer,
+		dest:     dest,
+		qObject:  qObject,
+		state:    state,
+	}, nil
+}
+
+// GetProducer returns the JMSProducer whose settings, such as the delivery mode,
+// priority and time to live, are applied to the messages of the groups. The
+// settings must not be changed part way through a group.
+func (groupProducer *GroupProducer) GetProducer() jms20subset.JMSProducer {
+	return groupProducer.producer
+}
+
+// Send sends a message as part of the current group, starting a new group if
+// there is no current group.
+func (groupProducer *GroupProducer) Send(msg jms20subset.Message) jms20subset.JMSException {
+	return groupProducer.send(msg, false)
+}
+
+// SendLast sends the last message of the current group, which completes the
+// group. If there is no current group then the message is sent as a group of one.
+func (groupProducer *GroupProducer) SendLast(msg jms20subset.Message) jms20subset.JMSException {
+	return groupProducer.send(msg, true)
+}
+
+// send sends a message as part of the current group.
+func (groupProducer *GroupProducer) send(msg jms20subset.Message, last bool) jms20subset.JMSException {
+
+	groupProducer.lock.Lock()
+	defer groupProducer.lock.Unlock()
+
+	if groupProducer.closed {
+		return jms20subset.CreateIllegalStateException(GroupProducer_CLOSED_REASON, ContextImpl_ILLEGAL_STATE_CODE, nil)
+	}
+
+	group := &groupPut{
+		qObject:       groupProducer.qObject,
+		last:          last,
+		ownUnitOfWork: groupProducer.ownUnitOfWork,
+	}
+
+	sendErr := groupProducer.producer.sendInternal(context.Background(), groupProducer.dest, msg, group)
+	if sendErr != nil {
+		return sendErr
+	}
+
+	if mqmd := messageMQMD(msg); mqmd != nil {
+		groupProducer.groupID = append([]byte{}, mqmd.GroupId...)
+	}
+	groupProducer.inGroup = !last
+
+	return nil
+}
+
+// GetGroupID returns the group ID that the queue manager assigned to the current
+// group, or to the most recent group if the last message of it has been sent, in
+// the same form as the JMSXGroupID property of the messages. An empty string is
+// returned if no messages have been sent.
+func (groupProducer *GroupProducer) GetGroupID() string {
+
+	groupProducer.lock.Lock()
+	defer groupProducer.lock.Unlock()
+
+	if isZeroID(groupProducer.groupID) {
+		return ""
+	}
+
+	return hex.EncodeToString(groupProducer.groupID)
+}
+
+// Close closes the queue that the GroupProducer holds open. The messages of a
+// group whose last message has not been sent remain on the queue but are never
+// received by ReceiveGroup, so a warning is logged in that case. Closing a
+// GroupProducer that is already closed has no effect.
+func (groupProducer *GroupProducer) Close() {
+
+	groupProducer.lock.Lock()
+	defer groupProducer.lock.Unlock()
+
+	if groupProducer.closed {
+		return
+	}
+	groupProducer.closed = true
+
+	ctx := groupProducer.producer.ctx
+
+	if groupProducer.inGroup {
+		ctx.logger.Log(LogLevelWarn, "Closed a GroupProducer before the last message of the group was sent",
+			LogField{Key: LogFieldQueue, Value: groupProducer.qObject.Name()},
+			LogField{Key: LogFieldValue, Value: hex.EncodeToString(groupProducer.groupID)})
+	}
+
+	ctx.ctxLock.Lock()
+	defer ctx.ctxLock.Unlock()
+
+	// The handle has already been closed if the JMSContext has been closed.
+	if groupProducer.state.closed {
+		return
+	}
+
+	err := groupProducer.qObject.Close(0)
+	ctx.traceMQI("MQCLOSE", groupProducer.qObject.Name(), err)
+	groupProducer.state.closed = true
+	delete(ctx.state.consumers, groupProducer.state)
+
+}
@@ -0,0 +1,126 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"container/list"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// handleCache holds the queues that have been opened for output by the producers
+// of a JMSContext, so that repeated sends to the same destination can use MQPUT
+// on an open handle rather than MQPUT1, which opens and closes the queue on every
+// call.
+//
+// When the cache is full the least recently used handle is closed to make room.
+// The cache is not safe for concurrent use by itself, so the caller must hold the
+// context lock when calling its methods.
+type handleCache struct {
+	maxSize int
+	lru     *list.List                       // Most recently used entries are at the front
+	entries map[handleCacheKey]*list.Element // Index of the entries in the list
+}
+
+// handleCacheKey identifies a queue in a handleCache. The queue manager name is
+// included because queues with the same name on different queue managers are
+// different destinations.
+type handleCacheKey struct {
+	qmName    string
+	queueName string
+}
+
+// handleCacheEntry is an individual open queue held in a handleCache.
+type handleCacheEntry struct {
+	key     handleCacheKey
+	qObject mqiObject
+}
+
+// cacheKeyFor returns the key of the queue described by an object descriptor.
+func cacheKeyFor(mqod *ibmmq.MQOD) handleCacheKey {
+	return handleCacheKey{qmName: mqod.ObjectQMgrName, queueName: mqod.ObjectName}
+}
+
+// newHandleCache creates a handleCache that keeps up to maxSize queues open.
+func newHandleCache(maxSize int) *handleCache {
+	return &handleCache{
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: make(map[handleCacheKey]*list.Element),
+	}
+}
+
+// getHandle returns an open handle for the specified queue, opening the queue if
+// it is not already held in the cache.
+func (cache *handleCache) getHandle(qMgr mqiQueueManager, mqod *ibmmq.MQOD) (mqiObject, error) {
+
+	key := cacheKeyFor(mqod)
+
+	if elem, ok := cache.entries[key]; ok {
+		cache.lru.MoveToFront(elem)
+		return elem.Value.(*handleCacheEntry).qObject, nil
+	}
+
+	var openOptions int32
+	openOptions = ibmmq.MQOO_FAIL_IF_QUIESCING
+	openOptions |= ibmmq.MQOO_OUTPUT
+
+	qObject, err := qMgr.Open(mqod, openOptions)
+	if err != nil {
+		return qObject, err
+	}
+
+	// Make room for the new handle by closing the one that has been unused for
+	// the longest.
+	if cache.lru.Len() >= cache.maxSize {
+		cache.closeEntry(cache.lru.Back())
+	}
+
+	entry := &handleCacheEntry{
+		key:     key,
+		qObject: qObject,
+	}
+	cache.entries[key] = cache.lru.PushFront(entry)
+
+	return qObject, nil
+}
+
+// removeHandle closes and discards the cached handle for the specified queue,
+// for example because a call using the handle has failed.
+func (cache *handleCache) removeHandle(mqod *ibmmq.MQOD) {
+
+	if elem, ok := cache.entries[cacheKeyFor(mqod)]; ok {
+		cache.closeEntry(elem)
+	}
+
+}
+
+// closeAll closes every handle in the cache, which is called when the
+// JMSContext that owns the cache is closed.
+func (cache *handleCache) closeAll() {
+
+	for cache.lru.Len() > 0 {
+		cache.closeEntry(cache.lru.Back())
+	}
+
+}
+
+// closeEntry closes the handle held in the specified list element and removes
+// it from the cache.
+func (cache *handleCache) closeEntry(elem *list.Element) {
+
+	entry := cache.lru.Remove(elem).(*handleCacheEntry)
+	delete(cache.entries, entry.key)
+
+	// There is nothing useful that can be done if the close fails, as the handle
+	// is being discarded either way.
+	entry.qObject.Close(0)
+
+}
@@ -0,0 +1,158 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// Logger receives the diagnostic messages that are written by this library, so
+// that applications can send them to the logging framework of their choice.
+//
+// Each message has a level and a set of fields that give the details of the
+// situation, such as the queue manager, queue and MQ reason code involved.
+// Implementations must be safe to call from multiple goroutines.
+type Logger interface {
+	Log(level LogLevel, msg string, fields ...LogField)
+}
+
+// LogLevel describes the importance of a message that is written to a Logger.
+type LogLevel int
+
+// The levels of message that are written to a Logger, from least to most important.
+const (
+	LogLevelTrace LogLevel = iota // Individual calls to the queue manager, see ConnectionFactoryImpl.TraceMQI
+	LogLevelDebug
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// String returns the name of the log level.
+func (level LogLevel) String() string {
+	switch level {
+	case LogLevelTrace:
+		return "TRACE"
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(level))
+}
+
+// LogField is a single named value that gives part of the detail of a log message.
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// The keys of the fields that are included in log messages.
+const (
+	LogFieldQueueManager = "qmgr"  // Name of the queue manager
+	LogFieldQueue        = "queue" // Name of the queue
+	LogFieldMQRC         = "mqrc"  // MQ reason code, where 0 means the call was successful
+	LogFieldVerb         = "verb"  // Name of the MQI call, for example MQPUT1
+	LogFieldValue        = "value" // Value that was supplied by the application
+)
+
+// NewStandardLogger creates a Logger that writes the messages at or above the
+// specified level to a logger from the standard library log package, with the
+// fields of each message appended as key=value pairs.
+func NewStandardLogger(out *log.Logger, minLevel LogLevel) Logger {
+	return &standardLogger{
+		out:      out,
+		minLevel: minLevel,
+	}
+}
+
+// standardLogger is the Logger that is returned by NewStandardLogger.
+type standardLogger struct {
+	out      *log.Logger
+	minLevel LogLevel
+}
+
+// Log writes the message if it is at or above the minimum level of this logger.
+func (logger *standardLogger) Log(level LogLevel, msg string, fields ...LogField) {
+
+	if level < logger.minLevel {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(level.String())
+	sb.WriteString(" ")
+	sb.WriteString(msg)
+
+	for _, field := range fields {
+		sb.WriteString(" ")
+		sb.WriteString(field.Key)
+		sb.WriteString("=")
+		sb.WriteString(fmt.Sprint(field.Value))
+	}
+
+	logger.out.Print(sb.String())
+}
+
+// defaultLogger is used when no Logger has been set on the ConnectionFactoryImpl,
+// and writes warnings and errors to stderr.
+var defaultLogger = NewStandardLogger(log.New(os.Stderr, "mqjms: ", log.LstdFlags), LogLevelWarn)
+
+// loggerOrDefault returns the logger that has been configured, or the default
+// logger if there isn't one.
+func loggerOrDefault(logger Logger) Logger {
+	if logger == nil {
+		return defaultLogger
+	}
+	return logger
+}
+
+// mqrcField returns a LogField holding the MQ reason code of the error returned
+// by a call to the queue manager, which is zero if the call was successful.
+func mqrcField(err error) LogField {
+
+	var mqrc int32
+	if mqret, ok := err.(*ibmmq.MQReturn); ok {
+		mqrc = mqret.MQRC
+	}
+
+	return LogField{Key: LogFieldMQRC, Value: mqrc}
+}
+
+// traceMQICall writes a message to the logger describing a call to the queue
+// manager and its outcome, if MQI tracing has been enabled.
+func traceMQICall(logger Logger, enabled bool, qmName string, verb string, queueName string, err error) {
+
+	if !enabled {
+		return
+	}
+
+	fields := []LogField{
+		{Key: LogFieldVerb, Value: verb},
+		{Key: LogFieldQueueManager, Value: qmName},
+	}
+
+	if queueName != "" {
+		fields = append(fields, LogField{Key: LogFieldQueue, Value: queueName})
+	}
+
+	fields = append(fields, mqrcField(err))
+
+	logger.Log(LogLevelTrace, "MQI call", fields...)
+}
@@ -0,0 +1,167 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// mqiQueueManager is the set of MQI calls that this package makes using a
+// connection to a queue manager.
+//
+// The calls are made through this interface rather than directly on an
+// ibmmq.MQQueueManager so that the paths that depend on the outcome of a call
+// can be tested against a fake implementation, without a queue manager. The
+// errors returned by every implementation are of type *ibmmq.MQReturn.
+type mqiQueueManager interface {
+	Open(mqod *ibmmq.MQOD, openOptions int32) (mqiObject, error)
+	Put1(mqod *ibmmq.MQOD, mqmd *ibmmq.MQMD, pmo *ibmmq.MQPMO, msgHandle mqiMessageHandle, buffer []byte) error
+	CrtMH(cmho *ibmmq.MQCMHO) (mqiMessageHandle, error)
+	Cmit() error
+	Back() error
+	Stat(statusType int32, sts *ibmmq.MQSTS) error
+	Disc() error
+}
+
+// mqiObject is the set of MQI calls that this package makes using an open queue.
+type mqiObject interface {
+	Name() string
+	Get(mqmd *ibmmq.MQMD, gmo *ibmmq.MQGMO, msgHandle mqiMessageHandle, buffer []byte) (int, error)
+	Put(mqmd *ibmmq.MQMD, pmo *ibmmq.MQPMO, msgHandle mqiMessageHandle, buffer []byte) error
+	Close(closeOptions int32) error
+}
+
+// mqiMessageHandle is the set of MQI calls that this package makes using a
+// message handle, which holds the properties of a message.
+type mqiMessageHandle interface {
+	SetMP(smpo *ibmmq.MQSMPO, name string, pd *ibmmq.MQPD, value interface{}) error
+	InqMP(impo *ibmmq.MQIMPO, pd *ibmmq.MQPD, name string) (string, interface{}, error)
+	DltMP(dmpo *ibmmq.MQDMPO, name string) error
+	DltMH(dmho *ibmmq.MQDMHO) error
+}
+
+// connectMQI connects to the named queue manager. It is a variable so that
+// the connection can be replaced by a fake implementation in tests.
+var connectMQI = func(qMgrName string, cno *ibmmq.MQCNO) (mqiQueueManager, error) {
+
+	qMgr, err := ibmmq.Connx(qMgrName, cno)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mqQueueManager{qMgr: qMgr}, nil
+}
+
+// mqQueueManager makes the calls of an mqiQueueManager on a real connection
+// to a queue manager.
+type mqQueueManager struct {
+	qMgr ibmmq.MQQueueManager
+}
+
+// mqObject makes the calls of an mqiObject on a queue that has been opened
+// using a real connection.
+type mqObject struct {
+	object ibmmq.MQObject
+}
+
+// mqMessageHandle makes the calls of an mqiMessageHandle on a message handle
+// that was created using a real connection.
+type mqMessageHandle struct {
+	handle ibmmq.MQMessageHandle
+}
+
+func (x *mqQueueManager) Open(mqod *ibmmq.MQOD, openOptions int32) (mqiObject, error) {
+
+	object, err := x.qMgr.Open(mqod, openOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mqObject{object: object}, nil
+}
+
+func (x *mqQueueManager) Put1(mqod *ibmmq.MQOD, mqmd *ibmmq.MQMD, pmo *ibmmq.MQPMO, msgHandle mqiMessageHandle, buffer []byte) error {
+	setOriginalMsgHandle(pmo, msgHandle)
+	return x.qMgr.Put1(mqod, mqmd, pmo, buffer)
+}
+
+func (x *mqQueueManager) CrtMH(cmho *ibmmq.MQCMHO) (mqiMessageHandle, error) {
+
+	handle, err := x.qMgr.CrtMH(cmho)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mqMessageHandle{handle: handle}, nil
+}
+
+func (x *mqQueueManager) Cmit() error {
+	return x.qMgr.Cmit()
+}
+
+func (x *mqQueueManager) Back() error {
+	return x.qMgr.Back()
+}
+
+func (x *mqQueueManager) Stat(statusType int32, sts *ibmmq.MQSTS) error {
+	return x.qMgr.Stat(statusType, sts)
+}
+
+func (x *mqQueueManager) Disc() error {
+	return x.qMgr.Disc()
+}
+
+func (object *mqObject) Name() string {
+	return object.object.Name
+}
+
+func (object *mqObject) Get(mqmd *ibmmq.MQMD, gmo *ibmmq.MQGMO, msgHandle mqiMessageHandle, buffer []byte) (int, error) {
+
+	if handle, ok := msgHandle.(*mqMessageHandle); ok {
+		gmo.MsgHandle = handle.handle
+	}
+
+	return object.object.Get(mqmd, gmo, buffer)
+}
+
+func (object *mqObject) Put(mqmd *ibmmq.MQMD, pmo *ibmmq.MQPMO, msgHandle mqiMessageHandle, buffer []byte) error {
+	setOriginalMsgHandle(pmo, msgHandle)
+	return object.object.Put(mqmd, pmo, buffer)
+}
+
+func (object *mqObject) Close(closeOptions int32) error {
+	return object.object.Close(closeOptions)
+}
+
+func (handle *mqMessageHandle) SetMP(smpo *ibmmq.MQSMPO, name string, pd *ibmmq.MQPD, value interface{}) error {
+	return handle.handle.SetMP(smpo, name, pd, value)
+}
+
+func (handle *mqMessageHandle) InqMP(impo *ibmmq.MQIMPO, pd *ibmmq.MQPD, name string) (string, interface{}, error) {
+	return handle.handle.InqMP(impo, pd, name)
+}
+
+func (handle *mqMessageHandle) DltMP(dmpo *ibmmq.MQDMPO, name string) error {
+	return handle.handle.DltMP(dmpo, name)
+}
+
+func (handle *mqMessageHandle) DltMH(dmho *ibmmq.MQDMHO) error {
+	return handle.handle.DltMH(dmho)
+}
+
+// setOriginalMsgHandle passes the properties held in the message handle with a
+// message that is being put. A nil handle means that the message is put without
+// any properties.
+func setOriginalMsgHandle(pmo *ibmmq.MQPMO, msgHandle mqiMessageHandle) {
+
+	if handle, ok := msgHandle.(*mqMessageHandle); ok {
+		pmo.OriginalMsgHandle = handle.handle
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// MQOptions configures the IBM MQ connection options (MQCNO) before a connection
+// is made. It implements jms20subset.ConnectionOption, and is the type of the
+// options that are understood by ConnectionFactoryImpl.CreateContext. Settings
+// that do not have a With... function can be made using MQCNOOption.
+//
+// The With... functions below provide options for the commonly tuned channel
+// and connection settings. Options that only apply to client connections have
+// no effect on a bindings connection. The values are checked when the option is
+// created, and an option that was given a value that is not valid causes
+// CreateContext to fail with an MQOptionsError, rather than the connection
+// being made with unexpected settings.
+//
+// Options that the ibmmq package does not expose, such as the connection tag,
+// are not available.
+type MQOptions struct {
+	apply func(cno *ibmmq.MQCNO)
+	err   *MQOptionsError // Set if the option was given a value that is not valid
+}
+
+// Apply applies the option to the MQCNO that is given as the target. It returns
+// an MQOptionsError if the option was given a value that is not valid, or if
+// the target is not an *ibmmq.MQCNO, in which case the target is not changed.
+func (mqo MQOptions) Apply(target interface{}) error {
+
+	if mqo.err != nil {
+		return mqo.err
+	}
+
+	var cno *ibmmq.MQCNO
+	switch settings := target.(type) {
+	case *ibmmq.MQCNO:
+		cno = settings
+	case connectionSettings:
+		cno = settings.cno
+	default:
+		return &MQOptionsError{Option: "MQOptions", Reason: fmt.Sprintf("cannot be applied to %T", target)}
+	}
+
+	if mqo.apply != nil {
+		mqo.apply(cno)
+	}
+	return nil
+}
+
+// MQOptionsError describes an option that was given a value that is not valid.
+type MQOptionsError struct {
+	Option string // Name of the function that created the option
+	Reason string // Description of the problem with the value
+}
+
+// Error returns a description of the invalid option.
+func (e *MQOptionsError) Error() string {
+	return "Invalid value for " + e.Option + ": " + e.Reason
+}
+
+// invalidMQOption returns an option that records the problem with a value, which
+// is reported when the option is applied.
+func invalidMQOption(option string, format string, args ...interface{}) MQOptions {
+	return MQOptions{err: &MQOptionsError{Option: option, Reason: fmt.Sprintf(format, args...)}}
+}
+
+// connectionSettings is the target that the connection options are applied to by
+// CreateContext. As well as the MQOptions, which change the MQCNO, it accepts the
+// options of the jms20subset package that cannot refer to the MQCNO itself.
+type connectionSettings struct {
+	cno *ibmmq.MQCNO
+}
+
+// SetMaxMsgLength is called by the deprecated jms20subset.WithMaxMsgLength
+// option, in the same way as the option returned by WithMaxMsgLength.
+func (settings connectionSettings) SetMaxMsgLength(maxMsgLength int32) error {
+	return WithMaxMsgLength(maxMsgLength).Apply(settings.cno)
+}
+
+// applyMQOptions applies the connection options to the MQCNO in order, returning
+// an MQOptionsError if any of them were given a value that is not valid, or are
+// not options for IBM MQ. The MQCNO is not changed by an option that is rejected.
+func applyMQOptions(cno *ibmmq.MQCNO, opts []jms20subset.ConnectionOption) *MQOptionsError {
+
+	settings := connectionSettings{cno: cno}
+
+	for _, opt := range opts {
+
+		if opt == nil {
+			return &MQOptionsError{Option: "<nil>", Reason: "not an option for IBM MQ"}
+		}
+
+		if err := opt.Apply(settings); err != nil {
+			var optErr *MQOptionsError
+			if errors.As(err, &optErr) {
+				return optErr
+			}
+			return &MQOptionsError{Option: fmt.Sprintf("%T", opt), Reason: "not an option for IBM MQ: " + err.Error()}
+		}
+	}
+
+	return nil
+}
+
+// MQCNOOption returns an option that calls the supplied function to make
+// changes to the MQCNO, for settings that do not have a With... function.
+func MQCNOOption(apply func(cno *ibmmq.MQCNO)) MQOptions {
+	return MQOptions{apply: apply}
+}
+
+// Compress_NONE and the following constants are the compression techniques that
+// can be given to WithHeaderCompression and WithMessageCompression. The channel
+// uses the first technique in the list that is also supported by the queue
+// manager.
+const (
+	Compress_NONE     int = 0
+	Compress_SYSTEM   int = 1 // Header compression only
+	Compress_RLE      int = 2 // Message compression only
+	Compress_ZLIBFAST int = 3 // Message compression only
+	Compress_ZLIBHIGH int = 4 // Message compression only
+	Compress_LZ4FAST  int = 5 // Message compression only
+	Compress_LZ4HIGH  int = 6 // Message compression only
+)
+
+// Reconnect_AS_DEF and the following constants are the automatic client
+// reconnection behaviours that can be given to WithReconnect.
+const (
+	Reconnect_AS_DEF   int = 0 // Use the DefReconnect setting from mqclient.ini, or the channel definition
+	Reconnect_ENABLED  int = 1 // Reconnect to any queue manager in the connection name list
+	Reconnect_Q_MGR    int = 2 // Reconnect only to the same queue manager
+	Reconnect_DISABLED int = 3 // Do not reconnect
+)
+
+// MQ limits for the values of the channel attributes.
+const (
+	maxHeartbeatInterval    = 999999
+	maxKeepAliveInterval    = 99999
+	maxSharingConversations = 999999999
+)
+
+// WithMaxMsgLength sets the maximum length of message that can be sent or
+// received over the client channel, in bytes.
+func WithMaxMsgLength(maxMsgLength int32) MQOptions {
+	if maxMsgLength < 0 {
+		return invalidMQOption("WithMaxMsgLength", "%d must not be negative", maxMsgLength)
+	}
+	return MQOptions{apply: func(cno *ibmmq.MQCNO) {
+		if cno.ClientConn != nil {
+			cno.ClientConn.MaxMsgLength = maxMsgLength
+		}
+	}}
+}
+
+// WithHeartbeatInterval sets the number of seconds between heartbeat flows on
+// the client channel when there is no other traffic, which allows a broken
+// connection to be detected. A value of 0 (zero) disables heartbeats. The
+// queue manager uses the larger of this value and that of the server channel.
+func WithHeartbeatInterval(seconds int32) MQOptions {
+	if seconds < 0 || seconds > maxHeartbeatInterval {
+		return invalidMQOption("WithHeartbeatInterval", "%d must be between 0 and %d", seconds, maxHeartbeatInterval)
+	}
+	return MQOptions{apply: func(cno *ibmmq.MQCNO) {
+		if cno.ClientConn != nil {
+			cno.ClientConn.HeartbeatInterval = seconds
+		}
+	}}
+}
+
+// WithKeepAliveInterval sets the TCP keepalive interval of the client channel
+// in seconds. A value of -1 uses the value negotiated from the heartbeat
+// interval, and 0 (zero) uses the keepalive settings of the operating system.
+func WithKeepAliveInterval(seconds int32) MQOptions {
+	if seconds < ibmmq.MQKAI_AUTO || seconds > maxKeepAliveInterval {
+		return invalidMQOption("WithKeepAliveInterval", "%d must be -1 or between 0 and %d", seconds, maxKeepAliveInterval)
+	}
+	return MQOptions{apply: func(cno *ibmmq.MQCNO) {
+		if cno.ClientConn != nil {
+			cno.ClientConn.KeepAliveInterval = seconds
+		}
+	}}
+}
+
+// WithSharingConversations sets the maximum number of connections that can
+// share one TCP connection to the queue manager. A value of 0 (zero) disables
+// sharing, and with it the bi-directional heartbeats and asynchronous consume
+// behaviour of the client.
+func WithSharingConversations(count int32) MQOptions {
+	if count < 0 || count > maxSharingConversations {
+		return invalidMQOption("WithSharingConversations", "%d must be between 0 and %d", count, maxSharingConversations)
+	}
+	return MQOptions{apply: func(cno *ibmmq.MQCNO) {
+		if cno.ClientConn != nil {
+			cno.ClientConn.SharingConversations = count
+		}
+	}}
+}
+
+// WithHeaderCompression sets the techniques for compressing the headers that
+// flow on the client channel, in order of preference; Compress_NONE or
+// Compress_SYSTEM. At most two techniques can be given.
+func WithHeaderCompression(techniques ...int) MQOptions {
+
+	var list [2]int32
+	err := compressionList("WithHeaderCompression", techniques, list[:], map[int]int32{
+		Compress_NONE:   ibmmq.MQCOMPRESS_NONE,
+		Compress_SYSTEM: ibmmq.MQCOMPRESS_SYSTEM,
+	})
+	if err != nil {
+		return MQOptions{err: err}
+	}
+
+	return MQOptions{apply: func(cno *ibmmq.MQCNO) {
+		if cno.ClientConn != nil {
+			cno.ClientConn.HdrCompList = list
+		}
+	}}
+}
+
+// WithMessageCompression sets the techniques for compressing the message data
+// that flows on the client channel, in order of preference; Compress_NONE,
+// Compress_RLE, Compress_ZLIBFAST, Compress_ZLIBHIGH, Compress_LZ4FAST or
+// Compress_LZ4HIGH. At most sixteen techniques can be given.
+func WithMessageCompression(techniques ...int) MQOptions {
+
+	var list [16]int32
+	err := compressionList("WithMessageCompression", techniques, list[:], map[int]int32{
+		Compress_NONE:     ibmmq.MQCOMPRESS_NONE,
+		Compress_RLE:      ibmmq.MQCOMPRESS_RLE,
+		Compress_ZLIBFAST: ibmmq.MQCOMPRESS_ZLIBFAST,
+		Compress_ZLIBHIGH: ibmmq.MQCOMPRESS_ZLIBHIGH,
+		Compress_LZ4FAST:  ibmmq.MQCOMPRESS_LZ4FAST,
+		Compress_LZ4HIGH:  ibmmq.MQCOMPRESS_LZ4HIGH,
+	})
+	if err != nil {
+		return MQOptions{err: err}
+	}
+
+	return MQOptions{apply: func(cno *ibmmq.MQCNO) {
+		if cno.ClientConn != nil {
+			cno.ClientConn.MsgCompList = list
+		}
+	}}
+}
+
+// compressionList fills in a compression list from the supplied techniques,
+// marking the unused entries as not available. It returns an MQOptionsError if
+// the techniques are not valid.
+func compressionList(option string, techniques []int, list []int32, allowed map[int]int32) *MQOptionsError {
+
+	if len(techniques) == 0 || len(techniques) > len(list) {
+		return &MQOptionsError{Option: option, Reason: fmt.Sprintf("between 1 and %d techniques must be given", len(list))}
+	}
+
+	for i := range list {
+		list[i] = ibmmq.MQCOMPRESS_NOT_AVAILABLE
+	}
+
+	for i, technique := range techniques {
+		value, ok := allowed[technique]
+		if !ok {
+			return &MQOptionsError{Option: option, Reason: fmt.Sprintf("%d is not a supported compression technique", technique)}
+		}
+		list[i] = value
+	}
+
+	return nil
+}
+
+// WithReconnect sets whether the client automatically reconnects if the
+// connection to the queue manager is broken; one of Reconnect_AS_DEF,
+// Reconnect_ENABLED, Reconnect_Q_MGR or Reconnect_DISABLED. The reconnection
+// uses the credentials of the original connection, even if the ConnectionFactory
+// has a CredentialsProvider.
+func WithReconnect(reconnect int) MQOptions {
+
+	var option int32
+	switch reconnect {
+	case Reconnect_AS_DEF:
+		option = ibmmq.MQCNO_RECONNECT_AS_DEF
+	case Reconnect_ENABLED:
+		option = ibmmq.MQCNO_RECONNECT
+	case Reconnect_Q_MGR:
+		option = ibmmq.MQCNO_RECONNECT_Q_MGR
+	case Reconnect_DISABLED:
+		option = ibmmq.MQCNO_RECONNECT_DISABLED
+	default:
+		return invalidMQOption("WithReconnect", "%d is not a supported reconnect option", reconnect)
+	}
+
+	return MQOptions{apply: func(cno *ibmmq.MQCNO) {
+		cno.Options &^= ibmmq.MQCNO_RECONNECT | ibmmq.MQCNO_RECONNECT_Q_MGR | ibmmq.MQCNO_RECONNECT_DISABLED
+		cno.Options |= option
+	}}
+}
+
+// WithApplName sets the name by which the application is identified to the
+// queue manager, for example in DISPLAY CONN. Unlike the ApplName of the
+// ConnectionFactory this also applies to bindings connections. The name can
+// be up to 28 characters long.
+func WithApplName(applName string) MQOptions {
+	if len(applName) > int(ibmmq.MQ_APPL_NAME_LENGTH) {
+		return invalidMQOption("WithApplName", "%q is longer than %d characters", applName, ibmmq.MQ_APPL_NAME_LENGTH)
+	}
+	return MQOptions{apply: func(cno *ibmmq.MQCNO) {
+		cno.ApplName = applName
+	}}
+}
+
+// WithCCDTURL connects using the client channel definition table at the given
+// location (a file path or a file://, http:// or ftp:// URL), in place of the
+// hostname, port and channel of the ConnectionFactory. The other channel
+// settings are then taken from the table, so options such as
+// WithHeartbeatInterval have no effect if they are applied after this one.
+func WithCCDTURL(url string) MQOptions {
+	if url == "" {
+		return invalidMQOption("WithCCDTURL", "the URL must not be empty")
+	}
+	return MQOptions{apply: func(cno *ibmmq.MQCNO) {
+		cno.CCDTUrl = url
+		cno.ClientConn = nil
+	}}
+}
+
+// WithFastPathBinding requests a fastpath bindings connection, in which the
+// application runs as a trusted part of the queue manager. It has no effect on
+// client connections.
+func WithFastPathBinding() MQOptions {
+	return MQOptions{apply: func(cno *ibmmq.MQCNO) {
+		if cno.Options&ibmmq.MQCNO_CLIENT_BINDING == 0 {
+			cno.Options &^= ibmmq.MQCNO_STANDARD_BINDING | ibmmq.MQCNO_SHARED_BINDING | ibmmq.MQCNO_ISOLATED_BINDING
+			cno.Options |= ibmmq.MQCNO_FASTPATH_BINDING
+		}
+	}}
+}
@@ -0,0 +1,179 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"runtime"
+	"sync"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// maxClearedProperties is the largest number of properties that are deleted from a
+// message handle so that it can be reused. Clearing a handle takes an MQINQMP and
+// an MQDLTMP call for each property, so beyond this it is cheaper to delete the
+// handle and create a new one.
+const maxClearedProperties = 8
+
+// msgHandlePool holds the message handles that have been released by the
+// messages of a JMSContext, so that they can be reused by new messages rather
+// than creating a new handle (MQCRTMH) for every message that is sent or received.
+//
+// Handles that are released when the pool is full are deleted (MQDLTMH) straight
+// away, as are handles that hold more than maxClearedProperties properties, since
+// deleting the properties one at a time would cost more than creating a new
+// handle. The pool is not safe for concurrent use by itself, so the caller must hold
+// the context lock when calling its methods.
+type msgHandlePool struct {
+	maxSize int
+	handles []mqiMessageHandle
+	ctxLock *sync.Mutex // Lock of the owning context, which is used by the finalizer
+	logger  Logger
+}
+
+// newMsgHandlePool creates a msgHandlePool that holds up to maxSize message handles.
+func newMsgHandlePool(maxSize int, ctxLock *sync.Mutex, logger Logger) *msgHandlePool {
+	return &msgHandlePool{
+		maxSize: maxSize,
+		handles: make([]mqiMessageHandle, 0, maxSize),
+		ctxLock: ctxLock,
+		logger:  logger,
+	}
+}
+
+// getHandle returns a message handle that has no properties set, taking one from
+// the pool if there is one available, or creating a new one if not.
+//
+// A finalizer is set on the returned handle, which deletes the handle if the
+// message that holds it is discarded without calling Release. This is only a
+// safety net; applications should call Release so that the handle is deleted
+// or reused at a predictable time.
+func (pool *msgHandlePool) getHandle(qMgr mqiQueueManager) (mqiMessageHandle, error) {
+
+	var msgHandle mqiMessageHandle
+
+	if len(pool.handles) > 0 {
+		msgHandle = pool.handles[len(pool.handles)-1]
+		pool.handles = pool.handles[:len(pool.handles)-1]
+	} else {
+		cmho := ibmmq.NewMQCMHO()
+		newHandle, err := qMgr.CrtMH(cmho)
+		if err != nil {
+			return nil, err
+		}
+		msgHandle = newHandle
+	}
+
+	setMessageHandleFinalizer(msgHandle, pool.ctxLock, pool.logger)
+
+	return msgHandle, nil
+}
+
+// putHandle returns a message handle to the pool once the message that held it
+// has been released, or deletes the handle if the pool is full.
+func (pool *msgHandlePool) putHandle(msgHandle mqiMessageHandle) {
+
+	// The handle is now being managed explicitly, so the finalizer is no longer needed.
+	runtime.SetFinalizer(msgHandle, nil)
+
+	// Only reuse the handle if all the properties of the previous message have
+	// been removed from it successfully.
+	if len(pool.handles) < pool.maxSize && clearMsgHandle(msgHandle) {
+		pool.handles = append(pool.handles, msgHandle)
+		return
+	}
+
+	// There is nothing useful that can be done if the delete fails, as the handle
+	// is being discarded either way.
+	dmho := ibmmq.NewMQDMHO()
+	msgHandle.DltMH(dmho)
+
+}
+
+// deleteAll deletes every handle in the pool, which is called when the
+// JMSContext that owns the pool is closed. Any handles that are released
+// after this point are deleted rather than kept for reuse.
+func (pool *msgHandlePool) deleteAll() {
+
+	for i := range pool.handles {
+		dmho := ibmmq.NewMQDMHO()
+		pool.handles[i].DltMH(dmho)
+	}
+
+	pool.handles = pool.handles[:0]
+	pool.maxSize = 0
+
+}
+
+// clearMsgHandle deletes all of the properties from a message handle so that it
+// can be used for a different message. It returns false if the properties could
+// not be deleted, or if there are more than maxClearedProperties of them, in
+// which case the handle should be deleted rather than reused.
+func clearMsgHandle(msgHandle mqiMessageHandle) bool {
+
+	impo := ibmmq.NewMQIMPO()
+	pd := ibmmq.NewMQPD()
+	dmpo := ibmmq.NewMQDMPO()
+
+	// Always ask for the first property, as the previous one has been deleted
+	// before asking again.
+	impo.Options = ibmmq.MQIMPO_INQ_FIRST
+
+	for cleared := 0; ; cleared++ {
+		name, _, err := msgHandle.InqMP(impo, pd, "%")
+
+		if err != nil {
+			// There are no properties left on the handle if the property is not
+			// available, otherwise the inquire failed.
+			mqret, ok := err.(*ibmmq.MQReturn)
+			return ok && mqret.MQRC == ibmmq.MQRC_PROPERTY_NOT_AVAILABLE
+		}
+
+		if cleared == maxClearedProperties {
+			return false
+		}
+
+		err = msgHandle.DltMP(dmpo, name)
+		if err != nil {
+			return false
+		}
+	}
+
+}
+
+/*
+ * Set a finalizer on the message handle to allow it to be deleted
+ * when it is no longer referenced by an active object, to reduce/prevent
+ * memory leaks.
+ */
+func setMessageHandleFinalizer(msgHandle mqiMessageHandle, ctxLock *sync.Mutex, logger Logger) {
+
+	runtime.SetFinalizer(msgHandle, func(msgHandle mqiMessageHandle) {
+		ctxLock.Lock()
+		defer ctxLock.Unlock()
+
+		dmho := ibmmq.NewMQDMHO()
+		err := msgHandle.DltMH(dmho)
+		if err != nil {
+
+			mqret := err.(*ibmmq.MQReturn)
+
+			if mqret.MQRC == ibmmq.MQRC_HCONN_ERROR {
+				// Expected if the connection is closed before the finalizer executes
+				// (at which point it should get tidied up automatically by the connection)
+			} else {
+				logger.Log(LogLevelWarn, "Failed to delete message handle in finalizer", mqrcField(err))
+			}
+
+		}
+
+	})
+
+}
@@ -13,7 +13,6 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 	"sync"
@@ -31,9 +30,75 @@ const MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_CODE string = "1056	"
 // MessageImpl contains the IBM MQ specific attributes that are
 // common to all types of message.
 type MessageImpl struct {
-	mqmd      *ibmmq.MQMD
-	msgHandle *ibmmq.MQMessageHandle
-	ctxLock   *sync.Mutex
+	mqmd       *ibmmq.MQMD
+	msgHandle  mqiMessageHandle // Holds the message properties, or nil once released
+	handlePool *msgHandlePool   // Pool of the context, to which the handle is released
+	ctxLock    *sync.Mutex
+	logger     Logger
+}
+
+// Release gives back the message handle that holds the properties of this message,
+// so that it can be reused by another message or deleted straight away, rather than
+// waiting for the garbage collector to find that the message is no longer in use.
+//
+// The properties of the message cannot be used once it has been released, and
+// calls to do so return an MQRC_HMSG_ERROR. Calling Release more than once has
+// no further effect.
+func (msg *MessageImpl) Release() {
+
+	// Lock the context while we are making calls to the queue manager so that it
+	// doesn't conflict with other goroutines using the same context.
+	msg.ctxLock.Lock()
+	defer msg.ctxLock.Unlock()
+
+	if msg.msgHandle == nil {
+		return
+	}
+
+	msg.handlePool.putHandle(msg.msgHandle)
+	msg.msgHandle = nil
+
+}
+
+// getMsgHandle returns the message handle that holds the properties of this
+// message, or an MQRC_HMSG_ERROR if the message has been released.
+func (msg *MessageImpl) getMsgHandle() (mqiMessageHandle, error) {
+
+	if msg.msgHandle == nil {
+		return nil, &ibmmq.MQReturn{
+			MQCC: ibmmq.MQCC_FAILED,
+			MQRC: ibmmq.MQRC_HMSG_ERROR,
+		}
+	}
+
+	return msg.msgHandle, nil
+}
+
+// messageMQMD returns the MQMD of a message from this provider, or nil if the
+// message does not have one.
+func messageMQMD(msg jms20subset.Message) *ibmmq.MQMD {
+
+	switch typedMsg := msg.(type) {
+	case *TextMessageImpl:
+		return typedMsg.mqmd
+	case *BytesMessageImpl:
+		return typedMsg.mqmd
+	}
+
+	return nil
+}
+
+// isZeroID returns whether an ID such as a group ID consists only of zeros,
+// which means that it has not been set.
+func isZeroID(id []byte) bool {
+
+	for _, thisByte := range id {
+		if thisByte != 0 {
+			return false
+		}
+	}
+
+	return true
 }
 
 // GetJMSDeliveryMode extracts the persistence setting from this message
@@ -51,7 +116,8 @@ func (msg *MessageImpl) GetJMSDeliveryMode() int {
 		jmsPersistence = jms20subset.DeliveryMode_PERSISTENT
 	} else {
 		// Give some indication if we received something we didn't expect.
-		fmt.Println("Unexpected persistence value: " + strconv.Itoa(int(mqMsgPersistence)))
+		loggerOrDefault(msg.logger).Log(LogLevelWarn, "Unexpected persistence value",
+			LogField{Key: LogFieldValue, Value: mqMsgPersistence})
 	}
 
 	return jmsPersistence
@@ -100,14 +166,14 @@ func (msg *MessageImpl) SetJMSReplyTo(dest jms20subset.Destination) jms20subset.
 
 		// Save the queue information into the MQMD so that it can be transmitted.
 		msg.mqmd.ReplyToQ = typedDest.queueName
+		msg.mqmd.ReplyToQMgr = typedDest.queueManagerName
 
 	default:
 		// This "should never happen"(!) apart from in situations where we are
 		// part way through adding support for a new destination type to this library.
-		log.Fatal(jms20subset.CreateJMSException("UnexpectedDestinationType", "UnexpectedDestinationType", nil))
+		return jms20subset.CreateInvalidDestinationException("UnexpectedDestinationType", "UnexpectedDestinationType", nil)
 	}
 
-	// The option to return an error is not currently used.
 	return nil
 }
 
@@ -122,10 +188,14 @@ func (msg *MessageImpl) GetJMSReplyTo() jms20subset.Destination {
 	// destination.
 	if msg.mqmd != nil && msg.mqmd.ReplyToQ != "" {
 		replyQ := strings.TrimSpace(msg.mqmd.ReplyToQ)
+		replyQMgr := strings.TrimSpace(msg.mqmd.ReplyToQMgr)
 
 		// Create the Destination object and populate it to be returned.
 		replyDest = QueueImpl{
-			queueName: replyQ,
+			queueName:        replyQ,
+			queueManagerName: replyQMgr,
+			deliveryMode:     Destination_AS_PRODUCER,
+			priority:         Destination_AS_PRODUCER,
 		}
 	}
 
@@ -318,7 +388,11 @@ func (msg *MessageImpl) SetStringProperty(name string, value *string) jms20subse
 	msg.ctxLock.Lock()
 	defer msg.ctxLock.Unlock()
 
-	if value != nil {
+	msgHandle, linkedErr := msg.getMsgHandle()
+
+	if linkedErr != nil {
+		// The message has been released.
+	} else if value != nil {
 		// Looking to set a value
 		var valueStr string
 		valueStr = *value
@@ -326,19 +400,16 @@ func (msg *MessageImpl) SetStringProperty(name string, value *string) jms20subse
 		smpo := ibmmq.NewMQSMPO()
 		pd := ibmmq.NewMQPD()
 
-		linkedErr = msg.msgHandle.SetMP(smpo, name, pd, valueStr)
+		linkedErr = msgHandle.SetMP(smpo, name, pd, valueStr)
 	} else {
 		// Looking to unset a value
 		dmpo := ibmmq.NewMQDMPO()
 
-		linkedErr = msg.msgHandle.DltMP(dmpo, name)
+		linkedErr = msgHandle.DltMP(dmpo, name)
 	}
 
 	if linkedErr != nil {
-		rcInt := int(linkedErr.(*ibmmq.MQReturn).MQRC)
-		errCode := strconv.Itoa(rcInt)
-		reason := ibmmq.MQItoString("RC", rcInt)
-		retErr = jms20subset.CreateJMSException(reason, errCode, linkedErr)
+		retErr = createJMSExceptionFromMQReturn(linkedErr)
 	}
 
 	return retErr
@@ -527,16 +598,10 @@ func (msg *MessageImpl) getSpecialPropertyValue(name string) (bool, interface{},
 		if msg.mqmd != nil {
 			valueBytes := msg.mqmd.GroupId
 
-			// See whether this is a non-zero response.
-			nonZeros := false
-			for _, thisByte := range valueBytes {
-				if thisByte != 0 {
-					nonZeros = true
-					break
-				}
-			}
-
-			if nonZeros {
+			// See whether this is a non-zero response. A segmented message has a
+			// group ID even when it is not part of a group.
+			inGroup := msg.mqmd.MsgFlags&(ibmmq.MQMF_MSG_IN_GROUP|ibmmq.MQMF_LAST_MSG_IN_GROUP) != 0
+			if inGroup && !isZeroID(valueBytes) {
 				value = hex.EncodeToString(valueBytes)
 			}
 		}
@@ -583,7 +648,11 @@ func (msg *MessageImpl) GetStringProperty(name string) (*string, jms20subset.JMS
 		defer msg.ctxLock.Unlock()
 
 		// If not then look for a user property
-		_, value, err = msg.msgHandle.InqMP(impo, pd, name)
+		var msgHandle mqiMessageHandle
+		msgHandle, err = msg.getMsgHandle()
+		if err == nil {
+			_, value, err = msgHandle.InqMP(impo, pd, name)
+		}
 	}
 
 	if err == nil {
@@ -599,7 +668,7 @@ func (msg *MessageImpl) GetStringProperty(name string) (*string, jms20subset.JMS
 				valueStr := strconv.FormatInt(valueTyped, 10)
 				valueStrPtr = &valueStr
 				if parseErr != nil {
-					retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
+					retErr = jms20subset.CreateMessageFormatException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
 						MessageImpl_PROPERTY_CONVERT_FAILED_CODE, parseErr)
 				}
 			case bool:
@@ -609,7 +678,7 @@ func (msg *MessageImpl) GetStringProperty(name string) (*string, jms20subset.JMS
 				valueStr := fmt.Sprintf("%g", valueTyped)
 				valueStrPtr = &valueStr
 			default:
-				retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
+				retErr = jms20subset.CreateMessageFormatException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
 					MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_CODE, parseErr)
 			}
 
@@ -624,10 +693,7 @@ func (msg *MessageImpl) GetStringProperty(name string) (*string, jms20subset.JMS
 			return nil, nil
 		} else {
 			// Err was not nil
-			rcInt := int(mqret.MQRC)
-			errCode := strconv.Itoa(rcInt)
-			reason := ibmmq.MQItoString("RC", rcInt)
-			retErr = jms20subset.CreateJMSException(reason, errCode, mqret)
+			retErr = createJMSExceptionFromMQReturn(mqret)
 
 			valueStrPtr = nil
 		}
@@ -659,13 +725,13 @@ func (msg *MessageImpl) SetIntProperty(name string, value int) jms20subset.JMSEx
 	msg.ctxLock.Lock()
 	defer msg.ctxLock.Unlock()
 
-	linkedErr = msg.msgHandle.SetMP(smpo, name, pd, value)
+	msgHandle, linkedErr := msg.getMsgHandle()
+	if linkedErr == nil {
+		linkedErr = msgHandle.SetMP(smpo, name, pd, value)
+	}
 
 	if linkedErr != nil {
-		rcInt := int(linkedErr.(*ibmmq.MQReturn).MQRC)
-		errCode := strconv.Itoa(rcInt)
-		reason := ibmmq.MQItoString("RC", rcInt)
-		retErr = jms20subset.CreateJMSException(reason, errCode, linkedErr)
+		retErr = createJMSExceptionFromMQReturn(linkedErr)
 	}
 
 	return retErr
@@ -692,7 +758,11 @@ func (msg *MessageImpl) GetIntProperty(name string) (int, jms20subset.JMSExcepti
 		defer msg.ctxLock.Unlock()
 
 		// If not then look for a user property
-		_, value, err = msg.msgHandle.InqMP(impo, pd, name)
+		var msgHandle mqiMessageHandle
+		msgHandle, err = msg.getMsgHandle()
+		if err == nil {
+			_, value, err = msgHandle.InqMP(impo, pd, name)
+		}
 	}
 
 	if err == nil {
@@ -716,12 +786,12 @@ func (msg *MessageImpl) GetIntProperty(name string) (int, jms20subset.JMSExcepti
 			s := fmt.Sprintf("%.0f", valueTyped)
 			valueRet, parseErr = strconv.Atoi(s)
 		default:
-			retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
+			retErr = jms20subset.CreateMessageFormatException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
 				MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_CODE, parseErr)
 		}
 
 		if parseErr != nil {
-			retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
+			retErr = jms20subset.CreateMessageFormatException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
 				MessageImpl_PROPERTY_CONVERT_FAILED_CODE, parseErr)
 		}
 
@@ -734,10 +804,7 @@ func (msg *MessageImpl) GetIntProperty(name string) (int, jms20subset.JMSExcepti
 			return 0, nil
 		} else {
 			// Err was not nil
-			rcInt := int(mqret.MQRC)
-			errCode := strconv.Itoa(rcInt)
-			reason := ibmmq.MQItoString("RC", rcInt)
-			retErr = jms20subset.CreateJMSException(reason, errCode, mqret)
+			retErr = createJMSExceptionFromMQReturn(mqret)
 		}
 	}
 	return valueRet, retErr
@@ -757,13 +824,13 @@ func (msg *MessageImpl) SetDoubleProperty(name string, value float64) jms20subse
 	msg.ctxLock.Lock()
 	defer msg.ctxLock.Unlock()
 
-	linkedErr = msg.msgHandle.SetMP(smpo, name, pd, value)
+	msgHandle, linkedErr := msg.getMsgHandle()
+	if linkedErr == nil {
+		linkedErr = msgHandle.SetMP(smpo, name, pd, value)
+	}
 
 	if linkedErr != nil {
-		rcInt := int(linkedErr.(*ibmmq.MQReturn).MQRC)
-		errCode := strconv.Itoa(rcInt)
-		reason := ibmmq.MQItoString("RC", rcInt)
-		retErr = jms20subset.CreateJMSException(reason, errCode, linkedErr)
+		retErr = createJMSExceptionFromMQReturn(linkedErr)
 	}
 
 	return retErr
@@ -790,7 +857,11 @@ func (msg *MessageImpl) GetDoubleProperty(name string) (float64, jms20subset.JMS
 		defer msg.ctxLock.Unlock()
 
 		// If not then look for a user property
-		_, value, err = msg.msgHandle.InqMP(impo, pd, name)
+		var msgHandle mqiMessageHandle
+		msgHandle, err = msg.getMsgHandle()
+		if err == nil {
+			_, value, err = msgHandle.InqMP(impo, pd, name)
+		}
 	}
 
 	if err == nil {
@@ -803,7 +874,7 @@ func (msg *MessageImpl) GetDoubleProperty(name string) (float64, jms20subset.JMS
 		case string:
 			valueRet, parseErr = strconv.ParseFloat(valueTyped, 64)
 			if parseErr != nil {
-				retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
+				retErr = jms20subset.CreateMessageFormatException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
 					MessageImpl_PROPERTY_CONVERT_FAILED_CODE, parseErr)
 			}
 		case int64:
@@ -813,7 +884,7 @@ func (msg *MessageImpl) GetDoubleProperty(name string) (float64, jms20subset.JMS
 				valueRet = 1
 			}
 		default:
-			retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
+			retErr = jms20subset.CreateMessageFormatException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
 				MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_CODE, parseErr)
 		}
 	} else {
@@ -825,10 +896,7 @@ func (msg *MessageImpl) GetDoubleProperty(name string) (float64, jms20subset.JMS
 			return 0, nil
 		} else {
 			// Err was not nil
-			rcInt := int(mqret.MQRC)
-			errCode := strconv.Itoa(rcInt)
-			reason := ibmmq.MQItoString("RC", rcInt)
-			retErr = jms20subset.CreateJMSException(reason, errCode, mqret)
+			retErr = createJMSExceptionFromMQReturn(mqret)
 		}
 	}
 	return valueRet, retErr
@@ -858,13 +926,13 @@ func (msg *MessageImpl) SetBooleanProperty(name string, value bool) jms20subset.
 	msg.ctxLock.Lock()
 	defer msg.ctxLock.Unlock()
 
-	linkedErr = msg.msgHandle.SetMP(smpo, name, pd, value)
+	msgHandle, linkedErr := msg.getMsgHandle()
+	if linkedErr == nil {
+		linkedErr = msgHandle.SetMP(smpo, name, pd, value)
+	}
 
 	if linkedErr != nil {
-		rcInt := int(linkedErr.(*ibmmq.MQReturn).MQRC)
-		errCode := strconv.Itoa(rcInt)
-		reason := ibmmq.MQItoString("RC", rcInt)
-		retErr = jms20subset.CreateJMSException(reason, errCode, linkedErr)
+		retErr = createJMSExceptionFromMQReturn(linkedErr)
 	}
 
 	return retErr
@@ -922,7 +990,11 @@ func (msg *MessageImpl) GetBooleanProperty(name string) (bool, jms20subset.JMSEx
 		defer msg.ctxLock.Unlock()
 
 		// If not then look for a user property
-		_, value, err = msg.msgHandle.InqMP(impo, pd, name)
+		var msgHandle mqiMessageHandle
+		msgHandle, err = msg.getMsgHandle()
+		if err == nil {
+			_, value, err = msgHandle.InqMP(impo, pd, name)
+		}
 	}
 
 	if err == nil {
@@ -935,7 +1007,7 @@ func (msg *MessageImpl) GetBooleanProperty(name string) (bool, jms20subset.JMSEx
 		case string:
 			valueRet, parseErr = strconv.ParseBool(valueTyped)
 			if parseErr != nil {
-				retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
+				retErr = jms20subset.CreateMessageFormatException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
 					MessageImpl_PROPERTY_CONVERT_FAILED_CODE, parseErr)
 			}
 		case int64:
@@ -949,7 +1021,7 @@ func (msg *MessageImpl) GetBooleanProperty(name string) (bool, jms20subset.JMSEx
 				valueRet = true
 			}
 		default:
-			retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
+			retErr = jms20subset.CreateMessageFormatException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
 				MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_CODE, parseErr)
 		}
 	} else {
@@ -961,10 +1033,7 @@ func (msg *MessageImpl) GetBooleanProperty(name string) (bool, jms20subset.JMSEx
 			return false, nil
 		} else {
 			// Err was not nil
-			rcInt := int(mqret.MQRC)
-			errCode := strconv.Itoa(rcInt)
-			reason := ibmmq.MQItoString("RC", rcInt)
-			retErr = jms20subset.CreateJMSException(reason, errCode, mqret)
+			retErr = createJMSExceptionFromMQReturn(mqret)
 		}
 	}
 	return valueRet, retErr
@@ -1002,20 +1071,22 @@ func (msg *MessageImpl) getPropertiesInternal(name string) (bool, []string, jms2
 	msg.ctxLock.Lock()
 	defer msg.ctxLock.Unlock()
 
+	msgHandle, err := msg.getMsgHandle()
+	if err != nil {
+		return false, nil, createJMSExceptionFromMQReturn(err)
+	}
+
 	impo.Options = ibmmq.MQIMPO_CONVERT_VALUE | ibmmq.MQIMPO_INQ_FIRST
 	for propsToRead := true; propsToRead; {
 
-		gotName, _, err := msg.msgHandle.InqMP(impo, pd, "%")
+		gotName, _, err := msgHandle.InqMP(impo, pd, "%")
 		impo.Options = ibmmq.MQIMPO_CONVERT_VALUE | ibmmq.MQIMPO_INQ_NEXT
 
 		if err != nil {
 			mqret := err.(*ibmmq.MQReturn)
 			if mqret.MQRC != ibmmq.MQRC_PROPERTY_NOT_AVAILABLE {
 
-				rcInt := int(mqret.MQRC)
-				errCode := strconv.Itoa(rcInt)
-				reason := ibmmq.MQItoString("RC", rcInt)
-				retErr := jms20subset.CreateJMSException(reason, errCode, mqret)
+				retErr := createJMSExceptionFromMQReturn(mqret)
 				return false, nil, retErr
 
 			} else {
@@ -1054,16 +1125,17 @@ func (msg *MessageImpl) ClearProperties() jms20subset.JMSException {
 		msg.ctxLock.Lock()
 		defer msg.ctxLock.Unlock()
 
+		msgHandle, err := msg.getMsgHandle()
+
 		for _, propName := range allPropNames {
 
 			// Delete this property
-			err := msg.msgHandle.DltMP(dmpo, propName)
+			if err == nil {
+				err = msgHandle.DltMP(dmpo, propName)
+			}
 
 			if err != nil {
-				rcInt := int(err.(*ibmmq.MQReturn).MQRC)
-				errCode := strconv.Itoa(rcInt)
-				reason := ibmmq.MQItoString("RC", rcInt)
-				jmsErr = jms20subset.CreateJMSException(reason, errCode, err)
+				jmsErr = createJMSExceptionFromMQReturn(err)
 				break
 			}
 		}
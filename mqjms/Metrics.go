@@ -0,0 +1,93 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"time"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// Metrics receives measurements of the messaging activity of the JMSContexts
+// that are created by a ConnectionFactoryImpl, so that applications can monitor
+// throughput, latency and errors without wrapping every call to the library.
+//
+// The methods are called while the JMSContext is locked, so they should return
+// quickly, and must be safe to call from multiple goroutines as each JMSContext
+// reports to the same Metrics. See NewPrometheusMetrics for an implementation.
+type Metrics interface {
+
+	// MessageSent is called when a message has been put to a queue, with the
+	// size of the message body in bytes.
+	MessageSent(queueName string, bodySize int)
+
+	// MessageReceived is called when a message has been received or browsed
+	// from a queue, with the size of the message body in bytes.
+	MessageReceived(queueName string, bodySize int)
+
+	// MQIError is called when a call to the queue manager returns a reason code
+	// other than MQRC_NONE, apart from MQRC_NO_MSG_AVAILABLE which is the normal
+	// outcome of a receive when there is no message on the queue.
+	MQIError(verb string, mqrc int32)
+
+	// AsyncPutStatus is called when the outcome of asynchronous puts has been
+	// checked with the queue manager, with the number of messages that have
+	// been reported as warnings and failures since the previous check.
+	AsyncPutStatus(warnings int32, failures int32)
+
+	// Committed is called when a transaction has been committed successfully.
+	Committed()
+
+	// RolledBack is called when a transaction has been rolled back by the application.
+	RolledBack()
+
+	// PutLatency is called with the time taken by each call to put a message
+	// (MQPUT1, or MQPUT when the queue handle is cached), whether or not it succeeded.
+	PutLatency(queueName string, d time.Duration)
+
+	// GetLatency is called with the time taken to receive each message, from
+	// the start of the receive until the message was returned by the queue
+	// manager, including the time spent waiting for the message to arrive.
+	GetLatency(queueName string, d time.Duration)
+}
+
+// noopMetrics is used when no Metrics has been set on the ConnectionFactoryImpl,
+// and discards the measurements.
+type noopMetrics struct{}
+
+func (noopMetrics) MessageSent(queueName string, bodySize int)     {}
+func (noopMetrics) MessageReceived(queueName string, bodySize int) {}
+func (noopMetrics) MQIError(verb string, mqrc int32)               {}
+func (noopMetrics) AsyncPutStatus(warnings int32, failures int32)  {}
+func (noopMetrics) Committed()                                     {}
+func (noopMetrics) RolledBack()                                    {}
+func (noopMetrics) PutLatency(queueName string, d time.Duration)   {}
+func (noopMetrics) GetLatency(queueName string, d time.Duration)   {}
+
+// metricsOrDefault returns the metrics that have been configured, or metrics
+// that discard the measurements if there aren't any.
+func metricsOrDefault(metrics Metrics) Metrics {
+	if metrics == nil {
+		return noopMetrics{}
+	}
+	return metrics
+}
+
+// recordMQIError counts a failed call to the queue manager in the metrics.
+// Nothing is counted if the call was successful, or if a get found no message.
+func recordMQIError(metrics Metrics, verb string, err error) {
+
+	mqret, ok := err.(*ibmmq.MQReturn)
+	if !ok || mqret.MQRC == ibmmq.MQRC_NONE || mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+		return
+	}
+
+	metrics.MQIError(verb, mqret.MQRC)
+}
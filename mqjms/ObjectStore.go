@@ -0,0 +1,327 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// ObjectStore holds named ConnectionFactory and Destination definitions that
+// are read from a file, in the same way as the administered objects that are
+// looked up using JNDI in Java JMS, so that the queue manager and queues used
+// by an application can be changed by editing the file rather than the code.
+//
+// The file is JSON or YAML (chosen by the extension of its name), with the
+// following structure;
+//
+//	{
+//	  "connectionFactories": {
+//	    "jms/OrdersCF": { "queueManager": "QM1", "hostname": "myhost", "port": 1414, ... }
+//	  },
+//	  "destinations": {
+//	    "jms/OrdersQ": { "queueName": "ORDERS", "queueManager": "QM2", "persistence": "PERSISTENT" }
+//	  }
+//	}
+//
+// Each connection factory has the same settings as the configuration file that
+// is read by LoadConnectionFactory, including reading secrets from files, but
+// is not affected by environment variables. Each destination has the following
+// settings, of which only queueName is required;
+//   - queueName: name of the queue
+//   - queueManager: queue manager that hosts the queue, if it is not the one
+//     the application is connected to
+//   - persistence: APP (use the setting of the producer), PERSISTENT or NON_PERSISTENT
+//   - priority: APP (use the setting of the producer) or 0-9
+//   - targetClient: JMS (send message properties) or MQ (send only the body)
+//   - putAsyncAllowed: AS_DEST, ENABLED or DISABLED
+//
+// See config-samples/object_store.yaml for an example.
+//
+// The file can instead be a .bindings file written by the JNDI file system
+// context (for example using JMSAdmin or MQ Explorer), so that Go and Java
+// applications can look up the same administered objects. The connection
+// factories (of any of the IBM MQ connection factory classes) and queues are
+// read from it, and other objects such as topics are ignored. The following
+// JMSAdmin properties are used, and the others are ignored;
+//   - connection factory: QMANAGER, HOSTNAME, PORT, CHANNEL, TRANSPORT (BIND
+//     or CLIENT) and SSLCIPHERSUITE, which must name a CipherSpec that the
+//     queue manager accepts
+//   - queue: QUEUE, QMANAGER, PERSISTENCE (APP, PERS or NON), PRIORITY (APP or
+//     0-9), TARGCLIENT and PUTASYNCALLOWED
+//
+// A property value that this library has no equivalent for, such as a
+// PERSISTENCE of QDEF, causes an error. The .bindings file is only read, and
+// is not changed by this library.
+type ObjectStore struct {
+	lock         sync.RWMutex
+	storeFile    string
+	factories    map[string]ConnectionFactoryImpl
+	destinations map[string]QueueImpl
+}
+
+// The sections of the object store file.
+const (
+	objectStoreFactoriesKey    = "connectionFactories"
+	objectStoreDestinationsKey = "destinations"
+)
+
+// The values of the settings of a destination in the object store file, which
+// describe the same options as the properties of an MQQueue in Java JMS.
+const (
+	objectStoreAsApp         = "APP"
+	objectStorePersistent    = "PERSISTENT"
+	objectStoreNonPersistent = "NON_PERSISTENT"
+	objectStoreTargetJMS     = "JMS"
+	objectStoreTargetMQ      = "MQ"
+	objectStoreAsyncEnabled  = "ENABLED"
+	objectStoreAsyncDisabled = "DISABLED"
+	objectStoreAsyncAsDest   = "AS_DEST"
+)
+
+// LoadObjectStore creates an ObjectStore containing the definitions in the
+// specified JSON, YAML or .bindings file. An error is returned if the file cannot be read
+// or contains a definition that is not valid.
+func LoadObjectStore(storeFile string) (*ObjectStore, error) {
+
+	store := &ObjectStore{storeFile: storeFile}
+
+	err := store.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Reload reads the file again, so that changes to the definitions are used by
+// later lookups without restarting the application. Objects that have already
+// been looked up are not affected. If the file is not valid then an error is
+// returned and the previous definitions are kept.
+func (store *ObjectStore) Reload() error {
+
+	var factoryDefs, destinationDefs map[string]map[string]interface{}
+	var err error
+
+	if isBindingsFile(store.storeFile) {
+		factoryDefs, destinationDefs, err = readBindingsFile(store.storeFile)
+	} else {
+		factoryDefs, destinationDefs, err = readObjectStoreFile(store.storeFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	factories := make(map[string]ConnectionFactoryImpl, len(factoryDefs))
+	for name, def := range factoryDefs {
+
+		source := store.storeFile + " " + name
+
+		values, err := configValues(def, connectionFactoryKeys(), source)
+		if err != nil {
+			return err
+		}
+
+		cf := ConnectionFactoryImpl{}
+		err = applyConfigSettings(&cf, mapLookup(values), false, source)
+		if err != nil {
+			return err
+		}
+
+		factories[name] = cf
+	}
+
+	destinations := make(map[string]QueueImpl, len(destinationDefs))
+	for name, def := range destinationDefs {
+
+		queue, err := parseDestination(def, store.storeFile+" "+name)
+		if err != nil {
+			return err
+		}
+
+		destinations[name] = queue
+	}
+
+	store.lock.Lock()
+	store.factories = factories
+	store.destinations = destinations
+	store.lock.Unlock()
+
+	return nil
+}
+
+// Lookup returns the connection factory with the specified name, or an error if
+// there is no connection factory with that name.
+//
+// The returned object is a copy, so the application can set attributes on it
+// that can't be given in the file, such as the Logger, without affecting the
+// definition in the store.
+func (store *ObjectStore) Lookup(name string) (ConnectionFactoryImpl, error) {
+
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	cf, ok := store.factories[name]
+	if !ok {
+		return ConnectionFactoryImpl{}, errors.New("Unable to find connection factory " + name + " in " + store.storeFile)
+	}
+
+	return cf, nil
+}
+
+// LookupDestination returns the destination with the specified name, or an
+// error if there is no destination with that name.
+func (store *ObjectStore) LookupDestination(name string) (jms20subset.Destination, error) {
+
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	queue, ok := store.destinations[name]
+	if !ok {
+		return nil, errors.New("Unable to find destination " + name + " in " + store.storeFile)
+	}
+
+	return queue, nil
+}
+
+// readObjectStoreFile reads the connection factory and destination definitions
+// from a JSON or YAML object store file.
+func readObjectStoreFile(storeFile string) (map[string]map[string]interface{}, map[string]map[string]interface{}, error) {
+
+	raw, err := unmarshalConfigFile(storeFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var unknown []string
+	for key := range raw {
+		if key != objectStoreFactoriesKey && key != objectStoreDestinationsKey {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, nil, fmt.Errorf("Unrecognised sections in %s: %s", storeFile, strings.Join(unknown, ", "))
+	}
+
+	factoryDefs, err := objectStoreSection(raw, objectStoreFactoriesKey, storeFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	destinationDefs, err := objectStoreSection(raw, objectStoreDestinationsKey, storeFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return factoryDefs, destinationDefs, nil
+}
+
+// objectStoreSection returns the named definitions from one section of the file.
+func objectStoreSection(raw map[string]interface{}, section string, storeFile string) (map[string]map[string]interface{}, error) {
+
+	defs := make(map[string]map[string]interface{})
+
+	if raw[section] == nil {
+		return defs, nil
+	}
+
+	sectionMap, ok := raw[section].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Invalid %s section in %s", section, storeFile)
+	}
+
+	for name, def := range sectionMap {
+		defMap, ok := def.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Invalid definition of %s in %s", name, storeFile)
+		}
+		defs[name] = defMap
+	}
+
+	return defs, nil
+}
+
+// parseDestination creates a QueueImpl from its definition in the file.
+func parseDestination(def map[string]interface{}, source string) (QueueImpl, error) {
+
+	known := map[string]bool{
+		"queueName":       true,
+		"queueManager":    true,
+		"persistence":     true,
+		"priority":        true,
+		"targetClient":    true,
+		"putAsyncAllowed": true,
+	}
+
+	values, err := configValues(def, known, source)
+	if err != nil {
+		return QueueImpl{}, err
+	}
+
+	queue := QueueImpl{
+		queueName:        values["queueName"],
+		queueManagerName: values["queueManager"],
+		putAsyncAllowed:  jms20subset.Destination_PUT_ASYNC_ALLOWED_AS_DEST,
+		deliveryMode:     Destination_AS_PRODUCER,
+		priority:         Destination_AS_PRODUCER,
+		targetClient:     TargetClient_JMS,
+	}
+
+	if queue.queueName == "" {
+		return QueueImpl{}, errors.New("Unable to find queueName in " + source)
+	}
+
+	switch strings.ToUpper(values["persistence"]) {
+	case "", objectStoreAsApp:
+	case objectStorePersistent:
+		queue.deliveryMode = jms20subset.DeliveryMode_PERSISTENT
+	case objectStoreNonPersistent:
+		queue.deliveryMode = jms20subset.DeliveryMode_NON_PERSISTENT
+	default:
+		return QueueImpl{}, fmt.Errorf("Invalid value %q for persistence in %s", values["persistence"], source)
+	}
+
+	switch priority := strings.ToUpper(values["priority"]); priority {
+	case "", objectStoreAsApp:
+	default:
+		p, err := strconv.Atoi(priority)
+		if err != nil || p < 0 || p > 9 {
+			return QueueImpl{}, fmt.Errorf("Invalid value %q for priority in %s", values["priority"], source)
+		}
+		queue.priority = p
+	}
+
+	switch strings.ToUpper(values["targetClient"]) {
+	case "", objectStoreTargetJMS:
+	case objectStoreTargetMQ:
+		queue.targetClient = TargetClient_MQ
+	default:
+		return QueueImpl{}, fmt.Errorf("Invalid value %q for targetClient in %s", values["targetClient"], source)
+	}
+
+	switch strings.ToUpper(values["putAsyncAllowed"]) {
+	case "", objectStoreAsyncAsDest:
+	case objectStoreAsyncEnabled:
+		queue.putAsyncAllowed = jms20subset.Destination_PUT_ASYNC_ALLOWED_ENABLED
+	case objectStoreAsyncDisabled:
+		queue.putAsyncAllowed = jms20subset.Destination_PUT_ASYNC_ALLOWED_DISABLED
+	default:
+		return QueueImpl{}, fmt.Errorf("Invalid value %q for putAsyncAllowed in %s", values["putAsyncAllowed"], source)
+	}
+
+	return queue, nil
+}
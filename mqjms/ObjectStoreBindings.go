@@ -0,0 +1,269 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// objectStoreBindingsExt is the extension of the file that is written by the
+// JNDI file system context (com.sun.jndi.fscontext) used by JMSAdmin and MQ
+// Explorer, which is always named .bindings.
+const objectStoreBindingsExt = ".bindings"
+
+// The classes of the administered objects in a .bindings file that are read
+// into an ObjectStore. The package is com.ibm.mq.jms, or com.ibm.mq.jakarta.jms
+// for Jakarta Messaging.
+const (
+	bindingsClassPrefix  = "com.ibm.mq."
+	bindingsQueueClass   = ".MQQueue"
+	bindingsFactoryClass = "ConnectionFactory"
+)
+
+// bindingsFactoryProperties maps the short names of the JMSAdmin properties of
+// a connection factory, which are the types of its RefAddr entries, to the
+// settings of a connection factory in an object store.
+var bindingsFactoryProperties = map[string]string{
+	"QMGR":  "queueManager",
+	"HOST":  "hostname",
+	"PORT":  "port",
+	"CHAN":  "channel",
+	"TRAN":  "transportType",
+	"SCPHS": "tlsCipherSpec",
+}
+
+// bindingsQueueProperties maps the short names of the JMSAdmin properties of a
+// queue to the settings of a destination in an object store.
+var bindingsQueueProperties = map[string]string{
+	"QU":    "queueName",
+	"QMGR":  "queueManager",
+	"PER":   "persistence",
+	"PRI":   "priority",
+	"TC":    "targetClient",
+	"PAALD": "putAsyncAllowed",
+}
+
+// bindingsValues maps the numeric values that Java stores for the properties
+// that have a fixed set of values to the values used in an object store. A
+// value that is not listed, such as a persistence of QDEF, has no equivalent
+// in this library.
+var bindingsValues = map[string]map[string]string{
+	"transportType":   {"0": "BINDINGS", "1": "CLIENT"},
+	"persistence":     {"-1": objectStoreAsApp, "1": objectStoreNonPersistent, "2": objectStorePersistent},
+	"priority":        {"-1": objectStoreAsApp},
+	"targetClient":    {"0": objectStoreTargetJMS, "1": objectStoreTargetMQ},
+	"putAsyncAllowed": {"-1": objectStoreAsyncAsDest, "0": objectStoreAsyncDisabled, "1": objectStoreAsyncEnabled},
+}
+
+// isBindingsFile returns whether the object store file is a JNDI .bindings file.
+func isBindingsFile(storeFile string) bool {
+	return strings.ToLower(filepath.Ext(storeFile)) == objectStoreBindingsExt
+}
+
+// readBindingsFile reads the connection factories and queues that are defined
+// in a .bindings file, converting each of them to a definition in the same
+// form as those in a JSON or YAML object store file. Objects of other classes,
+// such as topics, and properties that have no equivalent in this library are
+// ignored.
+func readBindingsFile(storeFile string) (map[string]map[string]interface{}, map[string]map[string]interface{}, error) {
+
+	content, err := ioutil.ReadFile(storeFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	props, err := parseJavaProperties(string(content))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failure during parsing %s: %v", storeFile, err)
+	}
+
+	factoryDefs := make(map[string]map[string]interface{})
+	destinationDefs := make(map[string]map[string]interface{})
+
+	for key, className := range props {
+
+		name := strings.TrimSuffix(key, "/ClassName")
+		if name == key || !strings.HasPrefix(className, bindingsClassPrefix) {
+			continue
+		}
+
+		var known map[string]string
+		var defs map[string]map[string]interface{}
+
+		switch {
+		case strings.HasSuffix(className, bindingsQueueClass):
+			known, defs = bindingsQueueProperties, destinationDefs
+		case strings.HasSuffix(className, bindingsFactoryClass):
+			known, defs = bindingsFactoryProperties, factoryDefs
+		default:
+			continue
+		}
+
+		def, err := bindingsReference(props, name, known, storeFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		defs[name] = def
+	}
+
+	return factoryDefs, destinationDefs, nil
+}
+
+// bindingsReference converts the RefAddr entries of the named object, which
+// are numbered from zero, into the settings of a definition.
+func bindingsReference(props map[string]string, name string, known map[string]string, storeFile string) (map[string]interface{}, error) {
+
+	def := make(map[string]interface{})
+
+	for i := 0; ; i++ {
+
+		prefix := name + "/RefAddr/" + strconv.Itoa(i) + "/"
+		refType, ok := props[prefix+"Type"]
+		if !ok {
+			break
+		}
+
+		setting, ok := known[refType]
+		if !ok {
+			continue
+		}
+
+		value := props[prefix+"Content"]
+		if values, ok := bindingsValues[setting]; ok {
+			if mapped, ok := values[value]; ok {
+				value = mapped
+			} else if setting != "priority" {
+				return nil, fmt.Errorf("Unsupported value %q for %s of %s in %s", value, refType, name, storeFile)
+			}
+		}
+
+		if value != "" {
+			def[setting] = value
+		}
+	}
+
+	return def, nil
+}
+
+// parseJavaProperties parses the content of a file in the format of
+// java.util.Properties, which is how a .bindings file is written.
+func parseJavaProperties(content string) (map[string]string, error) {
+
+	props := make(map[string]string)
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		// A line that ends with an odd number of backslashes continues on the
+		// next line, without its leading white space.
+		for continues(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+
+		// The key ends at the first separator that is not escaped.
+		end := 0
+		for end < len(line) && !strings.ContainsRune("=: \t\f", rune(line[end])) {
+			if line[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end > len(line) {
+			end = len(line)
+		}
+
+		rest := strings.TrimLeft(line[end:], " \t\f")
+		if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+			rest = strings.TrimLeft(rest[1:], " \t\f")
+		}
+
+		key, err := unescapeJavaProperty(line[:end])
+		if err != nil {
+			return nil, err
+		}
+		value, err := unescapeJavaProperty(rest)
+		if err != nil {
+			return nil, err
+		}
+
+		props[key] = value
+	}
+
+	return props, nil
+}
+
+// continues returns whether a line of a properties file ends with an odd
+// number of backslashes.
+func continues(line string) bool {
+
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+
+	return count%2 == 1
+}
+
+// unescapeJavaProperty replaces the escape sequences in a key or value of a
+// properties file with the characters they represent.
+func unescapeJavaProperty(escaped string) (string, error) {
+
+	if !strings.Contains(escaped, "\\") {
+		return escaped, nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(escaped); i++ {
+
+		c := escaped[i]
+		if c != '\\' || i+1 == len(escaped) {
+			sb.WriteByte(c)
+			continue
+		}
+
+		i++
+		switch escaped[i] {
+		case 't':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'u':
+			if i+5 > len(escaped) {
+				return "", fmt.Errorf("Invalid unicode escape in %q", escaped)
+			}
+			r, err := strconv.ParseUint(escaped[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("Invalid unicode escape in %q", escaped)
+			}
+			var buf [utf8.UTFMax]byte
+			sb.Write(buf[:utf8.EncodeRune(buf[:], rune(r))])
+			i += 4
+		default:
+			sb.WriteByte(escaped[i])
+		}
+	}
+
+	return sb.String(), nil
+}
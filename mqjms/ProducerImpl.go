@@ -10,10 +10,12 @@
 package mqjms
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"io"
 	"strconv"
 	"strings"
+	"time"
 
 	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
 	"github.com/zemlya25/mq-golang-jms20/jms20subset"
@@ -22,10 +24,11 @@ import (
 // ProducerImpl defines a struct that contains the necessary objects for
 // sending messages to a queue on an IBM MQ queue manager.
 type ProducerImpl struct {
-	ctx          ContextImpl
-	deliveryMode int
-	timeToLive   int
-	priority     int
+	ctx           ContextImpl
+	deliveryMode  int
+	timeToLive    int
+	priority      int
+	keepMessageID bool // Send with the message ID that is already set, as the Requestor does
 }
 
 // SendString sends a TextMessage with the specified body to the specified Destination
@@ -37,6 +40,10 @@ func (producer ProducerImpl) SendString(dest jms20subset.Destination, bodyStr st
 	msg := producer.ctx.CreateTextMessage()
 	msg.SetText(bodyStr)
 
+	// The message is not visible to the application, so its handle can be
+	// released as soon as it has been sent.
+	defer msg.Release()
+
 	return producer.Send(dest, msg)
 
 }
@@ -50,19 +57,151 @@ func (producer ProducerImpl) SendBytes(dest jms20subset.Destination, body []byte
 	msg := producer.ctx.CreateBytesMessage()
 	msg.WriteBytes(body)
 
+	// The message is not visible to the application, so its handle can be
+	// released as soon as it has been sent.
+	defer msg.Release()
+
 	return producer.Send(dest, msg)
 
 }
 
+// ProducerImpl_STREAM_READ_REASON is the reason used in the JMSException that is
+// returned by SendStream when the stream cannot be read, which links to the error
+// that was returned by the stream.
+const ProducerImpl_STREAM_READ_REASON string = "MQJMS_E_STREAM_READ_FAILED"
+
+// ProducerImpl_STREAM_READ_CODE is the error code used in the JMSException that is
+// returned by SendStream when the stream cannot be read.
+const ProducerImpl_STREAM_READ_CODE string = "StreamReadFailed"
+
+// SendStream sends the data that is read from the stream to the specified IBM MQ
+// queue as a message group, in the same way as a GroupProducer. The stream is
+// read in chunks of the receive buffer size of the ConnectionFactory, each of
+// which is sent as a BytesMessage, so that no more than two chunks are held in
+// memory at a time. The group is received as a whole by ReceiveStream, which
+// only starts once the last chunk has been sent.
+//
+// If the JMSContext is transacted then the whole group is sent under the
+// transaction, and the application commits or rolls it back. Otherwise the group
+// is sent in a unit of work of its own, which is committed once the last chunk
+// has been sent, and backed out if the stream or a send fails part way through,
+// so that an incomplete group is never left on the queue. While the group is
+// being sent, operations of this library that need a unit of work of their own
+// return an error with the reason MQJMS_E_UNIT_OF_WORK_OPEN (see ReceiveStream).
+func (producer ProducerImpl) SendStream(dest jms20subset.Destination, stream io.Reader) jms20subset.JMSException {
+
+	transacted := producer.ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED
+
+	if !transacted {
+		if jmsErr := producer.ctx.beginUnitOfWork(); jmsErr != nil {
+			return jmsErr
+		}
+	}
+
+	groupProducer, jmsErr := newGroupProducer(&producer, dest)
+	if jmsErr != nil {
+		if !transacted {
+			producer.ctx.endUnitOfWork()
+		}
+		return jmsErr
+	}
+	defer groupProducer.Close()
+	groupProducer.ownUnitOfWork = !transacted
+
+	jmsErr = producer.sendChunks(groupProducer, stream)
+
+	if !transacted {
+		if jmsErr != nil {
+			producer.ctx.completeUnitOfWork(false)
+			groupProducer.inGroup = false // The messages of the group have been backed out
+		} else {
+			jmsErr = producer.ctx.completeUnitOfWork(true)
+		}
+	}
+
+	return jmsErr
+}
+
+// sendChunks sends the data that is read from the stream as the messages of a
+// group using the GroupProducer.
+func (producer ProducerImpl) sendChunks(groupProducer *GroupProducer, stream io.Reader) jms20subset.JMSException {
+
+	// Read one chunk ahead, so that the last chunk can be sent as the last
+	// message of the group.
+	current := make([]byte, producer.ctx.receiveBufferSize)
+	next := make([]byte, producer.ctx.receiveBufferSize)
+
+	length, readErr := io.ReadFull(stream, current)
+
+	for {
+
+		last := readErr == io.EOF || readErr == io.ErrUnexpectedEOF
+		if readErr != nil && !last {
+			return jms20subset.CreateJMSException(ProducerImpl_STREAM_READ_REASON, ProducerImpl_STREAM_READ_CODE, readErr)
+		}
+
+		var nextLength int
+		var nextErr error
+
+		if !last {
+			nextLength, nextErr = io.ReadFull(stream, next)
+			last = nextLength == 0 && nextErr == io.EOF
+		}
+
+		msg := producer.ctx.CreateBytesMessageWithBytes(current[:length])
+		jmsErr := groupProducer.send(msg, last)
+		msg.Release()
+
+		if jmsErr != nil || last {
+			return jmsErr
+		}
+
+		current, next = next, current
+		length, readErr = nextLength, nextErr
+	}
+
+}
+
 // Send a message to the specified IBM MQ queue, using the message options
 // that are defined on this JMSProducer.
 func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.Message) jms20subset.JMSException {
+	return producer.sendInternal(context.Background(), dest, msg, nil)
+}
+
+// SendContext sends a message to the specified IBM MQ queue in the same way as
+// Send, but returns an error without sending the message if the Go context is
+// cancelled or reaches its deadline before the send can start, for example
+// while waiting for another goroutine to finish using this JMSContext.
+func (producer ProducerImpl) SendContext(ctx context.Context, dest jms20subset.Destination, msg jms20subset.Message) jms20subset.JMSException {
+	return producer.sendInternal(ctx, dest, msg, nil)
+}
+
+// groupPut describes how a message is put as part of a message group, which must
+// be done using a handle that stays open for the whole of the group, because the
+// queue manager keeps track of the group and its sequence numbers on the handle.
+type groupPut struct {
+	qObject       mqiObject
+	last          bool // Whether this is the last message in the group
+	ownUnitOfWork bool // Whether the group is sent in a unit of work of its own, which the caller completes
+}
+
+// Internal method to provide common functionality across the different types
+// of send. If group is not nil then the message is put as part of a message
+// group using the handle that it contains.
+func (producer ProducerImpl) sendInternal(ctx context.Context, dest jms20subset.Destination, msg jms20subset.Message, group *groupPut) jms20subset.JMSException {
 
 	// Lock the context while we are making calls to the queue manager so that it
 	// doesn't conflict with the finalizer we use (below) to delete unused MessageHandles.
-	producer.ctx.ctxLock.Lock()
+	lockErr := lockWithContext(ctx, producer.ctx.ctxLock)
+	if lockErr != nil {
+		return lockErr
+	}
 	defer producer.ctx.ctxLock.Unlock()
 
+	if producer.ctx.state.closed {
+		return createContextClosedException()
+	}
+
 	// Set up the basic objects we need to send the message.
 	mqod := ibmmq.NewMQOD()
 	putmqmd := ibmmq.NewMQMD()
@@ -74,22 +213,38 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 	mqod.ObjectType = ibmmq.MQOT_Q
 	mqod.ObjectName = dest.GetDestinationName()
 
+	// Attributes of the destination that override those of the producer, for
+	// example when it has been looked up from an ObjectStore.
+	queue, isQueueImpl := dest.(QueueImpl)
+	if isQueueImpl {
+		mqod.ObjectQMgrName = queue.queueManagerName
+	}
+
 	// Calculate the syncpoint value
+	transacted := producer.ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED
+	ownUnitOfWork := group != nil && group.ownUnitOfWork
+
 	syncpointSetting := ibmmq.MQPMO_NO_SYNCPOINT
-	if producer.ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED {
+	if transacted || ownUnitOfWork {
 		syncpointSetting = ibmmq.MQPMO_SYNCPOINT
 	}
 
 	// Configure the put message options, including asking MQ to allocate a
-	// unique message ID
-	pmo.Options = syncpointSetting | ibmmq.MQPMO_NEW_MSG_ID
+	// unique message ID unless one has been set by the Requestor
+	pmo.Options = syncpointSetting
+	if !producer.keepMessageID {
+		pmo.Options |= ibmmq.MQPMO_NEW_MSG_ID
+	}
 
-	// Is async put has been requested then apply the appropriate PMO option
-	if dest.GetPutAsyncAllowed() == jms20subset.Destination_PUT_ASYNC_ALLOWED_ENABLED {
+	// Is async put has been requested then apply the appropriate PMO option. The
+	// outcome of a message sent in a unit of work of its own is needed before the
+	// unit of work is committed, so async put is not used for it.
+	if dest.GetPutAsyncAllowed() == jms20subset.Destination_PUT_ASYNC_ALLOWED_ENABLED && !ownUnitOfWork {
 		pmo.Options |= ibmmq.MQPMO_ASYNC_RESPONSE
 	}
 
 	var buffer []byte
+	var msgHandle mqiMessageHandle
 
 	// We have a "Message" object and can use a switch to safely convert it
 	// to the implementation type in order to extract generic MQ message
@@ -102,8 +257,12 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 			putmqmd = typedMsg.mqmd
 		}
 
-		// Pass up the handle containing the message properties
-		pmo.OriginalMsgHandle = *typedMsg.msgHandle
+		// Get the handle containing the message properties
+		var handleErr error
+		msgHandle, handleErr = typedMsg.getMsgHandle()
+		if handleErr != nil {
+			return createJMSExceptionFromMQReturn(handleErr)
+		}
 
 		// Store the Put MQMD so that we can later retrieve "out" fields like MsgId
 		typedMsg.mqmd = putmqmd
@@ -127,8 +286,12 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 			putmqmd = typedMsg.mqmd
 		}
 
-		// Pass up the handle containing the message properties
-		pmo.OriginalMsgHandle = *typedMsg.msgHandle
+		// Get the handle containing the message properties
+		var handleErr error
+		msgHandle, handleErr = typedMsg.getMsgHandle()
+		if handleErr != nil {
+			return createJMSExceptionFromMQReturn(handleErr)
+		}
 
 		// Store the Put MQMD so that we can later retrieve "out" fields like MsgId
 		typedMsg.mqmd = putmqmd
@@ -139,7 +302,7 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 	default:
 		// This "should never happen"(!) apart from in situations where we are
 		// part way through adding support for a new message type to this library.
-		log.Fatal(jms20subset.CreateJMSException("UnexpectedMessageType", "UnexpectedMessageType-send1", nil))
+		return jms20subset.CreateMessageFormatException("UnexpectedMessageType", "UnexpectedMessageType-send1", nil)
 	}
 
 	// Convert the JMS persistence into the equivalent MQ message descriptor
@@ -162,9 +325,115 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 	// attribute.
 	putmqmd.Priority = int32(producer.priority)
 
-	// Invoke the MQ command to put the message using MQPUT1 to avoid MQOPEN and MQCLOSE.
-	// Any Err that occurs will be handled below.
-	err := producer.ctx.qMgr.Put1(mqod, putmqmd, pmo, buffer)
+	// Pass up the handle containing the message properties, unless the
+	// destination is for a non-JMS application that only expects the body.
+	putMsgHandle := msgHandle
+	if isQueueImpl && queue.targetClient == TargetClient_MQ {
+		putMsgHandle = nil
+	}
+
+	if isQueueImpl {
+		switch queue.deliveryMode {
+		case jms20subset.DeliveryMode_NON_PERSISTENT:
+			putmqmd.Persistence = ibmmq.MQPER_NOT_PERSISTENT
+		case jms20subset.DeliveryMode_PERSISTENT:
+			putmqmd.Persistence = ibmmq.MQPER_PERSISTENT
+		}
+
+		if queue.priority != Destination_AS_PRODUCER {
+			putmqmd.Priority = int32(queue.priority)
+		}
+	}
+
+	// Use the version 2 MQMD that contains the message flags, clearing any group
+	// or segment flags left over from an earlier send or receive of the message.
+	// If segmentation has been configured then the queue manager is allowed to
+	// split a message that is too large for the queue into segments.
+	putmqmd.Version = ibmmq.MQMD_VERSION_2
+	putmqmd.MsgFlags = 0
+	putmqmd.Offset = 0
+	if producer.ctx.segmentSize != 0 {
+		putmqmd.MsgFlags = ibmmq.MQMF_SEGMENTATION_ALLOWED
+	}
+
+	// Ask the queue manager to assign the group ID and sequence number.
+	if group != nil {
+		pmo.Options |= ibmmq.MQPMO_LOGICAL_ORDER
+
+		putmqmd.MsgFlags |= ibmmq.MQMF_MSG_IN_GROUP
+		if group.last {
+			putmqmd.MsgFlags |= ibmmq.MQMF_LAST_MSG_IN_GROUP
+		}
+	}
+
+	// The segments of a message are committed together on a JMSContext that is
+	// not transacted, which would also commit any other work in the unit of work
+	// of the connection handle.
+	segmented := producer.ctx.segmentSize > 0 && len(buffer) > producer.ctx.segmentSize
+	if transacted {
+		if jmsErr := producer.ctx.checkTransactedWork(); jmsErr != nil {
+			return jmsErr
+		}
+		producer.ctx.conn.uncommitted = true
+	} else if segmented && !ownUnitOfWork {
+		if jmsErr := producer.ctx.checkOwnUnitOfWork(); jmsErr != nil {
+			return jmsErr
+		}
+	}
+
+	// Start the span for this send, which also adds the trace context to the
+	// properties of the message.
+	span := producer.ctx.startSendSpan(ctx, mqod.ObjectName, msgHandle)
+
+	var err error
+
+	if segmented {
+
+		// Split the message into segments here, rather than relying on the
+		// queue manager to do so.
+		err = producer.putSegments(mqod, putmqmd, pmo, putMsgHandle, buffer, group)
+
+	} else if group != nil {
+
+		// Put the message using the handle that is open for the group.
+		putStart := time.Now()
+		err = group.qObject.Put(putmqmd, pmo, putMsgHandle, buffer)
+		producer.ctx.metrics.PutLatency(mqod.ObjectName, time.Since(putStart))
+		producer.ctx.traceMQI("MQPUT", mqod.ObjectName, err)
+
+	} else if producer.ctx.handleCache != nil {
+
+		// Put the message using a handle that is kept open between sends, to avoid
+		// the cost of opening and closing the queue each time.
+		var qObject mqiObject
+		qObject, err = producer.ctx.handleCache.getHandle(producer.ctx.qMgr, mqod)
+
+		if err == nil {
+			putStart := time.Now()
+			err = qObject.Put(putmqmd, pmo, putMsgHandle, buffer)
+			producer.ctx.metrics.PutLatency(mqod.ObjectName, time.Since(putStart))
+			producer.ctx.traceMQI("MQPUT", mqod.ObjectName, err)
+
+			// Discard the handle if the put failed, in case the failure means that
+			// the handle is no longer usable. It will be reopened on the next send.
+			if err != nil {
+				producer.ctx.handleCache.removeHandle(mqod)
+			}
+		}
+
+	} else {
+
+		// Invoke the MQ command to put the message using MQPUT1 to avoid MQOPEN and MQCLOSE.
+		// Any Err that occurs will be handled below.
+		putStart := time.Now()
+		err = producer.ctx.qMgr.Put1(mqod, putmqmd, pmo, putMsgHandle, buffer)
+		producer.ctx.metrics.PutLatency(mqod.ObjectName, time.Since(putStart))
+		producer.ctx.traceMQI("MQPUT1", mqod.ObjectName, err)
+	}
+
+	if err == nil {
+		producer.ctx.metrics.MessageSent(mqod.ObjectName, len(buffer))
+	}
 
 	// If the user is using non-transactional async-put and requested non-zero send check
 	// count then this is the point at which we carry out the check for errors.
@@ -195,6 +464,7 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 			// Invoke the Stat call agains the queue manager to check for errors.
 			sts := ibmmq.NewMQSTS()
 			statErr := producer.ctx.qMgr.Stat(ibmmq.MQSTAT_TYPE_ASYNC_ERROR, sts)
+			producer.ctx.traceMQI("MQSTAT", "", statErr)
 
 			if statErr != nil {
 
@@ -204,6 +474,8 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 
 			} else {
 
+				producer.ctx.metrics.AsyncPutStatus(sts.PutWarningCount, sts.PutFailureCount)
+
 				// If there are any Warnings or Failures then we have found a problem that
 				// needs to be reported to the user.
 				if sts.PutWarningCount+sts.PutFailureCount > 0 {
@@ -228,7 +500,7 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 	// Note that if there is already an error returned from Put then just pass that back to
 	// the user (only go into this if err is nil).
 	if dest.GetPutAsyncAllowed() == jms20subset.Destination_PUT_ASYNC_ALLOWED_ENABLED &&
-		syncpointSetting == ibmmq.MQPMO_SYNCPOINT &&
+		transacted &&
 		putmqmd.Persistence == ibmmq.MQPER_PERSISTENT &&
 		*producer.ctx.sendCheckCountInc != ContextImpl_TRANSACTED_ASYNCPUT_ACTIVE &&
 		err == nil {
@@ -241,17 +513,108 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 	// and putting the message.
 	if err != nil {
 
-		rcInt := int(err.(*ibmmq.MQReturn).MQRC)
-		errCode := strconv.Itoa(rcInt)
-		reason := ibmmq.MQItoString("RC", rcInt)
-		retErr = jms20subset.CreateJMSException(reason, errCode, err)
+		retErr = createJMSExceptionFromMQReturn(err)
 
 	}
 
+	if retErr != nil {
+		endSpan(span, retErr)
+	} else {
+		endSpan(span, nil, messageSpanAttributes(putmqmd, len(buffer))...)
+	}
+
 	return retErr
 
 }
 
+// putSegments puts a message that is larger than the segment size of the
+// ConnectionFactory as a series of segments, each of which is small enough for
+// queue managers and channels that cannot carry the whole message. The consumer
+// reassembles the segments into the original message.
+//
+// The segments are put in logical order, which means that the queue manager
+// assigns their group ID and offsets, using the handle that is open for the group
+// if the message is part of one or otherwise a handle that is opened for the
+// message. The segments are always put under syncpoint, and if the JMSContext is
+// not transacted they are committed (or backed out) together, so that part of a
+// message is never left on the queue. The context lock must be held.
+func (producer ProducerImpl) putSegments(mqod *ibmmq.MQOD, putmqmd *ibmmq.MQMD, pmo *ibmmq.MQPMO, msgHandle mqiMessageHandle, buffer []byte, group *groupPut) error {
+
+	var qObject mqiObject
+	var err error
+
+	if group != nil {
+		qObject = group.qObject
+	} else {
+		qObject, err = producer.ctx.qMgr.Open(mqod, ibmmq.MQOO_OUTPUT|ibmmq.MQOO_FAIL_IF_QUIESCING)
+		producer.ctx.traceMQI("MQOPEN", mqod.ObjectName, err)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			closeErr := qObject.Close(0)
+			producer.ctx.traceMQI("MQCLOSE", mqod.ObjectName, closeErr)
+		}()
+	}
+
+	// The outcome of every segment is needed before the next one is put, so
+	// asynchronous put is not used.
+	segmentOptions := pmo.Options | ibmmq.MQPMO_LOGICAL_ORDER | ibmmq.MQPMO_SYNCPOINT
+	segmentOptions &^= ibmmq.MQPMO_NO_SYNCPOINT | ibmmq.MQPMO_ASYNC_RESPONSE
+
+	groupFlags := putmqmd.MsgFlags & (ibmmq.MQMF_MSG_IN_GROUP | ibmmq.MQMF_LAST_MSG_IN_GROUP)
+	segmentSize := producer.ctx.segmentSize
+
+	putStart := time.Now()
+
+	for offset := 0; offset < len(buffer) && err == nil; offset += segmentSize {
+
+		end := offset + segmentSize
+		if end > len(buffer) {
+			end = len(buffer)
+		}
+
+		putmqmd.MsgFlags = groupFlags | ibmmq.MQMF_SEGMENT
+		if end == len(buffer) {
+			putmqmd.MsgFlags |= ibmmq.MQMF_LAST_SEGMENT
+		}
+
+		// Every segment has the message ID that is assigned to the first one, and
+		// the properties are carried by the first segment.
+		segmentPMO := ibmmq.NewMQPMO()
+		segmentPMO.Options = segmentOptions
+		segmentHandle := msgHandle
+
+		if offset > 0 {
+			segmentPMO.Options &^= ibmmq.MQPMO_NEW_MSG_ID
+			segmentHandle = nil
+		}
+
+		err = qObject.Put(putmqmd, segmentPMO, segmentHandle, buffer[offset:end])
+		producer.ctx.traceMQI("MQPUT", mqod.ObjectName, err)
+	}
+
+	producer.ctx.metrics.PutLatency(mqod.ObjectName, time.Since(putStart))
+
+	// The message as a whole is described by the flags of the group, if any.
+	putmqmd.MsgFlags = groupFlags
+
+	// The segments of a message in a group that is sent in a unit of work of its
+	// own are committed along with the rest of the group.
+	if producer.ctx.sessionMode != jms20subset.JMSContextSESSIONTRANSACTED && (group == nil || !group.ownUnitOfWork) {
+		if err == nil {
+			err = producer.ctx.qMgr.Cmit()
+			producer.ctx.traceMQI("MQCMIT", "", err)
+		} else {
+			backErr := producer.ctx.qMgr.Back()
+			producer.ctx.traceMQI("MQBACK", "", backErr)
+		}
+	}
+
+	return err
+}
+
 // populateAsyncPutError is a common function used in several places to generate a
 // consistent error message in response to failures during asynchronous put operations.
 func populateAsyncPutError(sts *ibmmq.MQSTS) jms20subset.JMSException {
@@ -282,8 +645,8 @@ func (producer *ProducerImpl) SetDeliveryMode(mode int) jms20subset.JMSProducer
 		// Normally we would throw an error here to indicate that an invalid value
 		// was specified, however we have decided that it is more useful to support
 		// method chaining, which prevents us from returning an error object.
-		// Instead we settle for printing an error message to the console.
-		fmt.Println("Invalid DeliveryMode specified: " + strconv.Itoa(mode))
+		// Instead we settle for writing a warning to the log.
+		producer.ctx.logger.Log(LogLevelWarn, "Invalid DeliveryMode specified", LogField{Key: LogFieldValue, Value: mode})
 	}
 
 	return producer
@@ -308,8 +671,8 @@ func (producer *ProducerImpl) SetTimeToLive(timeToLive int) jms20subset.JMSProdu
 		// Normally we would throw an error here to indicate that an invalid value
 		// was specified, however we have decided that it is more useful to support
 		// method chaining, which prevents us from returning an error object.
-		// Instead we settle for printing an error message to the console.
-		fmt.Println("Invalid TimeToLive specified: " + strconv.FormatInt(int64(timeToLive), 10))
+		// Instead we settle for writing a warning to the log.
+		producer.ctx.logger.Log(LogLevelWarn, "Invalid TimeToLive specified", LogField{Key: LogFieldValue, Value: timeToLive})
 	}
 
 	return producer
@@ -334,8 +697,8 @@ func (producer *ProducerImpl) SetPriority(priority int) jms20subset.JMSProducer
 		// Normally we would throw an error here to indicate that an invalid value
 		// was specified, however we have decided that it is more useful to support
 		// method chaining, which prevents us from returning an error object.
-		// Instead we settle for printing an error message to the console.
-		fmt.Println("Invalid Priority specified: " + strconv.FormatInt(int64(priority), 10))
+		// Instead we settle for writing a warning to the log.
+		producer.ctx.logger.Log(LogLevelWarn, "Invalid Priority specified", LogField{Key: LogFieldValue, Value: priority})
 	}
 
 	return producer
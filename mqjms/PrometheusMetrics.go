@@ -0,0 +1,305 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// DefaultLatencyBuckets are the upper bounds in seconds of the buckets of the
+// latency histograms, used when no buckets are passed to NewPrometheusMetrics.
+var DefaultLatencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusMetrics is an implementation of Metrics that keeps the measurements
+// in memory and writes them in the Prometheus text exposition format, either by
+// calling WriteTo or by registering it as the http.Handler of a metrics endpoint.
+type PrometheusMetrics struct {
+	lock sync.Mutex
+
+	buckets []float64
+
+	sent          map[string]*messageCounter
+	received      map[string]*messageCounter
+	errors        map[mqiErrorKey]uint64
+	asyncWarnings uint64
+	asyncFailures uint64
+	commits       uint64
+	rollbacks     uint64
+	putLatency    map[string]*latencyHistogram
+	getLatency    map[string]*latencyHistogram
+}
+
+// messageCounter holds the number of messages and bytes for a single queue.
+type messageCounter struct {
+	messages uint64
+	bytes    uint64
+}
+
+// mqiErrorKey identifies the errors that are counted together.
+type mqiErrorKey struct {
+	verb string
+	mqrc int32
+}
+
+// latencyHistogram holds the latency measurements for a single queue, where
+// counts[i] is the number of measurements that are no greater than buckets[i].
+type latencyHistogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewPrometheusMetrics creates a PrometheusMetrics that uses the specified
+// upper bounds in seconds for the buckets of its latency histograms, or
+// DefaultLatencyBuckets if none are specified.
+func NewPrometheusMetrics(latencyBuckets ...float64) *PrometheusMetrics {
+
+	if len(latencyBuckets) == 0 {
+		latencyBuckets = DefaultLatencyBuckets
+	}
+
+	buckets := make([]float64, len(latencyBuckets))
+	copy(buckets, latencyBuckets)
+	sort.Float64s(buckets)
+
+	return &PrometheusMetrics{
+		buckets:    buckets,
+		sent:       make(map[string]*messageCounter),
+		received:   make(map[string]*messageCounter),
+		errors:     make(map[mqiErrorKey]uint64),
+		putLatency: make(map[string]*latencyHistogram),
+		getLatency: make(map[string]*latencyHistogram),
+	}
+}
+
+// MessageSent counts a message that has been sent to a queue.
+func (metrics *PrometheusMetrics) MessageSent(queueName string, bodySize int) {
+	metrics.lock.Lock()
+	defer metrics.lock.Unlock()
+	countMessage(metrics.sent, queueName, bodySize)
+}
+
+// MessageReceived counts a message that has been received from a queue.
+func (metrics *PrometheusMetrics) MessageReceived(queueName string, bodySize int) {
+	metrics.lock.Lock()
+	defer metrics.lock.Unlock()
+	countMessage(metrics.received, queueName, bodySize)
+}
+
+// MQIError counts a call to the queue manager that returned a reason code.
+func (metrics *PrometheusMetrics) MQIError(verb string, mqrc int32) {
+	metrics.lock.Lock()
+	defer metrics.lock.Unlock()
+	metrics.errors[mqiErrorKey{verb: verb, mqrc: mqrc}]++
+}
+
+// AsyncPutStatus counts the asynchronous put warnings and failures.
+func (metrics *PrometheusMetrics) AsyncPutStatus(warnings int32, failures int32) {
+	metrics.lock.Lock()
+	defer metrics.lock.Unlock()
+	metrics.asyncWarnings += uint64(warnings)
+	metrics.asyncFailures += uint64(failures)
+}
+
+// Committed counts a transaction that has been committed.
+func (metrics *PrometheusMetrics) Committed() {
+	metrics.lock.Lock()
+	defer metrics.lock.Unlock()
+	metrics.commits++
+}
+
+// RolledBack counts a transaction that has been rolled back.
+func (metrics *PrometheusMetrics) RolledBack() {
+	metrics.lock.Lock()
+	defer metrics.lock.Unlock()
+	metrics.rollbacks++
+}
+
+// PutLatency records the time taken to put a message to a queue.
+func (metrics *PrometheusMetrics) PutLatency(queueName string, d time.Duration) {
+	metrics.lock.Lock()
+	defer metrics.lock.Unlock()
+	metrics.observe(metrics.putLatency, queueName, d)
+}
+
+// GetLatency records the time taken to get a message from a queue.
+func (metrics *PrometheusMetrics) GetLatency(queueName string, d time.Duration) {
+	metrics.lock.Lock()
+	defer metrics.lock.Unlock()
+	metrics.observe(metrics.getLatency, queueName, d)
+}
+
+// countMessage adds a message of the specified size to the counter for a queue.
+func countMessage(counters map[string]*messageCounter, queueName string, bodySize int) {
+
+	counter, ok := counters[queueName]
+	if !ok {
+		counter = &messageCounter{}
+		counters[queueName] = counter
+	}
+
+	counter.messages++
+	counter.bytes += uint64(bodySize)
+}
+
+// observe adds a latency measurement to the histogram for a queue.
+func (metrics *PrometheusMetrics) observe(histograms map[string]*latencyHistogram, queueName string, d time.Duration) {
+
+	histogram, ok := histograms[queueName]
+	if !ok {
+		histogram = &latencyHistogram{counts: make([]uint64, len(metrics.buckets))}
+		histograms[queueName] = histogram
+	}
+
+	seconds := d.Seconds()
+	for i, bound := range metrics.buckets {
+		if seconds <= bound {
+			histogram.counts[i]++
+		}
+	}
+
+	histogram.sum += seconds
+	histogram.count++
+}
+
+// WriteTo writes the current value of every metric to w in the Prometheus text
+// exposition format.
+func (metrics *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+
+	var buf bytes.Buffer
+
+	metrics.lock.Lock()
+
+	writeMessageCounters(&buf, "mqjms_messages_sent_total", "Number of messages sent.", metrics.sent, false)
+	writeMessageCounters(&buf, "mqjms_message_bytes_sent_total", "Number of bytes of message body sent.", metrics.sent, true)
+	writeMessageCounters(&buf, "mqjms_messages_received_total", "Number of messages received.", metrics.received, false)
+	writeMessageCounters(&buf, "mqjms_message_bytes_received_total", "Number of bytes of message body received.", metrics.received, true)
+
+	writeHeader(&buf, "mqjms_mqi_errors_total", "Number of calls to the queue manager that returned a reason code.", "counter")
+	errorKeys := make([]mqiErrorKey, 0, len(metrics.errors))
+	for key := range metrics.errors {
+		errorKeys = append(errorKeys, key)
+	}
+	sort.Slice(errorKeys, func(i, j int) bool {
+		if errorKeys[i].verb != errorKeys[j].verb {
+			return errorKeys[i].verb < errorKeys[j].verb
+		}
+		return errorKeys[i].mqrc < errorKeys[j].mqrc
+	})
+	for _, key := range errorKeys {
+		fmt.Fprintf(&buf, "mqjms_mqi_errors_total{verb=\"%s\",mqrc=\"%d\",reason=\"%s\"} %d\n",
+			escapeLabel(key.verb), key.mqrc, escapeLabel(ibmmq.MQItoString("RC", int(key.mqrc))), metrics.errors[key])
+	}
+
+	writeSingleCounter(&buf, "mqjms_async_put_warnings_total", "Number of asynchronous puts that completed with a warning.", metrics.asyncWarnings)
+	writeSingleCounter(&buf, "mqjms_async_put_failures_total", "Number of asynchronous puts that failed.", metrics.asyncFailures)
+	writeSingleCounter(&buf, "mqjms_commits_total", "Number of transactions committed.", metrics.commits)
+	writeSingleCounter(&buf, "mqjms_rollbacks_total", "Number of transactions rolled back.", metrics.rollbacks)
+
+	metrics.writeHistograms(&buf, "mqjms_put_latency_seconds", "Time taken to put a message.", metrics.putLatency)
+	metrics.writeHistograms(&buf, "mqjms_get_latency_seconds", "Time taken to get a message, including waiting for it to arrive.", metrics.getLatency)
+
+	metrics.lock.Unlock()
+
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP writes the metrics in response to a scrape by a Prometheus server.
+func (metrics *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	metrics.WriteTo(w)
+}
+
+// writeHeader writes the HELP and TYPE lines that introduce a metric.
+func writeHeader(buf *bytes.Buffer, name string, help string, metricType string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, metricType)
+}
+
+// writeSingleCounter writes a counter that has no labels.
+func writeSingleCounter(buf *bytes.Buffer, name string, help string, value uint64) {
+	writeHeader(buf, name, help, "counter")
+	fmt.Fprintf(buf, "%s %d\n", name, value)
+}
+
+// writeMessageCounters writes a counter with a queue label, using either the
+// number of messages or the number of bytes from each messageCounter.
+func writeMessageCounters(buf *bytes.Buffer, name string, help string, counters map[string]*messageCounter, useBytes bool) {
+
+	writeHeader(buf, name, help, "counter")
+
+	for _, queueName := range sortedKeys(counters) {
+		value := counters[queueName].messages
+		if useBytes {
+			value = counters[queueName].bytes
+		}
+		fmt.Fprintf(buf, "%s{queue=\"%s\"} %d\n", name, escapeLabel(queueName), value)
+	}
+}
+
+// writeHistograms writes a histogram with a queue label.
+func (metrics *PrometheusMetrics) writeHistograms(buf *bytes.Buffer, name string, help string, histograms map[string]*latencyHistogram) {
+
+	writeHeader(buf, name, help, "histogram")
+
+	queueNames := make([]string, 0, len(histograms))
+	for queueName := range histograms {
+		queueNames = append(queueNames, queueName)
+	}
+	sort.Strings(queueNames)
+
+	for _, queueName := range queueNames {
+		histogram := histograms[queueName]
+		label := escapeLabel(queueName)
+
+		for i, bound := range metrics.buckets {
+			fmt.Fprintf(buf, "%s_bucket{queue=\"%s\",le=\"%s\"} %d\n", name, label, formatFloat(bound), histogram.counts[i])
+		}
+		fmt.Fprintf(buf, "%s_bucket{queue=\"%s\",le=\"+Inf\"} %d\n", name, label, histogram.count)
+		fmt.Fprintf(buf, "%s_sum{queue=\"%s\"} %s\n", name, label, formatFloat(histogram.sum))
+		fmt.Fprintf(buf, "%s_count{queue=\"%s\"} %d\n", name, label, histogram.count)
+	}
+}
+
+// sortedKeys returns the queue names of a set of message counters in order,
+// so that the output is the same each time it is written.
+func sortedKeys(counters map[string]*messageCounter) []string {
+
+	keys := make([]string, 0, len(counters))
+	for key := range counters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// formatFloat formats a number in the way that Prometheus expects.
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// labelEscaper escapes the characters that have a special meaning in a label value.
+var labelEscaper = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n")
+
+// escapeLabel returns a label value that is safe to include in the output.
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -10,17 +10,19 @@
 package mqjms
 
 import (
-	"fmt"
-	"strconv"
-
 	"github.com/zemlya25/mq-golang-jms20/jms20subset"
 )
 
 // QueueImpl encapsulates the provider-specific attributes necessary to
 // communicate with an IBM MQ queue.
 type QueueImpl struct {
-	queueName       string
-	putAsyncAllowed int
+	queueName        string
+	queueManagerName string // Empty for a queue on the queue manager the application is connected to
+	putAsyncAllowed  int
+	deliveryMode     int // Overrides the delivery mode of the producer, unless Destination_AS_PRODUCER
+	priority         int // Overrides the priority of the producer, unless Destination_AS_PRODUCER
+	targetClient     int
+	logger           Logger // Logger of the context that created the queue, if any
 }
 
 // GetQueueName returns the provider-specific name of the queue that is
@@ -31,6 +33,15 @@ func (queue QueueImpl) GetQueueName() string {
 
 }
 
+// GetQueueManagerName returns the name of the queue manager that hosts the
+// queue, or an empty string if it is hosted by the queue manager that the
+// application is connected to.
+func (queue QueueImpl) GetQueueManagerName() string {
+
+	return queue.queueManagerName
+
+}
+
 // GetDestinationName returns the name of the destination represented by this
 // object.
 func (queue QueueImpl) GetDestinationName() string {
@@ -54,8 +65,9 @@ func (queue QueueImpl) SetPutAsyncAllowed(paa int) jms20subset.Queue {
 		// Normally we would throw an error here to indicate that an invalid value
 		// was specified, however we have decided that it is more useful to support
 		// method chaining, which prevents us from returning an error object.
-		// Instead we settle for printing an error message to the console.
-		fmt.Println("Invalid PutAsyncAllowed value specified: " + strconv.Itoa(paa))
+		// Instead we settle for writing a warning to the log.
+		loggerOrDefault(queue.logger).Log(LogLevelWarn, "Invalid PutAsyncAllowed value specified",
+			LogField{Key: LogFieldQueue, Value: queue.queueName}, LogField{Key: LogFieldValue, Value: paa})
 	}
 
 	return queue
@@ -65,3 +77,22 @@ func (queue QueueImpl) SetPutAsyncAllowed(paa int) jms20subset.Queue {
 func (queue QueueImpl) GetPutAsyncAllowed() int {
 	return queue.putAsyncAllowed
 }
+
+// GetDeliveryMode returns the delivery mode of the messages sent to this queue,
+// which is either jms20subset.DeliveryMode_PERSISTENT, DeliveryMode_NON_PERSISTENT
+// or Destination_AS_PRODUCER to use the setting of the JMSProducer.
+func (queue QueueImpl) GetDeliveryMode() int {
+	return queue.deliveryMode
+}
+
+// GetPriority returns the priority (0-9) of the messages sent to this queue, or
+// Destination_AS_PRODUCER to use the setting of the JMSProducer.
+func (queue QueueImpl) GetPriority() int {
+	return queue.priority
+}
+
+// GetTargetClient returns whether the messages sent to this queue include their
+// properties (TargetClient_JMS) or only the message body (TargetClient_MQ).
+func (queue QueueImpl) GetTargetClient() int {
+	return queue.targetClient
+}
@@ -0,0 +1,393 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// Requestor sends request messages and waits for their replies, using the
+// request/reply pattern in which the message ID of the request is used as the
+// correlation ID of the reply (see Responder).
+//
+// Every request is sent with its JMSReplyTo set to the reply queue of the
+// Requestor, and a single consumer on that queue delivers each reply to the
+// goroutine that is waiting for it, so a Requestor can be used by many
+// goroutines at once with any number of outstanding requests. Replies that do
+// not match an outstanding request, for example because the request has
+// already timed out, are discarded. The message ID of each request is a random
+// one that is generated by the Requestor, rather than by the queue manager.
+//
+// The Requestor uses its own JMSContexts, created using CreateContext on the
+// JMSContext that it was created from, so requests are sent and replies received
+// outside of any transaction of the application. The replies are received
+// using a separate JMSContext from the one used to send the requests, so that
+// waiting for replies does not hold up the sending of new requests.
+type Requestor struct {
+	sendContext  jms20subset.JMSContext
+	replyContext jms20subset.JMSContext
+	producer     jms20subset.JMSProducer
+	consumer     jms20subset.JMSConsumer
+	requestDest  jms20subset.Destination
+	replyQueue   jms20subset.Queue
+	logger       Logger
+	lock         sync.Mutex
+	pending      map[string]chan requestorReply // Outstanding requests, by message ID
+	stopErr      jms20subset.JMSException       // Reason that requests can no longer be made
+	stopReceive  context.CancelFunc
+	stopped      chan struct{} // Closed when the reply consumer has finished
+}
+
+// requestorReply is the outcome of a request, which is delivered by the reply
+// consumer to the goroutine that is waiting for it.
+type requestorReply struct {
+	msg jms20subset.Message
+	err jms20subset.JMSException
+}
+
+// Requestor_DEFAULT_MODEL_QUEUE is the model queue from which a temporary reply
+// queue is created, if the application does not supply a reply queue.
+const Requestor_DEFAULT_MODEL_QUEUE string = "SYSTEM.DEFAULT.MODEL.QUEUE"
+
+// Requestor_CLOSED_REASON is the reason used in the JMSException that is returned
+// when a request is made using a Requestor that has been closed.
+const Requestor_CLOSED_REASON string = "MQJMS_E_REQUESTOR_CLOSED"
+
+// Requestor_NO_REPLY_QUEUE_REASON is the reason used in the JMSException that is
+// returned when there is no queue to which a reply can be sent.
+const Requestor_NO_REPLY_QUEUE_REASON string = "MQJMS_E_NO_REPLY_QUEUE"
+
+// Requestor_NO_REPLY_QUEUE_CODE is the error code used in the JMSException that is
+// returned when there is no queue to which a reply can be sent.
+const Requestor_NO_REPLY_QUEUE_CODE string = "NoReplyQueue"
+
+// createNoReplyQueueException generates a consistent error to describe a
+// request or reply for which there is no reply queue.
+func createNoReplyQueueException() jms20subset.JMSException {
+	return jms20subset.CreateInvalidDestinationException(Requestor_NO_REPLY_QUEUE_REASON, Requestor_NO_REPLY_QUEUE_CODE, nil)
+}
+
+// NewRequestor creates a Requestor that sends requests to requestDest, and
+// receives the replies from replyQueue.
+//
+// If replyQueue is nil then the replies are received from a temporary dynamic
+// queue that is created from Requestor_DEFAULT_MODEL_QUEUE, and which is deleted
+// when the Requestor is closed. A temporary dynamic queue cannot hold persistent
+// messages, so in that case the requests are sent as non-persistent, which a
+// Responder also uses for the replies.
+//
+// A reply queue that is supplied by the application must only be used by this
+// Requestor, because every message on it is treated as a reply.
+func NewRequestor(parent jms20subset.JMSContext, requestDest jms20subset.Destination, replyQueue jms20subset.Queue) (*Requestor, jms20subset.JMSException) {
+
+	sendContext, ctxErr := parent.CreateContext(jms20subset.JMSContextAUTOACKNOWLEDGE)
+	if ctxErr != nil {
+		return nil, ctxErr
+	}
+
+	replyContext, ctxErr := parent.CreateContext(jms20subset.JMSContextAUTOACKNOWLEDGE)
+	if ctxErr != nil {
+		sendContext.Close()
+		return nil, ctxErr
+	}
+
+	// The Requestor generates the message ID of each request itself, so that it
+	// is known before the request is sent.
+	producer := sendContext.CreateProducer()
+	if mqProducer, ok := producer.(*ProducerImpl); ok {
+		mqProducer.keepMessageID = true
+	}
+
+	var consumer jms20subset.JMSConsumer
+	var conErr jms20subset.JMSException
+
+	if replyQueue != nil {
+
+		consumer, conErr = replyContext.CreateConsumer(replyQueue)
+
+	} else if mqContext, ok := replyContext.(ContextImpl); ok {
+
+		consumer, replyQueue, conErr = mqContext.createTemporaryQueueConsumer(Requestor_DEFAULT_MODEL_QUEUE)
+		producer.SetDeliveryMode(jms20subset.DeliveryMode_NON_PERSISTENT)
+
+	} else {
+
+		// Temporary queues can only be created using a connection to IBM MQ.
+		conErr = createNoReplyQueueException()
+	}
+
+	if conErr != nil {
+		sendContext.Close()
+		replyContext.Close()
+		return nil, conErr
+	}
+
+	logger := loggerOrDefault(nil)
+	if mqContext, ok := replyContext.(ContextImpl); ok {
+		logger = mqContext.logger
+	}
+
+	receiveCtx, stopReceive := context.WithCancel(context.Background())
+
+	requestor := &Requestor{
+		sendContext:  sendContext,
+		replyContext: replyContext,
+		producer:     producer,
+		consumer:     consumer,
+		requestDest:  requestDest,
+		replyQueue:   replyQueue,
+		logger:       logger,
+		pending:      make(map[string]chan requestorReply),
+		stopReceive:  stopReceive,
+		stopped:      make(chan struct{}),
+	}
+
+	go requestor.receiveReplies(receiveCtx)
+
+	return requestor, nil
+}
+
+// GetReplyQueue returns the queue from which the replies are received, which
+// is set as the JMSReplyTo of every request.
+func (requestor *Requestor) GetReplyQueue() jms20subset.Queue {
+	return requestor.replyQueue
+}
+
+// GetProducer returns the JMSProducer that is used to send the requests, so that
+// settings such as the delivery mode, priority and time to live of the requests
+// can be changed. The settings must not be changed while requests are being made.
+func (requestor *Requestor) GetProducer() jms20subset.JMSProducer {
+	return requestor.producer
+}
+
+// Request sends the request message and waits for up to timeoutMillis milliseconds
+// for its reply, or indefinitely if timeoutMillis is zero or less. If no reply
+// arrives in that time then a nil Message is returned, in the same way as
+// JMSConsumer.Receive.
+func (requestor *Requestor) Request(msg jms20subset.Message, timeoutMillis int32) (jms20subset.Message, jms20subset.JMSException) {
+
+	var timeout <-chan time.Time
+	if timeoutMillis > 0 {
+		timer := time.NewTimer(time.Duration(timeoutMillis) * time.Millisecond)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	return requestor.request(context.Background(), msg, timeout)
+}
+
+// RequestContext sends the request message and waits for its reply until the
+// supplied Go context is cancelled or reaches its deadline, in which case an
+// error is returned with the reason MQJMS_E_CONTEXT_DONE.
+func (requestor *Requestor) RequestContext(ctx context.Context, msg jms20subset.Message) (jms20subset.Message, jms20subset.JMSException) {
+	return requestor.request(ctx, msg, nil)
+}
+
+// request sends the request message and waits for its reply, the Go context to
+// be done, or the timeout channel to deliver.
+func (requestor *Requestor) request(ctx context.Context, msg jms20subset.Message, timeout <-chan time.Time) (jms20subset.Message, jms20subset.JMSException) {
+
+	replyToErr := msg.SetJMSReplyTo(requestor.replyQueue)
+	if replyToErr != nil {
+		return nil, replyToErr
+	}
+
+	// The message ID is generated here rather than by the queue manager, so that
+	// the request can be registered before it is sent, and the reply consumer
+	// finds it even if the reply arrives straight away. The lock is not held
+	// while the request is sent, so it does not hold up the delivery of replies.
+	requestID, idErr := setRequestMessageID(msg)
+	if idErr != nil {
+		return nil, idErr
+	}
+
+	replyChan := make(chan requestorReply, 1)
+
+	requestor.lock.Lock()
+
+	if requestor.stopErr != nil {
+		requestor.lock.Unlock()
+		return nil, requestor.stopErr
+	}
+
+	requestor.pending[requestID] = replyChan
+
+	requestor.lock.Unlock()
+
+	sendErr := requestor.producer.SendContext(ctx, requestor.requestDest, msg)
+	if sendErr != nil {
+		requestor.lock.Lock()
+		delete(requestor.pending, requestID)
+		requestor.lock.Unlock()
+		return nil, sendErr
+	}
+
+	var retErr jms20subset.JMSException
+
+	select {
+	case reply := <-replyChan:
+		return reply.msg, reply.err
+	case <-timeout:
+	case <-ctx.Done():
+		retErr = createContextDoneException(ctx.Err())
+	}
+
+	// Stop waiting for the reply, unless it arrived in the meantime. Once the
+	// request has been removed from pending no further reply can be delivered
+	// to it, and a reply that was delivered before then is in the channel.
+	requestor.lock.Lock()
+	delete(requestor.pending, requestID)
+	requestor.lock.Unlock()
+
+	select {
+	case reply := <-replyChan:
+		return reply.msg, reply.err
+	default:
+	}
+
+	return nil, retErr
+}
+
+// receiveReplies receives the replies from the reply queue and delivers each one
+// to the request that it matches, until the Go context is cancelled or a receive
+// fails.
+func (requestor *Requestor) receiveReplies(ctx context.Context) {
+
+	defer close(requestor.stopped)
+
+	for {
+
+		msg, rcvErr := requestor.consumer.ReceiveContext(ctx)
+
+		if rcvErr != nil {
+
+			// Cancellation of the context means that the Requestor is being closed,
+			// otherwise the failure is reported to every request.
+			if ctx.Err() != nil {
+				rcvErr = jms20subset.CreateIllegalStateException(Requestor_CLOSED_REASON, ContextImpl_ILLEGAL_STATE_CODE, nil)
+			}
+
+			requestor.stop(rcvErr)
+			return
+		}
+
+		correlID := messageIDKey(msg, true)
+
+		// The reply is delivered while the lock is held, so that a request that
+		// stops waiting after removing itself from pending always finds a reply
+		// that was routed to it. The channel is buffered, so this does not block.
+		requestor.lock.Lock()
+		replyChan, found := requestor.pending[correlID]
+		if found {
+			delete(requestor.pending, correlID)
+			replyChan <- requestorReply{msg: msg}
+		}
+		requestor.lock.Unlock()
+
+		if !found {
+			requestor.logger.Log(LogLevelWarn, "Discarded a reply that does not match an outstanding request",
+				LogField{Key: LogFieldQueue, Value: requestor.replyQueue.GetQueueName()},
+				LogField{Key: LogFieldValue, Value: correlID})
+			msg.Release()
+		}
+	}
+
+}
+
+// stop prevents any further requests from being made, and ends the wait of
+// every outstanding request with the specified error.
+func (requestor *Requestor) stop(stopErr jms20subset.JMSException) {
+
+	requestor.lock.Lock()
+	defer requestor.lock.Unlock()
+
+	requestor.stopErr = stopErr
+
+	for requestID, replyChan := range requestor.pending {
+		replyChan <- requestorReply{err: stopErr}
+		delete(requestor.pending, requestID)
+	}
+
+}
+
+// Close stops the Requestor, ending the wait of any outstanding requests with an
+// error, and closes its JMSContexts, which deletes the reply queue if it is a
+// temporary queue. Closing a Requestor that is already closed has no effect.
+func (requestor *Requestor) Close() {
+
+	requestor.stopReceive()
+	<-requestor.stopped
+
+	requestor.sendContext.Close()
+	requestor.replyContext.Close()
+
+}
+
+// Requestor_MESSAGE_ID_REASON is the reason used in the JMSException that is
+// returned when a message ID cannot be generated for a request.
+const Requestor_MESSAGE_ID_REASON string = "MQJMS_E_MESSAGE_ID"
+
+// Requestor_MESSAGE_ID_CODE is the error code used in the JMSException that is
+// returned when a message ID cannot be generated for a request.
+const Requestor_MESSAGE_ID_CODE string = "MessageIDUnavailable"
+
+// setRequestMessageID sets a new random message ID on the request, which the
+// producer of the Requestor sends in place of one allocated by the queue
+// manager, and returns it in the form that is used to match the reply.
+func setRequestMessageID(msg jms20subset.Message) (string, jms20subset.JMSException) {
+
+	mqmd := messageMQMD(msg)
+	if mqmd == nil {
+		return "", jms20subset.CreateMessageFormatException("UnexpectedMessageType", "UnexpectedMessageType-request", nil)
+	}
+
+	msgID := make([]byte, int(ibmmq.MQ_MSG_ID_LENGTH))
+	if _, err := rand.Read(msgID); err != nil {
+		return "", jms20subset.CreateJMSException(Requestor_MESSAGE_ID_REASON, Requestor_MESSAGE_ID_CODE, err)
+	}
+
+	// GetJMSCorrelationID trims trailing zero bytes as padding, so the last byte
+	// is never zero in order that the correlation ID of the reply is the same as
+	// the JMSMessageID of the request.
+	if msgID[len(msgID)-1] == 0 {
+		msgID[len(msgID)-1] = 1
+	}
+	mqmd.MsgId = msgID
+
+	return hex.EncodeToString(msgID), nil
+}
+
+// messageIDKey returns the message ID (or the correlation ID if correlID is true)
+// of a message as a string of hex digits, which are padded in the same way as
+// MQ pads the ID, so that the message ID of a request can be matched against the
+// correlation ID of its reply.
+func messageIDKey(msg jms20subset.Message, correlID bool) string {
+
+	mqmd := messageMQMD(msg)
+
+	id := make([]byte, int(ibmmq.MQ_MSG_ID_LENGTH))
+
+	if mqmd != nil {
+		if correlID {
+			copy(id, mqmd.CorrelId)
+		} else {
+			copy(id, mqmd.MsgId)
+		}
+	}
+
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"context"
+
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// Responder sends replies to request messages, such as those sent by a Requestor.
+//
+// Each reply is sent to the JMSReplyTo destination of its request, with its
+// JMSCorrelationID set to the message ID of the request, and with the same
+// delivery mode as the request. The replies are sent using the JMSContext that
+// the Responder was created from, so they are part of its transaction if it is
+// transacted, along with the receipt of the request.
+type Responder struct {
+	context jms20subset.JMSContext
+}
+
+// NewResponder creates a Responder that sends replies using the specified JMSContext.
+func NewResponder(context jms20subset.JMSContext) *Responder {
+	return &Responder{
+		context: context,
+	}
+}
+
+// Reply sends the reply message in response to the request message. An error
+// is returned if the request does not have a JMSReplyTo destination.
+func (responder *Responder) Reply(request jms20subset.Message, reply jms20subset.Message) jms20subset.JMSException {
+	return responder.ReplyContext(context.Background(), request, reply)
+}
+
+// ReplyContext sends the reply message in response to the request message, in
+// the same way as Reply, but gives up waiting to send it if the supplied Go
+// context is cancelled or reaches its deadline.
+func (responder *Responder) ReplyContext(ctx context.Context, request jms20subset.Message, reply jms20subset.Message) jms20subset.JMSException {
+
+	replyDest := request.GetJMSReplyTo()
+	if replyDest == nil {
+		return createNoReplyQueueException()
+	}
+
+	correlErr := reply.SetJMSCorrelationID(request.GetJMSMessageID())
+	if correlErr != nil {
+		return correlErr
+	}
+
+	// A producer holds the settings for a single send, so each reply uses its
+	// own so that replies can be sent from several goroutines at once.
+	producer := responder.context.CreateProducer()
+	producer.SetDeliveryMode(request.GetJMSDeliveryMode())
+
+	return producer.SendContext(ctx, replyDest, reply)
+}
@@ -0,0 +1,135 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"context"
+	"io"
+	"time"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// streamReader is the io.ReadCloser that is returned by ConsumerImpl.ReceiveStream,
+// which receives the messages of a group one at a time as their bodies are read,
+// so that only one message of the group is held in memory.
+type streamReader struct {
+	consumer ConsumerImpl
+	groupID  []byte
+	body     []byte // Part of the body of the current message that has not been read
+	last     bool   // Whether the current message is the last of the group
+	err      error  // Failure that ended the stream, or io.EOF once it has all been read
+	closed   bool
+}
+
+// setMessage makes the message the current message of the stream, and releases it
+// as only its body is needed.
+func (reader *streamReader) setMessage(msg jms20subset.Message) {
+
+	mqmd := messageMQMD(msg)
+	reader.groupID = append([]byte{}, mqmd.GroupId...)
+	reader.last = isLastInGroup(mqmd)
+
+	switch typedMsg := msg.(type) {
+	case jms20subset.TextMessage:
+		reader.body = nil
+		if typedMsg.GetText() != nil {
+			reader.body = []byte(*typedMsg.GetText())
+		}
+
+	case jms20subset.BytesMessage:
+		reader.body = *typedMsg.ReadBytes()
+	}
+
+	msg.Release()
+}
+
+// Read reads data from the body of the current message, receiving the next
+// message of the group once it has all been read. io.EOF is returned once the
+// body of the last message has been read, and the group has been committed if
+// the JMSContext is not transacted.
+func (reader *streamReader) Read(p []byte) (int, error) {
+
+	if reader.closed {
+		return 0, jms20subset.CreateIllegalStateException(ConsumerImpl_STREAM_CLOSED_REASON, ContextImpl_ILLEGAL_STATE_CODE, nil)
+	}
+
+	if reader.err != nil {
+		return 0, reader.err
+	}
+
+	transacted := reader.consumer.ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED
+
+	for len(reader.body) == 0 {
+
+		if reader.last {
+
+			reader.err = io.EOF
+			if !transacted {
+				if jmsErr := reader.consumer.ctx.completeUnitOfWork(true); jmsErr != nil {
+					reader.err = jmsErr
+				}
+			}
+
+			return 0, reader.err
+		}
+
+		// The rest of the group is already on the queue, so there is no need to wait.
+		msg, jmsErr := reader.consumer.receiveInternal(context.Background(), time.Now(), ibmmq.NewMQGMO(), reader.groupID, nil)
+
+		if msg == nil && jmsErr == nil {
+			// Part of the group has been received by another consumer.
+			jmsErr = jms20subset.CreateJMSException(ConsumerImpl_GROUP_INCOMPLETE_REASON, ConsumerImpl_GROUP_INCOMPLETE_CODE, nil)
+		}
+
+		if jmsErr != nil {
+			reader.err = jmsErr
+			if !transacted {
+				reader.consumer.ctx.completeUnitOfWork(false)
+			}
+			return 0, jmsErr
+		}
+
+		reader.setMessage(msg)
+	}
+
+	n := copy(p, reader.body)
+	reader.body = reader.body[n:]
+
+	return n, nil
+}
+
+// Close closes the reader. If the JMSContext is not transacted then the unit of
+// work of the group is committed if all of its data has been read, even if Read
+// has not yet returned io.EOF, and is otherwise backed out so that the group is
+// left on the queue. An error is returned if the commit fails. Closing a reader
+// that is already closed has no effect.
+func (reader *streamReader) Close() error {
+
+	if reader.closed {
+		return nil
+	}
+	reader.closed = true
+
+	if reader.err != nil || reader.consumer.ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED {
+		return nil
+	}
+
+	reader.err = io.EOF
+
+	jmsErr := reader.consumer.ctx.completeUnitOfWork(reader.last && len(reader.body) == 0)
+
+	if jmsErr != nil {
+		return jmsErr
+	}
+
+	return nil
+}
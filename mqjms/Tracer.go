@@ -0,0 +1,251 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// Tracer creates the spans that describe the messaging operations carried out
+// by a JMSContext, and converts between spans and the W3C trace context that is
+// carried in the properties of a message, so that a distributed trace can follow
+// a message from the application that sent it to the one that received it.
+//
+// This is deliberately small enough to be implemented on top of OpenTelemetry or
+// any other tracing library without this module depending on one. The methods
+// are called while the JMSContext is locked, so they must not call back into
+// the JMSContext or the objects created from it.
+type Tracer interface {
+
+	// StartSpan starts a span with the specified name and kind as a child of the
+	// span (if any) in the Go context, and returns a Go context containing the
+	// new span.
+	StartSpan(ctx context.Context, name string, kind SpanKind, attrs ...SpanAttribute) (context.Context, Span)
+
+	// Inject returns the trace context of the span in the Go context, to be sent
+	// in the properties of a message. An empty TraceParent means there is nothing
+	// to send.
+	Inject(ctx context.Context) TraceContext
+
+	// Extract returns a Go context whose parent span is described by the trace
+	// context that was received in the properties of a message.
+	Extract(ctx context.Context, tc TraceContext) context.Context
+}
+
+// Span is an individual operation within a trace, created by a Tracer.
+type Span interface {
+	SetAttributes(attrs ...SpanAttribute)
+	RecordError(err error)
+	End()
+}
+
+// SpanKind describes the relationship between a span and the remote side of the
+// operation, in the same way as the span kinds of OpenTelemetry.
+type SpanKind int
+
+// The kinds of span that are created by this package.
+const (
+	SpanKindInternal SpanKind = iota // Commit and rollback
+	SpanKindProducer                 // Sending a message
+	SpanKindConsumer                 // Receiving a message
+)
+
+// SpanAttribute is a single named value that describes a span.
+type SpanAttribute struct {
+	Key   string
+	Value interface{}
+}
+
+// TraceContext holds the values of the W3C trace context headers, which are
+// carried in the message properties named by TraceParentProperty and
+// TraceStateProperty.
+type TraceContext struct {
+	TraceParent string
+	TraceState  string
+}
+
+// The names of the message properties that carry the W3C trace context.
+const (
+	TraceParentProperty = "traceparent"
+	TraceStateProperty  = "tracestate"
+)
+
+// The keys of the span attributes, which follow the OpenTelemetry semantic
+// conventions for messaging systems.
+const (
+	SpanAttrMessagingSystem  = "messaging.system"
+	SpanAttrOperation        = "messaging.operation"
+	SpanAttrDestinationName  = "messaging.destination.name"
+	SpanAttrMessageID        = "messaging.message.id"
+	SpanAttrConversationID   = "messaging.message.conversation_id"
+	SpanAttrMessageBodySize  = "messaging.message.body.size"
+	SpanAttrQueueManagerName = "messaging.ibmmq.queue_manager"
+)
+
+// The values of the messaging.system and messaging.operation attributes.
+const (
+	messagingSystemIBMMQ       = "ibmmq"
+	messagingOperationPublish  = "publish"
+	messagingOperationReceive  = "receive"
+	messagingOperationCommit   = "commit"
+	messagingOperationRollback = "rollback"
+)
+
+// TraceContextFromMessage returns the trace context that was carried in the
+// properties of a received message, so that an application can continue the
+// trace while it processes the message, by passing it to Tracer.Extract.
+func TraceContextFromMessage(msg jms20subset.Message) TraceContext {
+
+	var tc TraceContext
+
+	if traceParent, err := msg.GetStringProperty(TraceParentProperty); err == nil && traceParent != nil {
+		tc.TraceParent = *traceParent
+	}
+
+	if traceState, err := msg.GetStringProperty(TraceStateProperty); err == nil && traceState != nil {
+		tc.TraceState = *traceState
+	}
+
+	return tc
+}
+
+// startOperationSpan starts a span for a commit or rollback of the transaction
+// of a JMSContext, returning nil if tracing is not enabled.
+func (ctx ContextImpl) startOperationSpan(operation string) Span {
+
+	if ctx.tracer == nil {
+		return nil
+	}
+
+	_, span := ctx.tracer.StartSpan(context.Background(), operation, SpanKindInternal,
+		SpanAttribute{Key: SpanAttrMessagingSystem, Value: messagingSystemIBMMQ},
+		SpanAttribute{Key: SpanAttrOperation, Value: operation},
+		SpanAttribute{Key: SpanAttrQueueManagerName, Value: ctx.factory.QMName})
+
+	return span
+}
+
+// startSendSpan starts the span that describes sending a message, and adds the
+// trace context to the properties of the message so that it travels with it.
+// Returns nil if tracing is not enabled.
+func (ctx ContextImpl) startSendSpan(goCtx context.Context, queueName string, msgHandle mqiMessageHandle) Span {
+
+	if ctx.tracer == nil {
+		return nil
+	}
+
+	spanCtx, span := ctx.tracer.StartSpan(goCtx, queueName+" "+messagingOperationPublish, SpanKindProducer,
+		SpanAttribute{Key: SpanAttrMessagingSystem, Value: messagingSystemIBMMQ},
+		SpanAttribute{Key: SpanAttrOperation, Value: messagingOperationPublish},
+		SpanAttribute{Key: SpanAttrDestinationName, Value: queueName},
+		SpanAttribute{Key: SpanAttrQueueManagerName, Value: ctx.factory.QMName})
+
+	tc := ctx.tracer.Inject(spanCtx)
+	if tc.TraceParent != "" {
+
+		smpo := ibmmq.NewMQSMPO()
+		pd := ibmmq.NewMQPD()
+		err := msgHandle.SetMP(smpo, TraceParentProperty, pd, tc.TraceParent)
+
+		if err == nil && tc.TraceState != "" {
+			err = msgHandle.SetMP(smpo, TraceStateProperty, pd, tc.TraceState)
+		}
+
+		if err != nil {
+			ctx.logger.Log(LogLevelWarn, "Failed to set trace context on message",
+				LogField{Key: LogFieldQueue, Value: queueName}, mqrcField(err))
+		}
+	}
+
+	return span
+}
+
+// recordReceiveSpan creates the span that describes receiving a message, as a
+// child of the span that sent the message if the message carries a trace context.
+// The span ends straight away, as the processing of the message by the application
+// is not part of the receive.
+func (ctx ContextImpl) recordReceiveSpan(goCtx context.Context, queueName string, mqmd *ibmmq.MQMD, msgHandle mqiMessageHandle, bodySize int) {
+
+	if ctx.tracer == nil {
+		return
+	}
+
+	var tc TraceContext
+	impo := ibmmq.NewMQIMPO()
+	pd := ibmmq.NewMQPD()
+
+	if _, value, err := msgHandle.InqMP(impo, pd, TraceParentProperty); err == nil {
+		tc.TraceParent, _ = value.(string)
+	}
+
+	if _, value, err := msgHandle.InqMP(impo, pd, TraceStateProperty); err == nil {
+		tc.TraceState, _ = value.(string)
+	}
+
+	if tc.TraceParent != "" {
+		goCtx = ctx.tracer.Extract(goCtx, tc)
+	}
+
+	_, span := ctx.tracer.StartSpan(goCtx, queueName+" "+messagingOperationReceive, SpanKindConsumer,
+		SpanAttribute{Key: SpanAttrMessagingSystem, Value: messagingSystemIBMMQ},
+		SpanAttribute{Key: SpanAttrOperation, Value: messagingOperationReceive},
+		SpanAttribute{Key: SpanAttrDestinationName, Value: queueName},
+		SpanAttribute{Key: SpanAttrQueueManagerName, Value: ctx.factory.QMName})
+
+	span.SetAttributes(messageSpanAttributes(mqmd, bodySize)...)
+	span.End()
+}
+
+// endSpan records the outcome of an operation on its span and ends it. It has
+// no effect if the span is nil because tracing is not enabled.
+func endSpan(span Span, err error, attrs ...SpanAttribute) {
+
+	if span == nil {
+		return
+	}
+
+	if len(attrs) > 0 {
+		span.SetAttributes(attrs...)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	span.End()
+}
+
+// messageSpanAttributes returns the attributes that describe an individual
+// message, for the span that sent or received it.
+func messageSpanAttributes(mqmd *ibmmq.MQMD, bodySize int) []SpanAttribute {
+
+	attrs := []SpanAttribute{
+		{Key: SpanAttrMessageBodySize, Value: bodySize},
+	}
+
+	if mqmd != nil {
+
+		if len(mqmd.MsgId) > 0 {
+			attrs = append(attrs, SpanAttribute{Key: SpanAttrMessageID, Value: hex.EncodeToString(mqmd.MsgId)})
+		}
+
+		// An unset correlation ID is all zeros, which isn't worth reporting.
+		if len(mqmd.CorrelId) > 0 && !bytes.Equal(mqmd.CorrelId, make([]byte, len(mqmd.CorrelId))) {
+			attrs = append(attrs, SpanAttribute{Key: SpanAttrConversationID, Value: hex.EncodeToString(mqmd.CorrelId)})
+		}
+	}
+
+	return attrs
+}
@@ -0,0 +1,925 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+package mqjms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// fakeQueueManager is an in-memory stand in for a queue manager, which is used
+// through the mqiQueueManager interface so that the paths of this package that
+// depend on the outcome of MQI calls can be tested without a queue manager.
+//
+// The outcome of the calls can be scripted, so that a test can make the next
+// call of a particular verb fail with a chosen reason code, or set the outcome
+// of the next asynchronous put that is reported by MQSTAT.
+type fakeQueueManager struct {
+	lock           sync.Mutex
+	name           string
+	queues         map[string][]*fakeMessage
+	maxMsgLengths  map[string]int // Largest message that each queue can hold, if limited
+	nextSeq        uint64
+	failures       map[string][]int32 // Reason codes for the next calls of each verb
+	calls          map[string]int     // Number of calls of each verb
+	asyncResults   []int32            // Completion codes for the next asynchronous puts
+	status         ibmmq.MQSTS        // Asynchronous put status, reset by MQSTAT
+	liveHandles    int                // Message handles created and not yet deleted
+	deletedHandles int
+}
+
+// fakeMessage is a message held on a queue of a fakeQueueManager.
+type fakeMessage struct {
+	seq        uint64
+	mqmd       ibmmq.MQMD
+	body       []byte
+	properties []fakeProperty
+}
+
+// fakeProperty is a message property, held in the order it was first set.
+type fakeProperty struct {
+	name  string
+	value interface{}
+}
+
+// fakeConnection is a connection handle to a fakeQueueManager, which has its
+// own unit of work.
+type fakeConnection struct {
+	qm           *fakeQueueManager
+	disconnected bool
+	pendingPuts  []fakePendingPut
+	pendingGets  []fakePendingGet
+}
+
+type fakePendingPut struct {
+	queueName string
+	msg       *fakeMessage
+}
+
+type fakePendingGet struct {
+	queueName string
+	msg       *fakeMessage
+}
+
+// fakeObject is a queue that has been opened using a fakeConnection.
+type fakeObject struct {
+	conn       *fakeConnection
+	name       string
+	browsed    *fakeMessage // Last message returned by a browse
+	putGroupID []byte       // Group being put in logical order, until its last message
+	putSeq     int32        // Sequence number of the last message put in the group
+	putOffset  int32        // Offset of the next segment of the message being put
+	putSegment bool         // Whether a message is being put as segments
+}
+
+// fakeMessageHandle holds the properties of a message in the same way as an
+// MQ message handle.
+type fakeMessageHandle struct {
+	conn       *fakeConnection
+	properties []fakeProperty
+	cursor     int // Position of the last property returned by an inquire of "%"
+	deleted    bool
+}
+
+// newFakeQueueManager creates a fakeQueueManager with no messages.
+func newFakeQueueManager(name string) *fakeQueueManager {
+	return &fakeQueueManager{
+		name:          name,
+		queues:        make(map[string][]*fakeMessage),
+		maxMsgLengths: make(map[string]int),
+		failures:      make(map[string][]int32),
+		calls:         make(map[string]int),
+	}
+}
+
+// useFakeMQI routes the connections made by this package to the fakeQueueManager
+// until the end of the test.
+func useFakeMQI(t *testing.T, qm *fakeQueueManager) {
+
+	previous := connectMQI
+	connectMQI = func(qMgrName string, cno *ibmmq.MQCNO) (mqiQueueManager, error) {
+		return qm.connect()
+	}
+
+	t.Cleanup(func() {
+		connectMQI = previous
+	})
+}
+
+// fail makes the next call of the MQI verb (such as "MQGET") fail with the
+// specified reason code. Repeated calls script the calls after that in turn.
+func (qm *fakeQueueManager) fail(verb string, mqrc int32) {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+	qm.failures[verb] = append(qm.failures[verb], mqrc)
+}
+
+// setAsyncPutResult sets the completion code of the next asynchronous put, as it
+// is reported by MQSTAT. A message with MQCC_FAILED is not delivered to the queue.
+func (qm *fakeQueueManager) setAsyncPutResult(mqcc int32) {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+	qm.asyncResults = append(qm.asyncResults, mqcc)
+}
+
+// setMaxMsgLength sets the largest message that the queue can hold, so that a
+// larger message is segmented by the queue manager if that is allowed, and is
+// otherwise rejected with MQRC_MSG_TOO_BIG_FOR_Q.
+func (qm *fakeQueueManager) setMaxMsgLength(queueName string, maxMsgLength int) {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+	qm.maxMsgLengths[queueName] = maxMsgLength
+}
+
+// callCount returns the number of calls that have been made of the MQI verb.
+func (qm *fakeQueueManager) callCount(verb string) int {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+	return qm.calls[verb]
+}
+
+// depth returns the number of committed messages on the queue.
+func (qm *fakeQueueManager) depth(queueName string) int {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+	return len(qm.queues[queueName])
+}
+
+// handleCounts returns the number of message handles that have been created and
+// not yet deleted, and the number that have been deleted.
+func (qm *fakeQueueManager) handleCounts() (int, int) {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+	return qm.liveHandles, qm.deletedHandles
+}
+
+// call records a call of the MQI verb, and returns the scripted failure for it
+// if there is one. The lock must be held.
+func (qm *fakeQueueManager) call(verb string) error {
+
+	qm.calls[verb]++
+
+	if scripted := qm.failures[verb]; len(scripted) > 0 {
+		qm.failures[verb] = scripted[1:]
+		return fakeMQReturn(ibmmq.MQCC_FAILED, scripted[0])
+	}
+
+	return nil
+}
+
+func (qm *fakeQueueManager) connect() (mqiQueueManager, error) {
+
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	if err := qm.call("MQCONNX"); err != nil {
+		return nil, err
+	}
+
+	return &fakeConnection{qm: qm}, nil
+}
+
+// fakeMQReturn creates an error in the same form as those returned by the MQI.
+func fakeMQReturn(mqcc int32, mqrc int32) error {
+	return &ibmmq.MQReturn{MQCC: mqcc, MQRC: mqrc}
+}
+
+// checkConnection returns an error if the connection has been disconnected.
+// The lock must be held.
+func (conn *fakeConnection) checkConnection(verb string) error {
+
+	if conn.disconnected {
+		conn.qm.calls[verb]++
+		return fakeMQReturn(ibmmq.MQCC_FAILED, ibmmq.MQRC_HCONN_ERROR)
+	}
+
+	return conn.qm.call(verb)
+}
+
+func (conn *fakeConnection) Open(mqod *ibmmq.MQOD, openOptions int32) (mqiObject, error) {
+
+	conn.qm.lock.Lock()
+	defer conn.qm.lock.Unlock()
+
+	if err := conn.checkConnection("MQOPEN"); err != nil {
+		return nil, err
+	}
+
+	// The dynamic queue name is only used when the queue is a model queue, which
+	// the fake recognises by its name, in which case a new queue is created in
+	// the same way as the queue manager would.
+	if strings.Contains(mqod.ObjectName, ".MODEL.") && mqod.DynamicQName != "" {
+		conn.qm.nextSeq++
+		mqod.ObjectName = strings.TrimSuffix(mqod.DynamicQName, "*") + fmt.Sprintf("%016X", conn.qm.nextSeq)
+	}
+
+	return &fakeObject{conn: conn, name: mqod.ObjectName}, nil
+}
+
+func (conn *fakeConnection) Put1(mqod *ibmmq.MQOD, mqmd *ibmmq.MQMD, pmo *ibmmq.MQPMO, msgHandle mqiMessageHandle, buffer []byte) error {
+
+	conn.qm.lock.Lock()
+	defer conn.qm.lock.Unlock()
+
+	if err := conn.checkConnection("MQPUT1"); err != nil {
+		return err
+	}
+
+	return conn.put(mqod.ObjectName, mqmd, pmo, msgHandle, buffer)
+}
+
+func (conn *fakeConnection) CrtMH(cmho *ibmmq.MQCMHO) (mqiMessageHandle, error) {
+
+	conn.qm.lock.Lock()
+	defer conn.qm.lock.Unlock()
+
+	if err := conn.checkConnection("MQCRTMH"); err != nil {
+		return nil, err
+	}
+
+	conn.qm.liveHandles++
+	return &fakeMessageHandle{conn: conn}, nil
+}
+
+func (conn *fakeConnection) Cmit() error {
+
+	conn.qm.lock.Lock()
+	defer conn.qm.lock.Unlock()
+
+	if err := conn.checkConnection("MQCMIT"); err != nil {
+
+		// A failed commit backs out the unit of work, in the same way as
+		// MQRC_BACKED_OUT from a queue manager.
+		conn.backOut()
+		return err
+	}
+
+	for _, pending := range conn.pendingPuts {
+		conn.qm.enqueue(pending.queueName, pending.msg)
+	}
+
+	conn.pendingPuts = nil
+	conn.pendingGets = nil
+	return nil
+}
+
+func (conn *fakeConnection) Back() error {
+
+	conn.qm.lock.Lock()
+	defer conn.qm.lock.Unlock()
+
+	if err := conn.checkConnection("MQBACK"); err != nil {
+		return err
+	}
+
+	conn.backOut()
+	return nil
+}
+
+// backOut discards the messages put in the unit of work and restores those
+// that were got. The lock must be held.
+func (conn *fakeConnection) backOut() {
+
+	for _, pending := range conn.pendingGets {
+		conn.qm.enqueue(pending.queueName, pending.msg)
+	}
+
+	conn.pendingPuts = nil
+	conn.pendingGets = nil
+}
+
+func (conn *fakeConnection) Stat(statusType int32, sts *ibmmq.MQSTS) error {
+
+	conn.qm.lock.Lock()
+	defer conn.qm.lock.Unlock()
+
+	if err := conn.checkConnection("MQSTAT"); err != nil {
+		return err
+	}
+
+	// The counts are reset each time that they are reported.
+	*sts = conn.qm.status
+	conn.qm.status = *ibmmq.NewMQSTS()
+	return nil
+}
+
+func (conn *fakeConnection) Disc() error {
+
+	conn.qm.lock.Lock()
+	defer conn.qm.lock.Unlock()
+
+	if err := conn.checkConnection("MQDISC"); err != nil {
+		return err
+	}
+
+	conn.disconnected = true
+	return nil
+}
+
+// put stores a message that is put using the connection. The lock must be held.
+func (conn *fakeConnection) put(queueName string, mqmd *ibmmq.MQMD, pmo *ibmmq.MQPMO, msgHandle mqiMessageHandle, buffer []byte) error {
+
+	qm := conn.qm
+	qm.nextSeq++
+
+	if pmo.Options&ibmmq.MQPMO_NEW_MSG_ID != 0 {
+		mqmd.MsgId = fakeMessageID(qm.name, qm.nextSeq)
+	}
+
+	now := time.Now().UTC()
+	mqmd.PutDate = now.Format("20060102")
+	mqmd.PutTime = now.Format("150405") + now.Format(".00")[1:]
+
+	msg := &fakeMessage{
+		seq:  qm.nextSeq,
+		mqmd: *mqmd,
+		body: append([]byte{}, buffer...),
+	}
+	msg.mqmd.MsgId = append([]byte{}, mqmd.MsgId...)
+	msg.mqmd.CorrelId = append([]byte{}, mqmd.CorrelId...)
+	msg.mqmd.GroupId = append([]byte{}, mqmd.GroupId...)
+
+	if handle, ok := msgHandle.(*fakeMessageHandle); ok {
+		msg.properties = append([]fakeProperty{}, handle.properties...)
+	}
+
+	msgs := []*fakeMessage{msg}
+
+	// A message that is too big for the queue is segmented by the queue manager,
+	// if the application allows it.
+	if maxMsgLength := qm.maxMsgLengths[queueName]; maxMsgLength > 0 && len(buffer) > maxMsgLength {
+
+		if mqmd.Version < ibmmq.MQMD_VERSION_2 || mqmd.MsgFlags&ibmmq.MQMF_SEGMENTATION_ALLOWED == 0 {
+			return fakeMQReturn(ibmmq.MQCC_FAILED, ibmmq.MQRC_MSG_TOO_BIG_FOR_Q)
+		}
+
+		msgs = qm.segment(msg, maxMsgLength)
+	}
+
+	// The outcome of an asynchronous put is only reported later, by MQSTAT.
+	if pmo.Options&ibmmq.MQPMO_ASYNC_RESPONSE != 0 {
+
+		mqcc := ibmmq.MQCC_OK
+		if len(qm.asyncResults) > 0 {
+			mqcc = qm.asyncResults[0]
+			qm.asyncResults = qm.asyncResults[1:]
+		}
+
+		switch mqcc {
+		case ibmmq.MQCC_OK:
+			qm.status.PutSuccessCount++
+		case ibmmq.MQCC_WARNING:
+			qm.status.PutWarningCount++
+		default:
+			qm.status.PutFailureCount++
+		}
+
+		if mqcc != ibmmq.MQCC_OK && qm.status.CompCode == ibmmq.MQCC_OK {
+			qm.status.CompCode = mqcc
+			qm.status.Reason = ibmmq.MQRC_Q_FULL
+		}
+
+		if mqcc == ibmmq.MQCC_FAILED {
+			return nil
+		}
+	}
+
+	for _, msg := range msgs {
+		if pmo.Options&ibmmq.MQPMO_SYNCPOINT != 0 {
+			conn.pendingPuts = append(conn.pendingPuts, fakePendingPut{queueName: queueName, msg: msg})
+		} else {
+			qm.enqueue(queueName, msg)
+		}
+	}
+
+	return nil
+}
+
+// segment splits a message into segments of at most maxMsgLength bytes, in the
+// same way as the queue manager. The properties are carried by the first
+// segment. The lock must be held.
+func (qm *fakeQueueManager) segment(msg *fakeMessage, maxMsgLength int) []*fakeMessage {
+
+	// A message that is not already part of a group or segmented is given a
+	// group ID of its own.
+	if msg.mqmd.MsgFlags&(ibmmq.MQMF_MSG_IN_GROUP|ibmmq.MQMF_LAST_MSG_IN_GROUP|ibmmq.MQMF_SEGMENT) == 0 {
+		msg.mqmd.GroupId = fakeMessageID(qm.name, qm.nextSeq)
+		msg.mqmd.MsgSeqNumber = 1
+		msg.mqmd.Offset = 0
+	}
+
+	lastSegment := msg.mqmd.MsgFlags&ibmmq.MQMF_SEGMENT == 0 || msg.mqmd.MsgFlags&ibmmq.MQMF_LAST_SEGMENT != 0
+
+	var segments []*fakeMessage
+
+	for offset := 0; offset < len(msg.body); offset += maxMsgLength {
+
+		end := offset + maxMsgLength
+		if end > len(msg.body) {
+			end = len(msg.body)
+		}
+
+		segment := &fakeMessage{
+			seq:  msg.seq,
+			mqmd: msg.mqmd,
+			body: msg.body[offset:end],
+		}
+		segment.mqmd.Offset = msg.mqmd.Offset + int32(offset)
+		segment.mqmd.MsgFlags |= ibmmq.MQMF_SEGMENT
+		segment.mqmd.MsgFlags &^= ibmmq.MQMF_LAST_SEGMENT
+
+		if offset == 0 {
+			segment.properties = msg.properties
+		} else {
+			qm.nextSeq++
+			segment.seq = qm.nextSeq
+		}
+
+		if end == len(msg.body) && lastSegment {
+			segment.mqmd.MsgFlags |= ibmmq.MQMF_LAST_SEGMENT
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments
+}
+
+// enqueue adds a message to a queue, in priority order. The lock must be held.
+func (qm *fakeQueueManager) enqueue(queueName string, msg *fakeMessage) {
+
+	queue := append(qm.queues[queueName], msg)
+	sort.SliceStable(queue, func(i, j int) bool {
+		return fakeBefore(queue[i], queue[j])
+	})
+	qm.queues[queueName] = queue
+}
+
+// fakeBefore returns whether message a is ahead of message b on a queue.
+func fakeBefore(a *fakeMessage, b *fakeMessage) bool {
+
+	if a.mqmd.Priority != b.mqmd.Priority {
+		return a.mqmd.Priority > b.mqmd.Priority
+	}
+
+	return a.seq < b.seq
+}
+
+// fakeMessageID returns a unique message ID in the same format as MQ.
+func fakeMessageID(qmName string, seq uint64) []byte {
+
+	msgID := make([]byte, 24)
+	copy(msgID, []byte("AMQ " + qmName + "            ")[:16])
+	binary.BigEndian.PutUint64(msgID[16:], seq)
+	return msgID
+}
+
+func (object *fakeObject) Name() string {
+	return object.name
+}
+
+func (object *fakeObject) Get(mqmd *ibmmq.MQMD, gmo *ibmmq.MQGMO, msgHandle mqiMessageHandle, buffer []byte) (int, error) {
+
+	qm := object.conn.qm
+
+	var deadline time.Time
+	if gmo.Options&ibmmq.MQGMO_WAIT != 0 {
+		deadline = time.Now().Add(time.Duration(gmo.WaitInterval) * time.Millisecond)
+	}
+
+	for {
+
+		qm.lock.Lock()
+		datalen, err := object.getOnce(mqmd, gmo, msgHandle, buffer)
+		qm.lock.Unlock()
+
+		noMsg := false
+		if mqret, ok := err.(*ibmmq.MQReturn); ok {
+			noMsg = mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE
+		}
+
+		if !noMsg || !time.Now().Before(deadline) {
+			return datalen, err
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// getOnce makes a single attempt to get a message. The lock must be held.
+func (object *fakeObject) getOnce(mqmd *ibmmq.MQMD, gmo *ibmmq.MQGMO, msgHandle mqiMessageHandle, buffer []byte) (int, error) {
+
+	conn := object.conn
+	qm := conn.qm
+
+	if err := conn.checkConnection("MQGET"); err != nil {
+		return 0, err
+	}
+
+	browse := gmo.Options&(ibmmq.MQGMO_BROWSE_FIRST|ibmmq.MQGMO_BROWSE_NEXT) != 0
+	if gmo.Options&ibmmq.MQGMO_BROWSE_FIRST != 0 {
+		object.browsed = nil
+	}
+
+	queue := qm.queues[object.name]
+	index := -1
+
+	matchGroup := gmo.MatchOptions&ibmmq.MQMO_MATCH_GROUP_ID != 0
+	matchSeq := gmo.MatchOptions&ibmmq.MQMO_MATCH_MSG_SEQ_NUMBER != 0
+	matchOffset := gmo.MatchOptions&ibmmq.MQMO_MATCH_OFFSET != 0
+	completeMsg := gmo.Options&ibmmq.MQGMO_COMPLETE_MSG != 0
+	logicalOrder := gmo.Options&ibmmq.MQGMO_LOGICAL_ORDER != 0
+	startOfGroup := fakeMatches(mqmd.GroupId, nil)
+	continuing := logicalOrder && !startOfGroup
+
+	for i, msg := range queue {
+
+		if browse && object.browsed != nil && !fakeBefore(object.browsed, msg) {
+			continue
+		}
+
+		if !fakeMatches(mqmd.MsgId, msg.mqmd.MsgId) || !fakeMatches(mqmd.CorrelId, msg.mqmd.CorrelId) {
+			continue
+		}
+
+		if matchGroup && !fakeMatches(mqmd.GroupId, msg.mqmd.GroupId) {
+			continue
+		}
+
+		if (matchSeq && mqmd.MsgSeqNumber != msg.mqmd.MsgSeqNumber) || (matchOffset && mqmd.Offset != msg.mqmd.Offset) {
+			continue
+		}
+
+		inGroup := msg.mqmd.MsgFlags&(ibmmq.MQMF_MSG_IN_GROUP|ibmmq.MQMF_LAST_MSG_IN_GROUP) != 0
+		segment := msg.mqmd.MsgFlags&ibmmq.MQMF_SEGMENT != 0
+
+		if logicalOrder && startOfGroup {
+
+			// A group is started from the first segment of its first message,
+			// and only once all of its messages have arrived if that has been
+			// asked for.
+			if (inGroup && msg.mqmd.MsgSeqNumber != 1) || msg.mqmd.Offset != 0 {
+				continue
+			}
+			if inGroup && gmo.Options&ibmmq.MQGMO_ALL_MSGS_AVAILABLE != 0 && !fakeGroupComplete(queue, msg.mqmd.GroupId) {
+				continue
+			}
+		}
+
+		// A segmented message is returned as a whole from its first segment, or
+		// one segment at a time once all of them have arrived if that has been
+		// asked for.
+		if segment && (completeMsg || gmo.Options&ibmmq.MQGMO_ALL_SEGMENTS_AVAILABLE != 0) {
+			if _, complete := fakeSegments(queue, msg); !complete || (completeMsg && msg.mqmd.Offset != 0) {
+				continue
+			}
+		}
+
+		// Within a group the messages, and the segments of each message, are
+		// returned in order.
+		if continuing && index >= 0 && !fakeLogicallyBefore(msg, queue[index]) {
+			continue
+		}
+
+		index = i
+		if !continuing {
+			break
+		}
+	}
+
+	if index < 0 {
+		return 0, fakeMQReturn(ibmmq.MQCC_FAILED, ibmmq.MQRC_NO_MSG_AVAILABLE)
+	}
+
+	msg := queue[index]
+	parts := []*fakeMessage{msg}
+	body := msg.body
+	gotmqmd := msg.mqmd
+
+	if completeMsg && msg.mqmd.MsgFlags&ibmmq.MQMF_SEGMENT != 0 {
+
+		parts, _ = fakeSegments(queue, msg)
+		body = nil
+		for _, part := range parts {
+			body = append(body, part.body...)
+		}
+
+		gotmqmd.MsgFlags &^= ibmmq.MQMF_SEGMENT | ibmmq.MQMF_LAST_SEGMENT
+	}
+
+	datalen := len(body)
+
+	// A message that does not fit in the buffer is left on the queue, unless the
+	// application has agreed to accept a truncated message.
+	var err error
+	if datalen > len(buffer) {
+		if gmo.Options&ibmmq.MQGMO_ACCEPT_TRUNCATED_MSG == 0 {
+			return datalen, fakeMQReturn(ibmmq.MQCC_WARNING, ibmmq.MQRC_TRUNCATED_MSG_FAILED)
+		}
+		err = fakeMQReturn(ibmmq.MQCC_WARNING, ibmmq.MQRC_TRUNCATED_MSG_ACCEPTED)
+	}
+
+	copy(buffer, body)
+	*mqmd = gotmqmd
+	mqmd.MsgId = append([]byte{}, msg.mqmd.MsgId...)
+	mqmd.CorrelId = append([]byte{}, msg.mqmd.CorrelId...)
+	mqmd.GroupId = append([]byte{}, msg.mqmd.GroupId...)
+
+	if handle, ok := msgHandle.(*fakeMessageHandle); ok && gmo.Options&ibmmq.MQGMO_NO_PROPERTIES == 0 {
+		handle.properties = append([]fakeProperty{}, msg.properties...)
+	}
+
+	if browse {
+		object.browsed = msg
+		return datalen, err
+	}
+
+	remaining := make([]*fakeMessage, 0, len(queue))
+	for _, queued := range queue {
+		if !fakeContains(parts, queued) {
+			remaining = append(remaining, queued)
+		}
+	}
+	qm.queues[object.name] = remaining
+
+	if gmo.Options&ibmmq.MQGMO_SYNCPOINT != 0 {
+		for _, part := range parts {
+			conn.pendingGets = append(conn.pendingGets, fakePendingGet{queueName: object.name, msg: part})
+		}
+	}
+
+	return datalen, err
+}
+
+// fakeContains returns whether the message is one of those in the slice.
+func fakeContains(msgs []*fakeMessage, msg *fakeMessage) bool {
+
+	for _, candidate := range msgs {
+		if candidate == msg {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fakeLogicallyBefore returns whether message a comes before message b in the
+// logical order of a group, by sequence number and then by segment offset.
+func fakeLogicallyBefore(a *fakeMessage, b *fakeMessage) bool {
+
+	if a.mqmd.MsgSeqNumber != b.mqmd.MsgSeqNumber {
+		return a.mqmd.MsgSeqNumber < b.mqmd.MsgSeqNumber
+	}
+
+	return a.mqmd.Offset < b.mqmd.Offset
+}
+
+// fakeSegments returns the segments on the queue of the message that the
+// segment belongs to, in order of their offsets, and whether every one of them
+// is on the queue.
+func fakeSegments(queue []*fakeMessage, segment *fakeMessage) ([]*fakeMessage, bool) {
+
+	var segments []*fakeMessage
+
+	for _, msg := range queue {
+		if msg.mqmd.MsgFlags&ibmmq.MQMF_SEGMENT != 0 &&
+			bytes.Equal(msg.mqmd.GroupId, segment.mqmd.GroupId) &&
+			msg.mqmd.MsgSeqNumber == segment.mqmd.MsgSeqNumber {
+			segments = append(segments, msg)
+		}
+	}
+
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].mqmd.Offset < segments[j].mqmd.Offset
+	})
+
+	offset := int32(0)
+	for _, msg := range segments {
+		if msg.mqmd.Offset != offset {
+			return segments, false
+		}
+		offset += int32(len(msg.body))
+	}
+
+	complete := len(segments) > 0 && segments[len(segments)-1].mqmd.MsgFlags&ibmmq.MQMF_LAST_SEGMENT != 0
+	return segments, complete
+}
+
+// fakeMatches returns whether an ID on a message matches the ID that was asked
+// for, where an ID of all zeros matches any message. Both IDs are taken as the
+// 24 bytes held by MQ, padded with zeros.
+func fakeMatches(wanted []byte, actual []byte) bool {
+
+	wantedID := make([]byte, 24)
+	copy(wantedID, wanted)
+
+	if bytes.Equal(wantedID, make([]byte, 24)) {
+		return true
+	}
+
+	actualID := make([]byte, 24)
+	copy(actualID, actual)
+
+	return bytes.Equal(wantedID, actualID)
+}
+
+// fakeGroupComplete returns whether every message of the group is on the queue,
+// where a segmented message is counted by its last segment.
+func fakeGroupComplete(queue []*fakeMessage, groupID []byte) bool {
+
+	count := int32(0)
+	last := int32(-1)
+
+	for _, msg := range queue {
+		if bytes.Equal(msg.mqmd.GroupId, groupID) {
+			if msg.mqmd.MsgFlags&ibmmq.MQMF_SEGMENT == 0 || msg.mqmd.MsgFlags&ibmmq.MQMF_LAST_SEGMENT != 0 {
+				count++
+			}
+			if msg.mqmd.MsgFlags&ibmmq.MQMF_LAST_MSG_IN_GROUP != 0 {
+				last = msg.mqmd.MsgSeqNumber
+			}
+		}
+	}
+
+	return count == last
+}
+
+func (object *fakeObject) Put(mqmd *ibmmq.MQMD, pmo *ibmmq.MQPMO, msgHandle mqiMessageHandle, buffer []byte) error {
+
+	object.conn.qm.lock.Lock()
+	defer object.conn.qm.lock.Unlock()
+
+	if err := object.conn.checkConnection("MQPUT"); err != nil {
+		return err
+	}
+
+	// In logical order the queue manager assigns the group ID, sequence number
+	// and segment offset of each message, keeping track of the current group and
+	// segmented message on the handle. A segmented message that is not part of a
+	// group is given a group of its own.
+	inGroup := mqmd.MsgFlags&(ibmmq.MQMF_MSG_IN_GROUP|ibmmq.MQMF_LAST_MSG_IN_GROUP) != 0
+	segment := mqmd.MsgFlags&ibmmq.MQMF_SEGMENT != 0
+
+	if pmo.Options&ibmmq.MQPMO_LOGICAL_ORDER != 0 && (inGroup || segment) {
+
+		if object.putGroupID == nil {
+			object.conn.qm.nextSeq++
+			object.putGroupID = fakeMessageID(object.conn.qm.name, object.conn.qm.nextSeq)
+			object.putSeq = 0
+		}
+
+		if !object.putSegment {
+			object.putSeq++
+			object.putOffset = 0
+		}
+
+		mqmd.GroupId = append([]byte{}, object.putGroupID...)
+		mqmd.MsgSeqNumber = object.putSeq
+		mqmd.Offset = object.putOffset
+
+		object.putOffset += int32(len(buffer))
+		object.putSegment = segment && mqmd.MsgFlags&ibmmq.MQMF_LAST_SEGMENT == 0
+
+		if !object.putSegment && (!inGroup || mqmd.MsgFlags&ibmmq.MQMF_LAST_MSG_IN_GROUP != 0) {
+			object.putGroupID = nil
+		}
+	}
+
+	return object.conn.put(object.name, mqmd, pmo, msgHandle, buffer)
+}
+
+func (object *fakeObject) Close(closeOptions int32) error {
+
+	object.conn.qm.lock.Lock()
+	defer object.conn.qm.lock.Unlock()
+
+	if err := object.conn.checkConnection("MQCLOSE"); err != nil {
+		return err
+	}
+
+	if object.putGroupID != nil {
+		return fakeMQReturn(ibmmq.MQCC_WARNING, ibmmq.MQRC_INCOMPLETE_GROUP)
+	}
+
+	return nil
+}
+
+func (handle *fakeMessageHandle) SetMP(smpo *ibmmq.MQSMPO, name string, pd *ibmmq.MQPD, value interface{}) error {
+
+	handle.conn.qm.lock.Lock()
+	defer handle.conn.qm.lock.Unlock()
+
+	if err := handle.check("MQSETMP"); err != nil {
+		return err
+	}
+
+	// MQ holds integers as 64 bit values.
+	if intValue, ok := value.(int); ok {
+		value = int64(intValue)
+	}
+
+	for i := range handle.properties {
+		if handle.properties[i].name == name {
+			handle.properties[i].value = value
+			return nil
+		}
+	}
+
+	handle.properties = append(handle.properties, fakeProperty{name: name, value: value})
+	return nil
+}
+
+func (handle *fakeMessageHandle) InqMP(impo *ibmmq.MQIMPO, pd *ibmmq.MQPD, name string) (string, interface{}, error) {
+
+	handle.conn.qm.lock.Lock()
+	defer handle.conn.qm.lock.Unlock()
+
+	if err := handle.check("MQINQMP"); err != nil {
+		return "", nil, err
+	}
+
+	notAvailable := fakeMQReturn(ibmmq.MQCC_FAILED, ibmmq.MQRC_PROPERTY_NOT_AVAILABLE)
+
+	// The wildcard name is used to iterate over all of the properties.
+	if name == "%" {
+
+		if impo.Options&ibmmq.MQIMPO_INQ_NEXT != 0 {
+			handle.cursor++
+		} else {
+			handle.cursor = 0
+		}
+
+		if handle.cursor >= len(handle.properties) {
+			return "", nil, notAvailable
+		}
+
+		property := handle.properties[handle.cursor]
+		return property.name, property.value, nil
+	}
+
+	for _, property := range handle.properties {
+		if property.name == name {
+			return property.name, property.value, nil
+		}
+	}
+
+	return "", nil, notAvailable
+}
+
+func (handle *fakeMessageHandle) DltMP(dmpo *ibmmq.MQDMPO, name string) error {
+
+	handle.conn.qm.lock.Lock()
+	defer handle.conn.qm.lock.Unlock()
+
+	if err := handle.check("MQDLTMP"); err != nil {
+		return err
+	}
+
+	for i := range handle.properties {
+		if handle.properties[i].name == name {
+			handle.properties = append(handle.properties[:i:i], handle.properties[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (handle *fakeMessageHandle) DltMH(dmho *ibmmq.MQDMHO) error {
+
+	handle.conn.qm.lock.Lock()
+	defer handle.conn.qm.lock.Unlock()
+
+	if err := handle.check("MQDLTMH"); err != nil {
+		return err
+	}
+
+	handle.deleted = true
+	handle.conn.qm.liveHandles--
+	handle.conn.qm.deletedHandles++
+	return nil
+}
+
+// check returns an error if the handle cannot be used. The lock must be held.
+func (handle *fakeMessageHandle) check(verb string) error {
+
+	if handle.deleted {
+		handle.conn.qm.calls[verb]++
+		return fakeMQReturn(ibmmq.MQCC_FAILED, ibmmq.MQRC_HMSG_ERROR)
+	}
+
+	return handle.conn.checkConnection(verb)
+}
@@ -0,0 +1,184 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+package mqjms
+
+import (
+	"testing"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// sendGroup sends a message for each of the bodies using the GroupProducer,
+// with the last one completing the group if last is true.
+func sendGroup(t *testing.T, jmsContext jms20subset.JMSContext, groupProducer *GroupProducer, last bool, bodies ...string) {
+
+	for i, body := range bodies {
+
+		msg := jmsContext.CreateTextMessageWithString(body)
+
+		if last && i == len(bodies)-1 {
+			assert.Nil(t, groupProducer.SendLast(msg))
+		} else {
+			assert.Nil(t, groupProducer.Send(msg))
+		}
+	}
+}
+
+// assertGroup checks that the group contains messages with the bodies in order,
+// and that their group properties are those of a group with the ID.
+func assertGroup(t *testing.T, group []jms20subset.Message, groupID string, bodies ...string) {
+
+	if !assert.Equal(t, len(bodies), len(group)) {
+		return
+	}
+
+	for i, msg := range group {
+
+		assert.Equal(t, bodies[i], *msg.(jms20subset.TextMessage).GetText())
+
+		gotGroupID, err := msg.GetStringProperty("JMSXGroupID")
+		assert.Nil(t, err)
+		if assert.NotNil(t, gotGroupID) {
+			assert.Equal(t, groupID, *gotGroupID)
+		}
+
+		gotSeq, err := msg.GetIntProperty("JMSXGroupSeq")
+		assert.Nil(t, err)
+		assert.Equal(t, i+1, gotSeq)
+
+		gotLast, err := msg.GetBooleanProperty("JMS_IBM_Last_Msg_In_Group")
+		assert.Nil(t, err)
+		assert.Equal(t, i == len(bodies)-1, gotLast)
+	}
+}
+
+/*
+ * Test that groups are only received once they are complete, and that their
+ * messages are received in order.
+ */
+func TestGroupProducerReceiveGroup(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	jmsContext := createFakeContext(t, qm, ConnectionFactoryImpl{}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := jmsContext.CreateQueue(fakeQueueName)
+
+	consumer, conErr := jmsContext.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	groupConsumer := consumer.(ConsumerImpl)
+
+	producerA, prodErr := NewGroupProducer(jmsContext, queue)
+	if !assert.Nil(t, prodErr) {
+		return
+	}
+	defer producerA.Close()
+	assert.Equal(t, "", producerA.GetGroupID())
+
+	producerB, prodErr := NewGroupProducer(jmsContext, queue)
+	if !assert.Nil(t, prodErr) {
+		return
+	}
+	defer producerB.Close()
+
+	// The first group is not complete, so the second one is received first.
+	sendGroup(t, jmsContext, producerA, false, "A1", "A2")
+	sendGroup(t, jmsContext, producerB, true, "B1", "B2")
+	assert.NotEqual(t, producerA.GetGroupID(), producerB.GetGroupID())
+
+	group, rcvErr := groupConsumer.ReceiveGroup(100)
+	assert.Nil(t, rcvErr)
+	assertGroup(t, group, producerB.GetGroupID(), "B1", "B2")
+
+	group, rcvErr = groupConsumer.ReceiveGroup(100)
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, group)
+	assert.Equal(t, 2, qm.depth(fakeQueueName))
+
+	sendGroup(t, jmsContext, producerA, true, "A3")
+
+	group, rcvErr = groupConsumer.ReceiveGroup(100)
+	assert.Nil(t, rcvErr)
+	assertGroup(t, group, producerA.GetGroupID(), "A1", "A2", "A3")
+
+	// A message that is not in a group is received as a group of one.
+	assert.Nil(t, jmsContext.CreateProducer().SendString(queue, "single"))
+
+	group, rcvErr = groupConsumer.ReceiveGroup(100)
+	assert.Nil(t, rcvErr)
+	if assert.Equal(t, 1, len(group)) {
+		assert.Equal(t, "single", *group[0].(jms20subset.TextMessage).GetText())
+	}
+
+	// A new group is started once the previous one is complete.
+	previousGroupID := producerA.GetGroupID()
+	sendGroup(t, jmsContext, producerA, true, "C1")
+	assert.NotEqual(t, previousGroupID, producerA.GetGroupID())
+
+	group, rcvErr = groupConsumer.ReceiveGroup(100)
+	assert.Nil(t, rcvErr)
+	assertGroup(t, group, producerA.GetGroupID(), "C1")
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+}
+
+/*
+ * Test that a group that cannot be received as a whole is left on the queue.
+ */
+func TestGroupProducerReceiveGroupFailure(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	jmsContext := createFakeContext(t, qm, ConnectionFactoryImpl{}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := jmsContext.CreateQueue(fakeQueueName)
+
+	consumer, conErr := jmsContext.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	groupConsumer := consumer.(ConsumerImpl)
+
+	groupProducer, prodErr := NewGroupProducer(jmsContext, queue)
+	if !assert.Nil(t, prodErr) {
+		return
+	}
+
+	sendGroup(t, jmsContext, groupProducer, true, "1", "2", "3")
+	assert.Equal(t, 3, qm.depth(fakeQueueName))
+
+	// The group is received in its own unit of work, which is backed out if it
+	// cannot be committed.
+	qm.fail("MQCMIT", ibmmq.MQRC_BACKED_OUT)
+	group, rcvErr := groupConsumer.ReceiveGroup(100)
+	assert.Nil(t, group)
+	if assert.NotNil(t, rcvErr) {
+		assert.Equal(t, "MQRC_BACKED_OUT", rcvErr.GetReason())
+	}
+	assert.Equal(t, 3, qm.depth(fakeQueueName))
+
+	group, rcvErr = groupConsumer.ReceiveGroup(100)
+	assert.Nil(t, rcvErr)
+	assertGroup(t, group, groupProducer.GetGroupID(), "1", "2", "3")
+
+	// A group whose last message is never sent is never received.
+	sendGroup(t, jmsContext, groupProducer, false, "incomplete")
+	groupProducer.Close()
+	groupProducer.Close() // Has no further effect
+
+	group, rcvErr = groupConsumer.ReceiveGroup(100)
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, group)
+	assert.Equal(t, 1, qm.depth(fakeQueueName))
+
+	sendErr := groupProducer.SendLast(jmsContext.CreateTextMessage())
+	if assert.NotNil(t, sendErr) {
+		assert.Equal(t, GroupProducer_CLOSED_REASON, sendErr.GetReason())
+	}
+
+}
@@ -0,0 +1,624 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+package mqjms
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+const fakeQueueName = "FAKE.QUEUE"
+
+// createFakeContext creates a JMSContext that is connected to the fake queue
+// manager, failing the test if that is not possible.
+func createFakeContext(t *testing.T, qm *fakeQueueManager, cf ConnectionFactoryImpl, sessionMode int) jms20subset.JMSContext {
+
+	useFakeMQI(t, qm)
+
+	cf.QMName = qm.name
+	cf.TransportType = TransportType_BINDINGS
+
+	context, ctxErr := cf.CreateContextWithSessionMode(sessionMode)
+	if ctxErr != nil {
+		t.Fatalf("Unable to create a context: %v", ctxErr)
+	}
+
+	t.Cleanup(context.Close)
+	return context
+}
+
+/*
+ * Test that messages and their properties are sent and received through the
+ * MQI interface.
+ */
+func TestFakeMQISendReceive(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	context := createFakeContext(t, qm, ConnectionFactoryImpl{}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := context.CreateQueue(fakeQueueName)
+
+	msg := context.CreateTextMessageWithString("with properties")
+	strValue := "value"
+	assert.Nil(t, msg.SetStringProperty("strProp", &strValue))
+	assert.Nil(t, msg.SetIntProperty("intProp", 42))
+	assert.Nil(t, msg.SetBooleanProperty("boolProp", true))
+	assert.Nil(t, msg.SetDoubleProperty("doubleProp", 1.5))
+	assert.Nil(t, msg.SetStringProperty("removedProp", &strValue))
+	assert.Nil(t, msg.SetStringProperty("removedProp", nil))
+
+	assert.Nil(t, context.CreateProducer().Send(queue, msg))
+	assert.Equal(t, 1, qm.depth(fakeQueueName))
+	assert.Equal(t, 1, qm.callCount("MQPUT1"))
+
+	consumer, conErr := context.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer consumer.Close()
+
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	if !assert.NotNil(t, rcvMsg) {
+		return
+	}
+
+	assert.Equal(t, msg.GetJMSMessageID(), rcvMsg.GetJMSMessageID())
+	assert.Equal(t, "with properties", *rcvMsg.(jms20subset.TextMessage).GetText())
+
+	names, namesErr := rcvMsg.GetPropertyNames()
+	assert.Nil(t, namesErr)
+	assert.ElementsMatch(t, []string{"strProp", "intProp", "boolProp", "doubleProp"}, names)
+
+	gotStr, _ := rcvMsg.GetStringProperty("strProp")
+	assert.Equal(t, "value", *gotStr)
+	gotInt, _ := rcvMsg.GetIntProperty("intProp")
+	assert.Equal(t, 42, gotInt)
+	gotBool, _ := rcvMsg.GetBooleanProperty("boolProp")
+	assert.True(t, gotBool)
+	gotDouble, _ := rcvMsg.GetDoubleProperty("doubleProp")
+	assert.Equal(t, 1.5, gotDouble)
+
+	exists, _ := rcvMsg.PropertyExists("removedProp")
+	assert.False(t, exists)
+
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+}
+
+/*
+ * Test that failures of asynchronous puts are gathered by MQSTAT and reported
+ * at the interval set by SendCheckCount.
+ */
+func TestFakeMQIAsyncPutErrors(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	cf := ConnectionFactoryImpl{SendCheckCount: 2}
+	context := createFakeContext(t, qm, cf, jms20subset.JMSContextAUTOACKNOWLEDGE)
+
+	queue := context.CreateQueue(fakeQueueName).
+		SetPutAsyncAllowed(jms20subset.Destination_PUT_ASYNC_ALLOWED_ENABLED)
+	producer := context.CreateProducer()
+
+	// The first message is checked straight away.
+	qm.setAsyncPutResult(ibmmq.MQCC_FAILED)
+	errSend := producer.SendString(queue, "failed")
+	if assert.NotNil(t, errSend) {
+		assert.Equal(t, "AsyncPutFailure", errSend.GetErrorCode())
+		assert.Equal(t, "1 failures and 0 warnings for asynchronous message put", errSend.GetReason())
+
+		linkedErr, ok := errSend.GetLinkedError().(jms20subset.JMSException)
+		if assert.True(t, ok) {
+			assert.Equal(t, "MQRC_Q_FULL", linkedErr.GetReason())
+			assert.Equal(t, "2", linkedErr.GetErrorCode())
+		}
+	}
+	assert.Equal(t, 1, qm.callCount("MQSTAT"))
+
+	// After that the check is made once for every two messages, and includes
+	// every message since the previous check.
+	assert.Nil(t, producer.SendString(queue, "unchecked"))
+	assert.Equal(t, 1, qm.callCount("MQSTAT"))
+
+	qm.setAsyncPutResult(ibmmq.MQCC_WARNING)
+	errSend = producer.SendString(queue, "warning")
+	if assert.NotNil(t, errSend) {
+		assert.Equal(t, "0 failures and 1 warnings for asynchronous message put", errSend.GetReason())
+	}
+	assert.Equal(t, 2, qm.callCount("MQSTAT"))
+
+	assert.Nil(t, producer.SendString(queue, "unchecked"))
+	assert.Nil(t, producer.SendString(queue, "succeeded"))
+	assert.Equal(t, 3, qm.callCount("MQSTAT"))
+
+	// The message that failed was not delivered.
+	assert.Equal(t, 4, qm.depth(fakeQueueName))
+
+	// A failure of the MQSTAT call itself is reported in place of the check.
+	assert.Nil(t, producer.SendString(queue, "unchecked"))
+	qm.fail("MQSTAT", ibmmq.MQRC_HCONN_ERROR)
+	errSend = producer.SendString(queue, "stat failed")
+	if assert.NotNil(t, errSend) {
+		assert.Equal(t, "2018", errSend.GetErrorCode())
+		assert.ErrorIs(t, errSend, jms20subset.IllegalStateException{})
+	}
+
+}
+
+/*
+ * Test that a commit that fails after a transacted asynchronous put reports
+ * the failures found by MQSTAT as the linked error.
+ */
+func TestFakeMQICommitAsyncPut(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	context := createFakeContext(t, qm, ConnectionFactoryImpl{}, jms20subset.JMSContextSESSIONTRANSACTED)
+
+	queue := context.CreateQueue(fakeQueueName).
+		SetPutAsyncAllowed(jms20subset.Destination_PUT_ASYNC_ALLOWED_ENABLED)
+	producer := context.CreateProducer().SetDeliveryMode(jms20subset.DeliveryMode_PERSISTENT)
+
+	// A commit that succeeds does not need to check the asynchronous puts.
+	assert.Nil(t, producer.SendString(queue, "committed"))
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+	assert.Nil(t, context.Commit())
+	assert.Equal(t, 1, qm.depth(fakeQueueName))
+	assert.Equal(t, 0, qm.callCount("MQSTAT"))
+
+	qm.setAsyncPutResult(ibmmq.MQCC_FAILED)
+	assert.Nil(t, producer.SendString(queue, "failed"))
+	assert.Nil(t, producer.SendString(queue, "backed out"))
+	qm.fail("MQCMIT", ibmmq.MQRC_BACKED_OUT)
+
+	errCommit := context.Commit()
+	if assert.NotNil(t, errCommit) {
+		assert.Equal(t, "MQRC_BACKED_OUT", errCommit.GetReason())
+		assert.ErrorIs(t, errCommit, jms20subset.TransactionRolledBackException{})
+
+		linkedErr, ok := errCommit.GetLinkedError().(jms20subset.JMSException)
+		if assert.True(t, ok) {
+			assert.Equal(t, "AsyncPutFailure", linkedErr.GetErrorCode())
+			assert.Equal(t, "1 failures and 0 warnings for asynchronous message put", linkedErr.GetReason())
+		}
+	}
+	assert.Equal(t, 1, qm.callCount("MQSTAT"))
+	assert.Equal(t, 1, qm.depth(fakeQueueName))
+
+	// If MQSTAT fails then its failure is reported, linked to the failed commit.
+	assert.Nil(t, producer.SendString(queue, "backed out"))
+	qm.fail("MQCMIT", ibmmq.MQRC_BACKED_OUT)
+	qm.fail("MQSTAT", ibmmq.MQRC_HCONN_ERROR)
+
+	errCommit = context.Commit()
+	if assert.NotNil(t, errCommit) {
+		assert.Equal(t, "MQRC_HCONN_ERROR", errCommit.GetReason())
+		assert.ErrorIs(t, errCommit, jms20subset.IllegalStateException{})
+
+		var mqret *ibmmq.MQReturn
+		if assert.True(t, errors.As(errCommit, &mqret)) {
+			assert.Equal(t, ibmmq.MQRC_BACKED_OUT, mqret.MQRC)
+		}
+	}
+	assert.Equal(t, 1, qm.depth(fakeQueueName))
+
+}
+
+/*
+ * Test that a message that is too large for the buffer supplied to ReceiveInto
+ * is left on the queue.
+ */
+func TestFakeMQIReceiveIntoTruncated(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	context := createFakeContext(t, qm, ConnectionFactoryImpl{}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := context.CreateQueue(fakeQueueName)
+
+	body := strings.Repeat("x", 100)
+	assert.Nil(t, context.CreateProducer().SendString(queue, body))
+
+	consumer, conErr := context.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer consumer.Close()
+
+	rcvMsg, rcvErr := consumer.ReceiveInto(make([]byte, 10), 100)
+	assert.Nil(t, rcvMsg)
+	if assert.NotNil(t, rcvErr) {
+		assert.Equal(t, "2080", rcvErr.GetErrorCode())
+		assert.Equal(t, "MQRC_TRUNCATED_MSG_FAILED", rcvErr.GetReason())
+	}
+	assert.Equal(t, 1, qm.depth(fakeQueueName))
+
+	// The handles of the message that was sent and of the failed receive have
+	// both been deleted, as there is no pool to return them to.
+	live, deleted := qm.handleCounts()
+	assert.Equal(t, 0, live)
+	assert.Equal(t, 2, deleted)
+
+	rcvMsg, rcvErr = consumer.ReceiveInto(make([]byte, 200), 100)
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, rcvMsg) {
+		assert.Equal(t, body, *rcvMsg.(jms20subset.TextMessage).GetText())
+	}
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+}
+
+/*
+ * Test that the reason codes returned by the MQI are reported as the matching
+ * type of JMSException.
+ */
+func TestFakeMQIReasonCodes(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	useFakeMQI(t, qm)
+
+	qm.fail("MQCONNX", ibmmq.MQRC_NOT_AUTHORIZED)
+	cf := ConnectionFactoryImpl{QMName: qm.name, TransportType: TransportType_BINDINGS}
+	_, ctxErr := cf.CreateContext()
+	if assert.NotNil(t, ctxErr) {
+		assert.Equal(t, "2035", ctxErr.GetErrorCode())
+		assert.ErrorIs(t, ctxErr, jms20subset.SecurityException{})
+	}
+
+	context := createFakeContext(t, qm, ConnectionFactoryImpl{}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := context.CreateQueue(fakeQueueName)
+
+	qm.fail("MQOPEN", ibmmq.MQRC_UNKNOWN_OBJECT_NAME)
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(t, consumer)
+	if assert.NotNil(t, conErr) {
+		assert.Equal(t, "MQRC_UNKNOWN_OBJECT_NAME", conErr.GetReason())
+		assert.ErrorIs(t, conErr, jms20subset.InvalidDestinationException{})
+	}
+
+	qm.fail("MQPUT1", ibmmq.MQRC_Q_FULL)
+	errSend := context.CreateProducer().SendString(queue, "queue full")
+	if assert.NotNil(t, errSend) {
+		assert.Equal(t, "2053", errSend.GetErrorCode())
+		assert.True(t, jms20subset.IsRetryable(errSend))
+	}
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+	consumer, conErr = context.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer consumer.Close()
+
+	qm.fail("MQGET", ibmmq.MQRC_HOBJ_ERROR)
+	_, rcvErr := consumer.ReceiveNoWait()
+	if assert.NotNil(t, rcvErr) {
+		assert.ErrorIs(t, rcvErr, jms20subset.IllegalStateException{})
+
+		var mqret *ibmmq.MQReturn
+		if assert.True(t, errors.As(rcvErr, &mqret)) {
+			assert.Equal(t, ibmmq.MQRC_HOBJ_ERROR, mqret.MQRC)
+		}
+	}
+
+	// No message available is not an error.
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+}
+
+/*
+ * Test that message handles are reused up to the size of the pool, and deleted
+ * when the pool is full or the context is closed.
+ */
+func TestFakeMQIHandlePool(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	cf := ConnectionFactoryImpl{MessageHandlePoolSize: 2}
+	context := createFakeContext(t, qm, cf, jms20subset.JMSContextAUTOACKNOWLEDGE)
+
+	msgs := make([]jms20subset.Message, 0, 3)
+	for i := 0; i < 3; i++ {
+		msg := context.CreateTextMessage()
+		assert.Nil(t, msg.SetIntProperty("index", i))
+		msgs = append(msgs, msg)
+	}
+	assert.Equal(t, 3, qm.callCount("MQCRTMH"))
+
+	for _, msg := range msgs {
+		msg.Release()
+	}
+
+	live, deleted := qm.handleCounts()
+	assert.Equal(t, 2, live)
+	assert.Equal(t, 1, deleted)
+
+	// The new messages reuse the handles, without the properties of the
+	// messages that held them before.
+	reused1 := context.CreateTextMessage()
+	reused2 := context.CreateTextMessage()
+	assert.Equal(t, 3, qm.callCount("MQCRTMH"))
+
+	names, namesErr := reused1.GetPropertyNames()
+	assert.Nil(t, namesErr)
+	assert.Empty(t, names)
+
+	reused1.Release()
+	reused2.Release()
+
+	context.Close()
+	live, deleted = qm.handleCounts()
+	assert.Equal(t, 0, live)
+	assert.Equal(t, 3, deleted)
+
+}
+
+/*
+ * Test that the handle of a message with more properties than are worth clearing
+ * is deleted rather than returned to the pool.
+ */
+func TestFakeMQIHandlePoolManyProperties(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	cf := ConnectionFactoryImpl{MessageHandlePoolSize: 2}
+	context := createFakeContext(t, qm, cf, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	defer context.Close()
+
+	few := context.CreateTextMessage()
+	for i := 0; i < maxClearedProperties; i++ {
+		assert.Nil(t, few.SetIntProperty("few"+strconv.Itoa(i), i))
+	}
+
+	many := context.CreateTextMessage()
+	for i := 0; i <= maxClearedProperties; i++ {
+		assert.Nil(t, many.SetIntProperty("many"+strconv.Itoa(i), i))
+	}
+
+	few.Release()
+	many.Release()
+
+	live, deleted := qm.handleCounts()
+	assert.Equal(t, 1, live)
+	assert.Equal(t, 1, deleted)
+	assert.Equal(t, 2*maxClearedProperties, qm.callCount("MQDLTMP"))
+
+	// The cleared handle is reused, and a new handle is created after that.
+	reused := context.CreateTextMessage()
+	names, namesErr := reused.GetPropertyNames()
+	assert.Nil(t, namesErr)
+	assert.Empty(t, names)
+	assert.Equal(t, 2, qm.callCount("MQCRTMH"))
+
+	context.CreateTextMessage()
+	assert.Equal(t, 3, qm.callCount("MQCRTMH"))
+
+}
+
+/*
+ * Test that the handle of a message that is discarded without calling Release
+ * is deleted by its finalizer.
+ */
+func TestFakeMQIHandleFinalizer(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	context := createFakeContext(t, qm, ConnectionFactoryImpl{}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+
+	func() {
+		msg := context.CreateTextMessageWithString("discarded")
+		assert.Nil(t, msg.SetIntProperty("prop", 1))
+	}()
+
+	live, _ := qm.handleCounts()
+	assert.Equal(t, 1, live)
+
+	// Finalizers run some time after the garbage collection that finds the
+	// object to be unreachable, so keep collecting until the handle is deleted.
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+
+		runtime.GC()
+		if _, deleted := qm.handleCounts(); deleted == 1 {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	live, deleted := qm.handleCounts()
+	assert.Equal(t, 0, live)
+	assert.Equal(t, 1, deleted)
+
+}
+
+/*
+ * Test that selectors on JMSCorrelationID and JMSMessageID are applied by
+ * matching the fields of the MQMD, rather than browsing the messages.
+ */
+func TestFakeMQISelectors(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	context := createFakeContext(t, qm, ConnectionFactoryImpl{}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := context.CreateQueue(fakeQueueName)
+	producer := context.CreateProducer()
+
+	msg1 := context.CreateTextMessageWithString("first")
+	assert.Nil(t, msg1.SetJMSCorrelationID("myCorrel"))
+	msg2 := context.CreateTextMessageWithString("second")
+	msg3 := context.CreateTextMessageWithString("third")
+	assert.Nil(t, msg3.SetJMSCorrelationID("myCorrel"))
+
+	assert.Nil(t, producer.Send(queue, msg1))
+	assert.Nil(t, producer.Send(queue, msg2))
+	assert.Nil(t, producer.Send(queue, msg3))
+
+	msgIDConsumer, conErr := context.CreateConsumerWithSelector(queue, "JMSMessageID = 'ID:"+msg2.GetJMSMessageID()+"'")
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer msgIDConsumer.Close()
+
+	rcvMsg, rcvErr := msgIDConsumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, rcvMsg) {
+		assert.Equal(t, "second", *rcvMsg.(jms20subset.TextMessage).GetText())
+	}
+
+	rcvMsg, rcvErr = msgIDConsumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+	correlConsumer, conErr := context.CreateConsumerWithSelector(queue, "JMSCorrelationID = 'myCorrel'")
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer correlConsumer.Close()
+
+	for _, expected := range []string{"first", "third"} {
+		rcvMsg, rcvErr = correlConsumer.ReceiveNoWait()
+		assert.Nil(t, rcvErr)
+		if assert.NotNil(t, rcvMsg) {
+			assert.Equal(t, expected, *rcvMsg.(jms20subset.TextMessage).GetText())
+			assert.Equal(t, msg1.GetJMSCorrelationID(), rcvMsg.GetJMSCorrelationID())
+		}
+	}
+
+	rcvMsg, rcvErr = correlConsumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, rcvMsg)
+
+	// Each receive is a single MQGET, without browsing the queue.
+	assert.Equal(t, 5, qm.callCount("MQGET"))
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+}
+
+/*
+ * Test that a JMSContext created using CreateContext shares the connection
+ * handle and unit of work of the original context, and that the connection
+ * handle is disconnected when the last of them is closed.
+ */
+func TestFakeMQICreateContext(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	context := createFakeContext(t, qm, ConnectionFactoryImpl{}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+
+	txContext, ctxErr := context.CreateContext(jms20subset.JMSContextSESSIONTRANSACTED)
+	if !assert.Nil(t, ctxErr) {
+		return
+	}
+	defer txContext.Close()
+	assert.Equal(t, 1, qm.callCount("MQCONNX"))
+
+	queue := context.CreateQueue(fakeQueueName)
+	consumer, conErr := context.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+
+	// Commit has no effect on the context that is not transacted.
+	assert.Nil(t, txContext.CreateProducer().SendString(queue, "committed"))
+	assert.Nil(t, context.Commit())
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+	assert.Nil(t, txContext.Commit())
+	assert.Equal(t, 1, qm.depth(fakeQueueName))
+
+	// A group cannot be received in a unit of work of its own while the
+	// transacted context has work that is not committed.
+	assert.Nil(t, txContext.CreateProducer().SendString(queue, "rolled back"))
+	_, streamErr := consumer.ReceiveStream(0)
+	if assert.NotNil(t, streamErr) {
+		assert.Equal(t, ContextImpl_UNIT_OF_WORK_OPEN_REASON, streamErr.GetReason())
+	}
+	assert.Nil(t, txContext.Rollback())
+
+	// The transacted context cannot use the unit of work while it is being used
+	// to receive a group.
+	reader, streamErr := consumer.ReceiveStream(0)
+	if !assert.Nil(t, streamErr) || !assert.NotNil(t, reader) {
+		return
+	}
+
+	sendErr := txContext.CreateProducer().SendString(queue, "refused")
+	if assert.NotNil(t, sendErr) {
+		assert.Equal(t, ContextImpl_UNIT_OF_WORK_OPEN_REASON, sendErr.GetReason())
+	}
+	commitErr := txContext.Commit()
+	if assert.NotNil(t, commitErr) {
+		assert.Equal(t, ContextImpl_UNIT_OF_WORK_OPEN_REASON, commitErr.GetReason())
+	}
+
+	data, readErr := io.ReadAll(reader)
+	assert.Nil(t, readErr)
+	assert.Equal(t, "committed", string(data))
+	assert.Nil(t, reader.Close())
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+	// Closing the original context leaves the connection for the other one.
+	context.Close()
+	assert.Equal(t, 0, qm.callCount("MQDISC"))
+
+	assert.Nil(t, txContext.CreateProducer().SendString(queue, "after close"))
+	assert.Nil(t, txContext.Commit())
+	assert.Equal(t, 1, qm.depth(fakeQueueName))
+
+	txContext.Close()
+	assert.Equal(t, 1, qm.callCount("MQDISC"))
+
+	_, ctxErr = context.CreateContext(jms20subset.JMSContextAUTOACKNOWLEDGE)
+	assert.NotNil(t, ctxErr)
+
+}
+
+// latencyMetrics records the get latencies that are reported to it.
+type latencyMetrics struct {
+	noopMetrics
+	getLatencies chan time.Duration
+}
+
+func (metrics latencyMetrics) GetLatency(queueName string, d time.Duration) {
+	metrics.getLatencies <- d
+}
+
+/*
+ * Test that the get latency of a message that is received after a wait covers
+ * the whole of the wait, rather than only the last slice of it.
+ */
+func TestFakeMQIGetLatency(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	metrics := latencyMetrics{getLatencies: make(chan time.Duration, 1)}
+	cf := ConnectionFactoryImpl{ReceiveWaitSlice: 20, Metrics: metrics}
+	context := createFakeContext(t, qm, cf, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := context.CreateQueue(fakeQueueName)
+
+	consumer, conErr := context.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer consumer.Close()
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		context.CreateProducer().SendString(queue, "late")
+	}()
+
+	rcvBody, rcvErr := consumer.ReceiveStringBody(5000)
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, rcvBody) {
+		assert.Equal(t, "late", *rcvBody)
+	}
+
+	assert.GreaterOrEqual(t, <-metrics.getLatencies, 200*time.Millisecond)
+
+}
@@ -0,0 +1,85 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+package mqjms
+
+import (
+	"errors"
+	"testing"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// foreignOption is a connection option for a provider other than IBM MQ.
+type foreignOption struct{}
+
+func (o foreignOption) Apply(target interface{}) error {
+	return errors.New("not for this provider")
+}
+
+// newClientMQCNO returns an MQCNO for a client connection.
+func newClientMQCNO() *ibmmq.MQCNO {
+
+	cno := ibmmq.NewMQCNO()
+	cno.ClientConn = ibmmq.NewMQCD()
+	cno.ClientConn.MaxMsgLength = 1234
+	return cno
+
+}
+
+// Test that the options are applied in order, including the deprecated option
+// from the jms20subset package.
+func TestApplyMQOptions(t *testing.T) {
+
+	cno := newClientMQCNO()
+	var seen int32
+	optErr := applyMQOptions(cno, []jms20subset.ConnectionOption{
+		WithApplName("options-test"),
+		jms20subset.WithMaxMsgLength(2000),
+		MQCNOOption(func(cno *ibmmq.MQCNO) {
+			seen = cno.ClientConn.MaxMsgLength
+		}),
+	})
+	assert.Nil(t, optErr)
+	assert.Equal(t, "options-test", cno.ApplName)
+	assert.Equal(t, int32(2000), cno.ClientConn.MaxMsgLength)
+	assert.Equal(t, int32(2000), seen)
+
+}
+
+// Test that an option that was given a value that is not valid, or an option
+// for another provider, is reported without changing the MQCNO.
+func TestApplyMQOptionsRejected(t *testing.T) {
+
+	cno := newClientMQCNO()
+	optErr := applyMQOptions(cno, []jms20subset.ConnectionOption{WithMaxMsgLength(-1)})
+	if assert.NotNil(t, optErr) {
+		assert.Equal(t, "WithMaxMsgLength", optErr.Option)
+	}
+	assert.Equal(t, int32(1234), cno.ClientConn.MaxMsgLength)
+
+	optErr = applyMQOptions(cno, []jms20subset.ConnectionOption{jms20subset.WithMaxMsgLength(-1)})
+	if assert.NotNil(t, optErr) {
+		assert.Equal(t, "WithMaxMsgLength", optErr.Option)
+	}
+
+	optErr = applyMQOptions(cno, []jms20subset.ConnectionOption{WithMessageCompression(99)})
+	if assert.NotNil(t, optErr) {
+		assert.Equal(t, "WithMessageCompression", optErr.Option)
+	}
+
+	optErr = applyMQOptions(cno, []jms20subset.ConnectionOption{foreignOption{}})
+	assert.NotNil(t, optErr)
+
+	optErr = applyMQOptions(cno, []jms20subset.ConnectionOption{nil})
+	assert.NotNil(t, optErr)
+
+	assert.Equal(t, int32(1234), cno.ClientConn.MaxMsgLength)
+
+}
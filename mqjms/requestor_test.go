@@ -0,0 +1,242 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+package mqjms
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// startResponder replies to every request on the queue with a text message
+// that repeats the body of the request, until the Go context is cancelled.
+func startResponder(t *testing.T, ctx context.Context, parent jms20subset.JMSContext, queue jms20subset.Queue) *sync.WaitGroup {
+
+	respContext, ctxErr := parent.CreateContext(jms20subset.JMSContextAUTOACKNOWLEDGE)
+	if ctxErr != nil {
+		t.Fatalf("Unable to create a context: %v", ctxErr)
+	}
+
+	consumer, conErr := respContext.CreateConsumer(queue)
+	if conErr != nil {
+		t.Fatalf("Unable to create a consumer: %v", conErr)
+	}
+
+	responder := NewResponder(respContext)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer respContext.Close()
+
+		for {
+			request, rcvErr := consumer.ReceiveContext(ctx)
+			if rcvErr != nil {
+				return
+			}
+
+			body := *request.(jms20subset.TextMessage).GetText()
+			reply := respContext.CreateTextMessageWithString("reply to " + body)
+			assert.Nil(t, responder.Reply(request, reply))
+		}
+	}()
+
+	return &wg
+}
+
+/*
+ * Test that many concurrent requests each receive their own reply through a
+ * temporary reply queue.
+ */
+func TestRequestorConcurrentRequests(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	jmsContext := createFakeContext(t, qm, ConnectionFactoryImpl{ReceiveWaitSlice: 50}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	requestQueue := jmsContext.CreateQueue(fakeQueueName)
+
+	requestor, reqErr := NewRequestor(jmsContext, requestQueue, nil)
+	if !assert.Nil(t, reqErr) {
+		return
+	}
+
+	replyQueueName := requestor.GetReplyQueue().GetQueueName()
+	assert.True(t, strings.HasPrefix(replyQueueName, "AMQ."))
+	assert.Equal(t, jms20subset.DeliveryMode_NON_PERSISTENT, requestor.GetProducer().GetDeliveryMode())
+
+	stopCtx, stopResponder := context.WithCancel(context.Background())
+	responderDone := startResponder(t, stopCtx, jmsContext, requestQueue)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			body := "request " + strconv.Itoa(i)
+			request := jmsContext.CreateTextMessageWithString(body)
+
+			reply, replyErr := requestor.Request(request, 5000)
+			assert.Nil(t, replyErr)
+			if assert.NotNil(t, reply) {
+				assert.Equal(t, "reply to "+body, *reply.(jms20subset.TextMessage).GetText())
+				assert.Equal(t, request.GetJMSMessageID(), reply.GetJMSCorrelationID())
+				assert.Equal(t, jms20subset.DeliveryMode_NON_PERSISTENT, reply.GetJMSDeliveryMode())
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stopResponder()
+	responderDone.Wait()
+
+	requestor.Close()
+	requestor.Close() // Has no further effect
+
+	_, replyErr := requestor.Request(jmsContext.CreateTextMessageWithString("closed"), 100)
+	if assert.NotNil(t, replyErr) {
+		assert.Equal(t, Requestor_CLOSED_REASON, replyErr.GetReason())
+		assert.ErrorIs(t, replyErr, jms20subset.IllegalStateException{})
+	}
+
+}
+
+/*
+ * Test that a request that is not answered in time returns without a reply,
+ * and that a reply that arrives late is discarded.
+ */
+func TestRequestorTimeout(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	jmsContext := createFakeContext(t, qm, ConnectionFactoryImpl{ReceiveWaitSlice: 50}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	requestQueue := jmsContext.CreateQueue(fakeQueueName)
+
+	requestor, reqErr := NewRequestor(jmsContext, requestQueue, jmsContext.CreateQueue("FAKE.REPLY"))
+	if !assert.Nil(t, reqErr) {
+		return
+	}
+	defer requestor.Close()
+
+	request := jmsContext.CreateTextMessageWithString("unanswered")
+	start := time.Now()
+	reply, replyErr := requestor.Request(request, 200)
+	assert.Nil(t, replyErr)
+	assert.Nil(t, reply)
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+
+	// The request was sent with the reply queue set.
+	assert.Equal(t, 1, qm.depth(fakeQueueName))
+
+	// A reply that arrives after the request has timed out is discarded.
+	assert.Nil(t, NewResponder(jmsContext).Reply(request, jmsContext.CreateTextMessageWithString("late")))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for qm.depth("FAKE.REPLY") > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 0, qm.depth("FAKE.REPLY"))
+
+	// A request using a Go context ends with an error when the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	reply, replyErr = requestor.RequestContext(ctx, jmsContext.CreateTextMessageWithString("unanswered"))
+	assert.Nil(t, reply)
+	if assert.NotNil(t, replyErr) {
+		assert.Equal(t, ContextImpl_CONTEXT_DONE_REASON, replyErr.GetReason())
+		assert.ErrorIs(t, replyErr, context.DeadlineExceeded)
+	}
+
+}
+
+/*
+ * Test the errors that are returned when there is no reply queue.
+ */
+func TestRequestorNoReplyQueue(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	jmsContext := createFakeContext(t, qm, ConnectionFactoryImpl{}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	requestQueue := jmsContext.CreateQueue(fakeQueueName)
+
+	// The model queue for the temporary reply queue does not exist.
+	qm.fail("MQOPEN", ibmmq.MQRC_UNKNOWN_OBJECT_NAME)
+	requestor, reqErr := NewRequestor(jmsContext, requestQueue, nil)
+	assert.Nil(t, requestor)
+	if assert.NotNil(t, reqErr) {
+		assert.ErrorIs(t, reqErr, jms20subset.InvalidDestinationException{})
+	}
+
+	// A request that does not have a JMSReplyTo cannot be replied to.
+	request := jmsContext.CreateTextMessageWithString("no reply to")
+	replyErr := NewResponder(jmsContext).Reply(request, jmsContext.CreateTextMessage())
+	if assert.NotNil(t, replyErr) {
+		assert.Equal(t, Requestor_NO_REPLY_QUEUE_REASON, replyErr.GetReason())
+		assert.ErrorIs(t, replyErr, jms20subset.InvalidDestinationException{})
+	}
+
+}
+
+/*
+ * Test that the Requestor sends each request with a message ID that it has
+ * generated, and stops waiting for a request that could not be sent.
+ */
+func TestRequestorMessageID(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	jmsContext := createFakeContext(t, qm, ConnectionFactoryImpl{ReceiveWaitSlice: 50}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	requestQueue := jmsContext.CreateQueue(fakeQueueName)
+
+	requestor, reqErr := NewRequestor(jmsContext, requestQueue, jmsContext.CreateQueue("FAKE.REPLY"))
+	if !assert.Nil(t, reqErr) {
+		return
+	}
+	defer requestor.Close()
+
+	first := jmsContext.CreateTextMessageWithString("first")
+	second := jmsContext.CreateTextMessageWithString("second")
+	for _, request := range []jms20subset.Message{first, second} {
+		reply, replyErr := requestor.Request(request, 50)
+		assert.Nil(t, replyErr)
+		assert.Nil(t, reply)
+	}
+	assert.NotEqual(t, first.GetJMSMessageID(), second.GetJMSMessageID())
+
+	// The requests were put with the message IDs that the Requestor generated.
+	consumer, conErr := jmsContext.CreateConsumer(requestQueue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer consumer.Close()
+
+	for _, request := range []jms20subset.Message{first, second} {
+		received, rcvErr := consumer.ReceiveNoWait()
+		assert.Nil(t, rcvErr)
+		if assert.NotNil(t, received) {
+			assert.Equal(t, request.GetJMSMessageID(), received.GetJMSMessageID())
+		}
+	}
+
+	// A request that cannot be sent is no longer outstanding.
+	qm.fail("MQPUT1", ibmmq.MQRC_Q_FULL)
+	reply, replyErr := requestor.Request(jmsContext.CreateTextMessageWithString("full"), 50)
+	assert.Nil(t, reply)
+	assert.NotNil(t, replyErr)
+
+	requestor.lock.Lock()
+	assert.Empty(t, requestor.pending)
+	requestor.lock.Unlock()
+
+}
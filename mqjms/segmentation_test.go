@@ -0,0 +1,320 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+package mqjms
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// sendLargeMessage sends a text message with the body and a property, and
+// returns its message ID.
+func sendLargeMessage(t *testing.T, jmsContext jms20subset.JMSContext, queue jms20subset.Queue, body string) string {
+
+	msg := jmsContext.CreateTextMessageWithString(body)
+	document := "report.pdf"
+	assert.Nil(t, msg.SetStringProperty("document", &document))
+	assert.Nil(t, jmsContext.CreateProducer().Send(queue, msg))
+
+	return msg.GetJMSMessageID()
+}
+
+// assertLargeMessage checks that a message sent by sendLargeMessage has been
+// received as a whole.
+func assertLargeMessage(t *testing.T, rcvMsg jms20subset.Message, msgID string, body string) {
+
+	if !assert.NotNil(t, rcvMsg) {
+		return
+	}
+
+	assert.Equal(t, msgID, rcvMsg.GetJMSMessageID())
+	assert.Equal(t, body, *rcvMsg.(jms20subset.TextMessage).GetText())
+
+	gotProp, propErr := rcvMsg.GetStringProperty("document")
+	assert.Nil(t, propErr)
+	if assert.NotNil(t, gotProp) {
+		assert.Equal(t, "report.pdf", *gotProp)
+	}
+
+	// A segmented message is not part of a group.
+	gotGroupID, propErr := rcvMsg.GetStringProperty("JMSXGroupID")
+	assert.Nil(t, propErr)
+	assert.Nil(t, gotGroupID)
+}
+
+/*
+ * Test that a message that is too big for the queue is segmented by the queue
+ * manager if that has been configured, and reassembled by it when it is received.
+ */
+func TestSegmentationByQueueManager(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	qm.setMaxMsgLength(fakeQueueName, 1000)
+
+	body := strings.Repeat("0123456789", 350)
+
+	// Messages are not segmented unless that has been configured.
+	defaultContext := createFakeContext(t, qm, ConnectionFactoryImpl{}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	sendErr := defaultContext.CreateProducer().SendString(defaultContext.CreateQueue(fakeQueueName), body)
+	if assert.NotNil(t, sendErr) {
+		assert.Equal(t, "MQRC_MSG_TOO_BIG_FOR_Q", sendErr.GetReason())
+	}
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+	jmsContext := createFakeContext(t, qm, ConnectionFactoryImpl{SegmentSize: SegmentSize_QUEUE_MANAGER}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := jmsContext.CreateQueue(fakeQueueName)
+
+	msgID := sendLargeMessage(t, jmsContext, queue, body)
+	assert.Equal(t, 4, qm.depth(fakeQueueName))
+
+	consumer, conErr := jmsContext.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer consumer.Close()
+
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assertLargeMessage(t, rcvMsg, msgID, body)
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+}
+
+/*
+ * Test that a message that is larger than the SegmentSize is segmented by the
+ * library, and reassembled when it is received using a receive buffer that
+ * only holds a single segment.
+ */
+func TestSegmentationByClient(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	cf := ConnectionFactoryImpl{
+		SegmentSize:       1000,
+		ReceiveBufferSize: 1000,
+	}
+	jmsContext := createFakeContext(t, qm, cf, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := jmsContext.CreateQueue(fakeQueueName)
+
+	consumer, conErr := jmsContext.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer consumer.Close()
+
+	body := strings.Repeat("0123456789", 350)
+	msgID := sendLargeMessage(t, jmsContext, queue, body)
+	assert.Equal(t, 4, qm.depth(fakeQueueName))
+
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assertLargeMessage(t, rcvMsg, msgID, body)
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+	// A message that fits in a segment is sent as it is.
+	assert.Nil(t, jmsContext.CreateProducer().SendString(queue, "small"))
+	assert.Equal(t, 1, qm.depth(fakeQueueName))
+
+	rcvBody, rcvErr := consumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, rcvBody) {
+		assert.Equal(t, "small", *rcvBody)
+	}
+
+	// The segments are also reassembled by the queue manager for a consumer that
+	// does not segment messages itself.
+	otherContext := createFakeContext(t, qm, ConnectionFactoryImpl{}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	otherConsumer, conErr := otherContext.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer otherConsumer.Close()
+
+	msgID = sendLargeMessage(t, jmsContext, queue, body)
+
+	rcvMsg, rcvErr = otherConsumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	assertLargeMessage(t, rcvMsg, msgID, body)
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+	// The messages of a group are segmented individually.
+	groupProducer, prodErr := NewGroupProducer(jmsContext, queue)
+	if !assert.Nil(t, prodErr) {
+		return
+	}
+	defer groupProducer.Close()
+
+	bodies := []string{strings.Repeat("a", 2500), "b", strings.Repeat("c", 1000), strings.Repeat("d", 1001)}
+	sendGroup(t, jmsContext, groupProducer, true, bodies...)
+	assert.Equal(t, 7, qm.depth(fakeQueueName))
+
+	group, rcvErr := consumer.(ConsumerImpl).ReceiveGroup(100)
+	assert.Nil(t, rcvErr)
+	assertGroup(t, group, groupProducer.GetGroupID(), bodies...)
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+}
+
+/*
+ * Test that the segments of a message are sent and received together, so that
+ * a failure leaves either all of them or none of them on the queue.
+ */
+func TestSegmentationFailure(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	cf := ConnectionFactoryImpl{
+		SegmentSize:       1000,
+		ReceiveBufferSize: 1000,
+	}
+	jmsContext := createFakeContext(t, qm, cf, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := jmsContext.CreateQueue(fakeQueueName)
+
+	consumer, conErr := jmsContext.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer consumer.Close()
+
+	body := strings.Repeat("0123456789", 350)
+
+	qm.fail("MQPUT", ibmmq.MQRC_Q_FULL)
+	sendErr := jmsContext.CreateProducer().SendString(queue, body)
+	if assert.NotNil(t, sendErr) {
+		assert.Equal(t, "MQRC_Q_FULL", sendErr.GetReason())
+	}
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+	qm.fail("MQCMIT", ibmmq.MQRC_BACKED_OUT)
+	sendErr = jmsContext.CreateProducer().SendString(queue, body)
+	if assert.NotNil(t, sendErr) {
+		assert.Equal(t, "MQRC_BACKED_OUT", sendErr.GetReason())
+	}
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+	msgID := sendLargeMessage(t, jmsContext, queue, body)
+	assert.Equal(t, 4, qm.depth(fakeQueueName))
+
+	qm.fail("MQCMIT", ibmmq.MQRC_BACKED_OUT)
+	rcvMsg, rcvErr := consumer.ReceiveNoWait()
+	assert.Nil(t, rcvMsg)
+	if assert.NotNil(t, rcvErr) {
+		assert.Equal(t, "MQRC_BACKED_OUT", rcvErr.GetReason())
+	}
+	assert.Equal(t, 4, qm.depth(fakeQueueName))
+
+	// A supplied buffer must hold the whole message, not just one segment.
+	rcvMsg, rcvErr = consumer.ReceiveInto(make([]byte, 2000), 100)
+	assert.Nil(t, rcvMsg)
+	if assert.NotNil(t, rcvErr) {
+		assert.Equal(t, "MQRC_TRUNCATED_MSG_FAILED", rcvErr.GetReason())
+	}
+	assert.Equal(t, 4, qm.depth(fakeQueueName))
+
+	rcvMsg, rcvErr = consumer.ReceiveInto(make([]byte, 4000), 100)
+	assert.Nil(t, rcvErr)
+	assertLargeMessage(t, rcvMsg, msgID, body)
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+}
+
+/*
+ * Test that a message is not segmented or reassembled by the library while a
+ * group is being received in its own unit of work on the same JMSContext, as
+ * that would also complete the unit of work of the group.
+ */
+func TestSegmentationWhileReceivingGroup(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	cf := ConnectionFactoryImpl{
+		SegmentSize:       500,
+		ReceiveBufferSize: 1000,
+	}
+	jmsContext := createFakeContext(t, qm, cf, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := jmsContext.CreateQueue(fakeQueueName)
+	otherQueue := jmsContext.CreateQueue("FAKE.OTHER")
+
+	consumer, conErr := jmsContext.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer consumer.Close()
+
+	otherConsumer, conErr := jmsContext.CreateConsumer(otherQueue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer otherConsumer.Close()
+
+	data := fakeStreamData(3500)
+	assert.Nil(t, jmsContext.CreateProducer().SendStream(queue, bytes.NewReader(data)))
+
+	body := strings.Repeat("0123456789", 100)
+	assert.Nil(t, jmsContext.CreateProducer().SendString(otherQueue, body))
+
+	reader, rcvErr := consumer.ReceiveStream(100)
+	assert.Nil(t, rcvErr)
+	if !assert.NotNil(t, reader) {
+		return
+	}
+
+	received := make([]byte, 1500)
+	_, readErr := io.ReadFull(reader, received)
+	assert.Nil(t, readErr)
+
+	commits := qm.callCount("MQCMIT")
+
+	sendErr := jmsContext.CreateProducer().SendString(otherQueue, body)
+	if assert.NotNil(t, sendErr) {
+		assert.Equal(t, ContextImpl_UNIT_OF_WORK_OPEN_REASON, sendErr.GetReason())
+		assert.ErrorIs(t, sendErr, jms20subset.IllegalStateException{})
+	}
+
+	rcvMsg, rcvErr := otherConsumer.ReceiveNoWait()
+	assert.Nil(t, rcvMsg)
+	if assert.NotNil(t, rcvErr) {
+		assert.Equal(t, ContextImpl_UNIT_OF_WORK_OPEN_REASON, rcvErr.GetReason())
+	}
+
+	group, rcvErr := otherConsumer.(ConsumerImpl).ReceiveGroup(100)
+	assert.Nil(t, group)
+	if assert.NotNil(t, rcvErr) {
+		assert.Equal(t, ContextImpl_UNIT_OF_WORK_OPEN_REASON, rcvErr.GetReason())
+	}
+
+	otherReader, rcvErr := otherConsumer.ReceiveStream(100)
+	assert.Nil(t, otherReader)
+	if assert.NotNil(t, rcvErr) {
+		assert.Equal(t, ContextImpl_UNIT_OF_WORK_OPEN_REASON, rcvErr.GetReason())
+	}
+
+	// A message that is not segmented does not need a unit of work.
+	assert.Nil(t, jmsContext.CreateProducer().SendString(otherQueue, "small"))
+
+	// Nothing was committed while the stream was being read.
+	assert.Equal(t, commits, qm.callCount("MQCMIT"))
+
+	rest, readErr := io.ReadAll(reader)
+	assert.Nil(t, readErr)
+	assert.Equal(t, data, append(received, rest...))
+	assert.Nil(t, reader.Close())
+
+	// Once the stream is complete the messages can be received.
+	rcvMsg, rcvErr = otherConsumer.ReceiveNoWait()
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, rcvMsg) {
+		assert.Equal(t, body, *rcvMsg.(jms20subset.TextMessage).GetText())
+	}
+
+	assert.Nil(t, jmsContext.CreateProducer().SendString(otherQueue, body))
+
+}
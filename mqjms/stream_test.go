@@ -0,0 +1,280 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+package mqjms
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+)
+
+// fakeStreamData returns data of the specified length for a stream.
+func fakeStreamData(length int) []byte {
+
+	data := make([]byte, length)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	return data
+}
+
+/*
+ * Test that a stream is sent as a group of messages of the receive buffer size,
+ * and that it is received one message at a time.
+ */
+func TestStreamChunks(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	cf := ConnectionFactoryImpl{
+		ReceiveBufferSize: 1000,
+	}
+	jmsContext := createFakeContext(t, qm, cf, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := jmsContext.CreateQueue(fakeQueueName)
+
+	consumer, conErr := jmsContext.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer consumer.Close()
+
+	// There is always at least one message, and no empty message is sent at the
+	// end of a stream whose length is a multiple of the chunk size.
+	for length, messages := range map[int]int{3500: 4, 2000: 2, 0: 1} {
+
+		data := fakeStreamData(length)
+		assert.Nil(t, jmsContext.CreateProducer().SendStream(queue, bytes.NewReader(data)))
+		assert.Equal(t, messages, qm.depth(fakeQueueName))
+
+		reader, rcvErr := consumer.ReceiveStream(100)
+		assert.Nil(t, rcvErr)
+		if !assert.NotNil(t, reader) {
+			return
+		}
+
+		// The messages after the first are only received as they are needed.
+		if length > 1000 {
+			_, readErr := io.ReadFull(reader, make([]byte, 1000))
+			assert.Nil(t, readErr)
+			assert.Equal(t, (length-1)/1000, qm.depth(fakeQueueName))
+		}
+
+		rest, readErr := io.ReadAll(iotest.OneByteReader(reader))
+		assert.Nil(t, readErr)
+		if length > 1000 {
+			assert.Equal(t, data[1000:], rest)
+		} else {
+			assert.Equal(t, 0, len(rest))
+		}
+
+		assert.Nil(t, reader.Close())
+		assert.Equal(t, 0, qm.depth(fakeQueueName))
+	}
+
+	// The chunks are segmented in the same way as any other message.
+	segmentingContext := createFakeContext(t, qm, ConnectionFactoryImpl{ReceiveBufferSize: 1000, SegmentSize: 500}, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	segmentingConsumer, conErr := segmentingContext.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer segmentingConsumer.Close()
+
+	data := fakeStreamData(3500)
+	assert.Nil(t, segmentingContext.CreateProducer().SendStream(queue, bytes.NewReader(data)))
+	assert.Equal(t, 7, qm.depth(fakeQueueName))
+
+	reader, rcvErr := segmentingConsumer.ReceiveStream(100)
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, reader) {
+		received, readErr := io.ReadAll(reader)
+		assert.Nil(t, readErr)
+		assert.Equal(t, data, received)
+		assert.Nil(t, reader.Close())
+	}
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+}
+
+/*
+ * Test that a stream that fails part way through is never received, and that a
+ * stream that cannot be received as a whole is left on the queue.
+ */
+func TestStreamFailure(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	cf := ConnectionFactoryImpl{
+		ReceiveBufferSize: 1000,
+	}
+	jmsContext := createFakeContext(t, qm, cf, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := jmsContext.CreateQueue(fakeQueueName)
+
+	consumer, conErr := jmsContext.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer consumer.Close()
+
+	// The chunk that was sent before the failure is backed out, rather than being
+	// left on the queue as a group whose last message is never sent.
+	readErr := errors.New("stream failed")
+	stream := io.MultiReader(bytes.NewReader(fakeStreamData(1500)), iotest.ErrReader(readErr))
+
+	sendErr := jmsContext.CreateProducer().SendStream(queue, stream)
+	if assert.NotNil(t, sendErr) {
+		assert.Equal(t, ProducerImpl_STREAM_READ_REASON, sendErr.GetReason())
+		assert.True(t, errors.Is(sendErr, readErr))
+	}
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+	assert.Equal(t, 1, qm.callCount("MQBACK"))
+
+	// The same happens if a chunk after the first cannot be sent. The failure of
+	// the second put is scripted once the first has been sent, which is when the
+	// stream is read to the end.
+	failPut := readerFunc(func(p []byte) (int, error) {
+		qm.fail("MQPUT", ibmmq.MQRC_Q_FULL)
+		return 0, io.EOF
+	})
+	stream = io.MultiReader(bytes.NewReader(fakeStreamData(2000)), failPut)
+
+	sendErr = jmsContext.CreateProducer().SendStream(queue, stream)
+	if assert.NotNil(t, sendErr) {
+		assert.Equal(t, "MQRC_Q_FULL", sendErr.GetReason())
+	}
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+	assert.Equal(t, 2, qm.callCount("MQBACK"))
+
+	reader, rcvErr := consumer.ReceiveStream(100)
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, reader)
+
+	// The stream is only removed from the queue once it has been committed.
+	data := fakeStreamData(2500)
+	assert.Nil(t, jmsContext.CreateProducer().SendStream(queue, bytes.NewReader(data)))
+
+	reader, rcvErr = consumer.ReceiveStream(100)
+	assert.Nil(t, rcvErr)
+	if !assert.NotNil(t, reader) {
+		return
+	}
+
+	qm.fail("MQCMIT", ibmmq.MQRC_BACKED_OUT)
+	_, readErr = io.ReadAll(reader)
+	if assert.NotNil(t, readErr) {
+		assert.Equal(t, "MQRC_BACKED_OUT", readErr.(jms20subset.JMSException).GetReason())
+	}
+	assert.Nil(t, reader.Close())
+	assert.Equal(t, 3, qm.depth(fakeQueueName))
+
+	_, readErr = reader.Read(make([]byte, 10))
+	if assert.NotNil(t, readErr) {
+		assert.Equal(t, ConsumerImpl_STREAM_CLOSED_REASON, readErr.(jms20subset.JMSException).GetReason())
+	}
+
+	// A stream whose next message cannot be received is backed out.
+	reader, rcvErr = consumer.ReceiveStream(100)
+	assert.Nil(t, rcvErr)
+	if !assert.NotNil(t, reader) {
+		return
+	}
+
+	qm.fail("MQGET", ibmmq.MQRC_GET_INHIBITED)
+	_, readErr = io.ReadAll(reader)
+	if assert.NotNil(t, readErr) {
+		assert.Equal(t, "MQRC_GET_INHIBITED", readErr.(jms20subset.JMSException).GetReason())
+	}
+	assert.Nil(t, reader.Close())
+	assert.Equal(t, 3, qm.depth(fakeQueueName))
+
+	reader, rcvErr = consumer.ReceiveStream(100)
+	assert.Nil(t, rcvErr)
+	if assert.NotNil(t, reader) {
+		received, readErr := io.ReadAll(reader)
+		assert.Nil(t, readErr)
+		assert.Equal(t, data, received)
+		assert.Nil(t, reader.Close())
+	}
+	assert.Equal(t, 0, qm.depth(fakeQueueName))
+
+}
+
+/*
+ * Test that a stream whose data has all been read is committed when the reader
+ * is closed, even if Read has not returned io.EOF, and that one that has only
+ * been partly read is backed out.
+ */
+func TestStreamCloseAfterReadingAll(t *testing.T) {
+
+	qm := newFakeQueueManager("FAKEQM")
+	cf := ConnectionFactoryImpl{
+		ReceiveBufferSize: 1000,
+	}
+	jmsContext := createFakeContext(t, qm, cf, jms20subset.JMSContextAUTOACKNOWLEDGE)
+	queue := jmsContext.CreateQueue(fakeQueueName)
+
+	consumer, conErr := jmsContext.CreateConsumer(queue)
+	if !assert.Nil(t, conErr) {
+		return
+	}
+	defer consumer.Close()
+
+	data := fakeStreamData(3500)
+	assert.Nil(t, jmsContext.CreateProducer().SendStream(queue, bytes.NewReader(data)))
+	assert.Nil(t, jmsContext.CreateProducer().SendStream(queue, bytes.NewReader(data)))
+	assert.Equal(t, 8, qm.depth(fakeQueueName))
+
+	// Reading all but the last byte leaves the stream on the queue.
+	reader, rcvErr := consumer.ReceiveStream(100)
+	assert.Nil(t, rcvErr)
+	if !assert.NotNil(t, reader) {
+		return
+	}
+
+	_, readErr := io.ReadFull(reader, make([]byte, len(data)-1))
+	assert.Nil(t, readErr)
+	assert.Nil(t, reader.Close())
+	assert.Equal(t, 8, qm.depth(fakeQueueName))
+
+	// Reading exactly the length of the data removes it from the queue.
+	for remaining := 4; remaining >= 0; remaining -= 4 {
+
+		reader, rcvErr = consumer.ReceiveStream(100)
+		assert.Nil(t, rcvErr)
+		if !assert.NotNil(t, reader) {
+			return
+		}
+
+		received := make([]byte, len(data))
+		_, readErr = io.ReadFull(reader, received)
+		assert.Nil(t, readErr)
+		assert.Equal(t, data, received)
+
+		commits := qm.callCount("MQCMIT")
+		assert.Nil(t, reader.Close())
+		assert.Equal(t, commits+1, qm.callCount("MQCMIT"))
+		assert.Equal(t, remaining, qm.depth(fakeQueueName))
+	}
+
+	// The unit of work of the stream is complete, so another can be received.
+	reader, rcvErr = consumer.ReceiveStream(100)
+	assert.Nil(t, rcvErr)
+	assert.Nil(t, reader)
+
+}
+
+// readerFunc is an io.Reader that calls the function.
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) {
+	return f(p)
+}
@@ -0,0 +1,285 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+/*
+ * Test looking up connection factories and destinations that are defined in
+ * an object store file.
+ */
+func TestObjectStoreLookup(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "objectstore")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	storeFile := filepath.Join(dir, "store.json")
+	ioutil.WriteFile(storeFile, []byte(`{
+  "connectionFactories": {
+    "jms/OrdersCF": { "queueManager": "QM1", "hostname": "myhost", "port": 1414, "channel": "DEV.APP.SVRCONN" }
+  },
+  "destinations": {
+    "jms/OrdersQ": { "queueName": "ORDERS", "persistence": "NON_PERSISTENT", "priority": 7 },
+    "jms/ShippingQ": { "queueName": "SHIPPING", "queueManager": "QM2", "targetClient": "MQ", "putAsyncAllowed": "ENABLED" }
+  }
+}`), 0600)
+
+	store, err := mqjms.LoadObjectStore(storeFile)
+	assert.Nil(t, err)
+
+	cf, err := store.Lookup("jms/OrdersCF")
+	assert.Nil(t, err)
+	assert.Equal(t, "QM1", cf.QMName)
+	assert.Equal(t, "myhost", cf.Hostname)
+	assert.Equal(t, 1414, cf.PortNumber)
+	assert.Equal(t, "DEV.APP.SVRCONN", cf.ChannelName)
+
+	dest, err := store.LookupDestination("jms/OrdersQ")
+	assert.Nil(t, err)
+	orders := dest.(mqjms.QueueImpl)
+	assert.Equal(t, "ORDERS", orders.GetQueueName())
+	assert.Equal(t, "", orders.GetQueueManagerName())
+	assert.Equal(t, jms20subset.DeliveryMode_NON_PERSISTENT, orders.GetDeliveryMode())
+	assert.Equal(t, 7, orders.GetPriority())
+	assert.Equal(t, mqjms.TargetClient_JMS, orders.GetTargetClient())
+	assert.Equal(t, jms20subset.Destination_PUT_ASYNC_ALLOWED_AS_DEST, orders.GetPutAsyncAllowed())
+
+	dest, err = store.LookupDestination("jms/ShippingQ")
+	assert.Nil(t, err)
+	shipping := dest.(mqjms.QueueImpl)
+	assert.Equal(t, "SHIPPING", shipping.GetQueueName())
+	assert.Equal(t, "QM2", shipping.GetQueueManagerName())
+	assert.Equal(t, mqjms.Destination_AS_PRODUCER, shipping.GetDeliveryMode())
+	assert.Equal(t, mqjms.Destination_AS_PRODUCER, shipping.GetPriority())
+	assert.Equal(t, mqjms.TargetClient_MQ, shipping.GetTargetClient())
+	assert.Equal(t, jms20subset.Destination_PUT_ASYNC_ALLOWED_ENABLED, shipping.GetPutAsyncAllowed())
+
+	_, err = store.Lookup("jms/Missing")
+	assert.NotNil(t, err)
+	_, err = store.LookupDestination("jms/Missing")
+	assert.NotNil(t, err)
+
+	// Repoint the destination, which is picked up by a reload.
+	ioutil.WriteFile(storeFile, []byte(`{
+  "destinations": { "jms/OrdersQ": { "queueName": "ORDERS.V2" } }
+}`), 0600)
+	assert.Nil(t, store.Reload())
+
+	dest, err = store.LookupDestination("jms/OrdersQ")
+	assert.Nil(t, err)
+	assert.Equal(t, "ORDERS.V2", dest.GetDestinationName())
+
+	// A file that is not valid leaves the previous definitions in place.
+	ioutil.WriteFile(storeFile, []byte(`{
+  "destinations": { "jms/OrdersQ": { "queueName": "ORDERS", "priority": 12 } }
+}`), 0600)
+	assert.NotNil(t, store.Reload())
+
+	dest, err = store.LookupDestination("jms/OrdersQ")
+	assert.Nil(t, err)
+	assert.Equal(t, "ORDERS.V2", dest.GetDestinationName())
+
+}
+
+/*
+ * Test looking up the connection factories and queues that are defined in a
+ * .bindings file written by JMSAdmin for Java applications.
+ */
+func TestObjectStoreBindings(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "objectstore")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	storeFile := filepath.Join(dir, ".bindings")
+	ioutil.WriteFile(storeFile, []byte(`#This file is used by the JNDI FSContext.
+#Sat Oct 17 10:00:00 BST 2026
+OrdersCF/ClassName=com.ibm.mq.jms.MQQueueConnectionFactory
+OrdersCF/FactoryName=com.ibm.mq.jms.MQQueueConnectionFactoryFactory
+OrdersCF/RefAddr/0/Type=VER
+OrdersCF/RefAddr/0/Encoding=String
+OrdersCF/RefAddr/0/Content=7
+OrdersCF/RefAddr/1/Type=TRAN
+OrdersCF/RefAddr/1/Encoding=String
+OrdersCF/RefAddr/1/Content=1
+OrdersCF/RefAddr/2/Type=QMGR
+OrdersCF/RefAddr/2/Encoding=String
+OrdersCF/RefAddr/2/Content=QM1
+OrdersCF/RefAddr/3/Type=HOST
+OrdersCF/RefAddr/3/Encoding=String
+OrdersCF/RefAddr/3/Content=myhost
+OrdersCF/RefAddr/4/Type=PORT
+OrdersCF/RefAddr/4/Encoding=String
+OrdersCF/RefAddr/4/Content=1414
+OrdersCF/RefAddr/5/Type=CHAN
+OrdersCF/RefAddr/5/Encoding=String
+OrdersCF/RefAddr/5/Content=DEV.APP.SVRCONN
+OrdersQ/ClassName=com.ibm.mq.jms.MQQueue
+OrdersQ/FactoryName=com.ibm.mq.jms.MQQueueFactory
+OrdersQ/RefAddr/0/Type=VER
+OrdersQ/RefAddr/0/Encoding=String
+OrdersQ/RefAddr/0/Content=7
+OrdersQ/RefAddr/1/Type=QU
+OrdersQ/RefAddr/1/Encoding=String
+OrdersQ/RefAddr/1/Content=ORDERS
+OrdersQ/RefAddr/2/Type=PER
+OrdersQ/RefAddr/2/Encoding=String
+OrdersQ/RefAddr/2/Content=1
+OrdersQ/RefAddr/3/Type=PRI
+OrdersQ/RefAddr/3/Encoding=String
+OrdersQ/RefAddr/3/Content=7
+OrdersQ/RefAddr/4/Type=TC
+OrdersQ/RefAddr/4/Encoding=String
+OrdersQ/RefAddr/4/Content=1
+OrdersQ/RefAddr/5/Type=QMGR
+OrdersQ/RefAddr/5/Encoding=String
+OrdersQ/RefAddr/5/Content=
+PricesT/ClassName=com.ibm.mq.jms.MQTopic
+PricesT/FactoryName=com.ibm.mq.jms.MQTopicFactory
+PricesT/RefAddr/0/Type=TOP
+PricesT/RefAddr/0/Encoding=String
+PricesT/RefAddr/0/Content=prices
+`), 0600)
+
+	store, err := mqjms.LoadObjectStore(storeFile)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	cf, err := store.Lookup("OrdersCF")
+	assert.Nil(t, err)
+	assert.Equal(t, "QM1", cf.QMName)
+	assert.Equal(t, "myhost", cf.Hostname)
+	assert.Equal(t, 1414, cf.PortNumber)
+	assert.Equal(t, "DEV.APP.SVRCONN", cf.ChannelName)
+	assert.Equal(t, mqjms.TransportType_CLIENT, cf.TransportType)
+
+	dest, err := store.LookupDestination("OrdersQ")
+	assert.Nil(t, err)
+	orders := dest.(mqjms.QueueImpl)
+	assert.Equal(t, "ORDERS", orders.GetQueueName())
+	assert.Equal(t, "", orders.GetQueueManagerName())
+	assert.Equal(t, jms20subset.DeliveryMode_NON_PERSISTENT, orders.GetDeliveryMode())
+	assert.Equal(t, 7, orders.GetPriority())
+	assert.Equal(t, mqjms.TargetClient_MQ, orders.GetTargetClient())
+
+	// Topics are not read from the file.
+	_, err = store.LookupDestination("PricesT")
+	assert.NotNil(t, err)
+
+	// A property value that has no equivalent in this library is an error.
+	ioutil.WriteFile(storeFile, []byte(`OrdersQ/ClassName=com.ibm.mq.jms.MQQueue
+OrdersQ/RefAddr/0/Type=QU
+OrdersQ/RefAddr/0/Content=ORDERS
+OrdersQ/RefAddr/1/Type=PER
+OrdersQ/RefAddr/1/Content=0
+`), 0600)
+	assert.NotNil(t, store.Reload())
+
+}
+
+/*
+ * Test that the attributes of a destination from the object store override
+ * those of the producer when a message is sent.
+ */
+func TestObjectStoreDestinationAttributes(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Use the handle cache, which must distinguish the queue on each queue manager.
+	cf.ProducerHandleCacheSize = 2
+
+	dir, err := ioutil.TempDir("", "objectstore")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	storeFile := filepath.Join(dir, "store.yaml")
+	ioutil.WriteFile(storeFile, []byte(`
+destinations:
+  jms/TestQ:
+    queueName: DEV.QUEUE.1
+    queueManager: `+cf.QMName+`
+    persistence: NON_PERSISTENT
+    priority: 2
+  jms/PlainQ:
+    queueName: DEV.QUEUE.1
+    targetClient: MQ
+`), 0600)
+
+	store, err := mqjms.LoadObjectStore(storeFile)
+	assert.Nil(t, err)
+
+	testQ, err := store.LookupDestination("jms/TestQ")
+	assert.Nil(t, err)
+	plainQ, err := store.LookupDestination("jms/PlainQ")
+	assert.Nil(t, err)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	consumer, conErr := context.CreateConsumer(context.CreateQueue("DEV.QUEUE.1"))
+	assert.Nil(t, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// The producer settings are overridden by the destination.
+	producer := context.CreateProducer().SetDeliveryMode(jms20subset.DeliveryMode_PERSISTENT).SetPriority(8)
+
+	propValue := "myValue"
+	msg := context.CreateTextMessageWithString("ObjectStoreMsg")
+	msg.SetStringProperty("myProp", &propValue)
+	errSend := producer.Send(testQ, msg)
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+	assert.Equal(t, jms20subset.DeliveryMode_NON_PERSISTENT, rcvMsg.GetJMSDeliveryMode())
+	assert.Equal(t, 2, rcvMsg.GetJMSPriority())
+	gotPropValue, propErr := rcvMsg.GetStringProperty("myProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "myValue", *gotPropValue)
+
+	// A destination for a non-JMS application receives the body without the properties,
+	// and uses the settings of the producer.
+	msg = context.CreateTextMessageWithString("ObjectStorePlainMsg")
+	msg.SetStringProperty("myProp", &propValue)
+	errSend = producer.Send(plainQ, msg)
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRcv = consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+	assert.Equal(t, "ObjectStorePlainMsg", *rcvMsg.(jms20subset.TextMessage).GetText())
+	assert.Equal(t, jms20subset.DeliveryMode_PERSISTENT, rcvMsg.GetJMSDeliveryMode())
+	assert.Equal(t, 8, rcvMsg.GetJMSPriority())
+	gotPropValue, propErr = rcvMsg.GetStringProperty("myProp")
+	assert.Nil(t, propErr)
+	assert.Nil(t, gotPropValue)
+
+}
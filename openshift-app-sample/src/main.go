@@ -12,8 +12,6 @@ package main
 import (
 	"fmt"
 	"log"
-	"os"
-	"strconv"
 
 	"github.com/zemlya25/mq-golang-jms20/mqjms"
 )
@@ -21,16 +19,11 @@ import (
 func main() {
 	fmt.Println("Beginning world!!!")
 
-	portNum, _ := strconv.Atoi(os.Getenv("PORT"))
-
-	// Initialise the attributes of the CF in whatever way you like
-	cf := mqjms.ConnectionFactoryImpl{
-		QMName:      os.Getenv("QMNAME"),
-		Hostname:    os.Getenv("HOSTNAME"),
-		PortNumber:  portNum,
-		ChannelName: os.Getenv("CHANNELNAME"),
-		UserName:    os.Getenv("USERNAME"),
-		Password:    os.Getenv("PASSWORD"),
+	// Initialise the attributes of the CF from the MQ_* environment variables (and
+	// the file named by MQ_CONFIG_FILE, if it is set).
+	cf, errCF := mqjms.LoadConnectionFactory("")
+	if errCF != nil {
+		log.Fatal(errCF)
 	}
 
 	// Creates a connection to the queue manager, using defer to close it automatically
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+/*
+ * Test sending messages when the context keeps destinations open between sends,
+ * including when there are more destinations than the size of the cache.
+ */
+func TestProducerHandleCache(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Keep a single destination open, so that alternating between two queues
+	// causes the open handle to be replaced on every send.
+	cf.ProducerHandleCacheSize = 1
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue1 := context.CreateQueue("DEV.QUEUE.1")
+	queue2 := context.CreateQueue("DEV.QUEUE.2")
+
+	consumer1, conErr := context.CreateConsumer(queue1)
+	assert.Nil(t, conErr)
+	if consumer1 != nil {
+		defer consumer1.Close()
+	}
+
+	consumer2, conErr2 := context.CreateConsumer(queue2)
+	assert.Nil(t, conErr2)
+	if consumer2 != nil {
+		defer consumer2.Close()
+	}
+
+	producer := context.CreateProducer()
+	numMsgs := 10
+
+	// Several sends to the same queue reuse the same open handle.
+	for i := 0; i < numMsgs; i++ {
+		errSend := producer.SendString(queue1, "CachedMsg"+strconv.Itoa(i))
+		assert.Nil(t, errSend)
+	}
+
+	for i := 0; i < numMsgs; i++ {
+		rcvBody, errRcv := consumer1.ReceiveStringBodyNoWait()
+		assert.Nil(t, errRcv)
+		assert.NotNil(t, rcvBody)
+		assert.Equal(t, "CachedMsg"+strconv.Itoa(i), *rcvBody)
+	}
+
+	// Alternate between the queues so that handles are evicted from the cache.
+	for i := 0; i < numMsgs; i++ {
+		errSend := producer.SendString(queue1, "Queue1Msg"+strconv.Itoa(i))
+		assert.Nil(t, errSend)
+		errSend = producer.SendString(queue2, "Queue2Msg"+strconv.Itoa(i))
+		assert.Nil(t, errSend)
+	}
+
+	for i := 0; i < numMsgs; i++ {
+		rcvBody, errRcv := consumer1.ReceiveStringBodyNoWait()
+		assert.Nil(t, errRcv)
+		assert.NotNil(t, rcvBody)
+		assert.Equal(t, "Queue1Msg"+strconv.Itoa(i), *rcvBody)
+
+		rcvBody, errRcv = consumer2.ReceiveStringBodyNoWait()
+		assert.Nil(t, errRcv)
+		assert.NotNil(t, rcvBody)
+		assert.Equal(t, "Queue2Msg"+strconv.Itoa(i), *rcvBody)
+	}
+
+	// Errors opening the queue are reported in the same way as for MQPUT1.
+	errSend := producer.SendString(context.CreateQueue("DOES.NOT.EXIST"), "NotSent")
+	assert.NotNil(t, errSend)
+	assert.Equal(t, "2085", errSend.GetErrorCode())
+	assert.Equal(t, "MQRC_UNKNOWN_OBJECT_NAME", errSend.GetReason())
+
+}
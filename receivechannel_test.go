@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+/*
+ * Test receiving messages from a channel, in a select statement alongside
+ * a timer, and that the channels are closed when the context is cancelled.
+ */
+func TestReceiveChannel(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	jmsContext, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if jmsContext != nil {
+		defer jmsContext.Close()
+	}
+
+	// Equivalent to a JNDI lookup or other declarative definition
+	queue := jmsContext.CreateQueue("DEV.QUEUE.1")
+
+	// Check no message on the queue to start with
+	consumer, conErr := jmsContext.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	testMsg, err := consumer.ReceiveNoWait()
+	assert.Nil(t, err)
+	assert.Nil(t, testMsg)
+
+	// Send some messages to be received through the channel.
+	producer := jmsContext.CreateProducer()
+	numMsgs := 5
+	for i := 0; i < numMsgs; i++ {
+		errSend := producer.SendString(queue, "ChannelMsg"+strconv.Itoa(i))
+		assert.Nil(t, errSend)
+	}
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	msgChan, errChan := consumer.ReceiveChannel(cancelCtx, 2)
+
+	received := 0
+	timeout := time.After(10 * time.Second)
+
+	for received < numMsgs {
+		select {
+		case msg := <-msgChan:
+			switch msg := msg.(type) {
+			case jms20subset.TextMessage:
+				assert.Equal(t, "ChannelMsg"+strconv.Itoa(received), *msg.GetText())
+			default:
+				assert.Fail(t, "Got something other than a text message")
+			}
+			received++
+
+		case errRcv := <-errChan:
+			assert.Fail(t, "Unexpected error from receive loop", errRcv)
+			return
+
+		case <-timeout:
+			assert.Fail(t, "Timed out waiting for messages, received "+strconv.Itoa(received))
+			return
+		}
+	}
+
+	// Nothing else should arrive.
+	select {
+	case msg := <-msgChan:
+		assert.Nil(t, msg)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	// Cancelling the context stops the loop and closes both channels, without
+	// reporting an error.
+	cancel()
+
+	select {
+	case msg, ok := <-msgChan:
+		assert.False(t, ok)
+		assert.Nil(t, msg)
+	case <-time.After(time.Duration(mqjms.ConsumerImpl_DEFAULT_RECEIVE_WAIT_SLICE_MILLIS+1000) * time.Millisecond):
+		assert.Fail(t, "Message channel was not closed")
+	}
+
+	errRcv, ok := <-errChan
+	assert.False(t, ok)
+	assert.Nil(t, errRcv)
+
+}
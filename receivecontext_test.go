@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+/*
+ * Test that a receive using a Go context returns when the deadline of the
+ * context is reached.
+ */
+func TestReceiveContextDeadline(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	jmsContext, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if jmsContext != nil {
+		defer jmsContext.Close()
+	}
+
+	// Equivalent to a JNDI lookup or other declarative definition
+	queue := jmsContext.CreateQueue("DEV.QUEUE.1")
+
+	// Set up the consumer ready to receive messages.
+	consumer, conErr := jmsContext.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// Check no message on the queue to start with
+	testMsg, err1 := consumer.ReceiveNoWait()
+	assert.Nil(t, err1)
+	assert.Nil(t, testMsg)
+
+	// The deadline is longer than a single wait slice, to check that the
+	// receive keeps waiting across several MQGET calls.
+	waitTime := int64(2500)
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), time.Duration(waitTime)*time.Millisecond)
+	defer cancel()
+
+	startTime := currentTimeMillis()
+	testMsg2, err2 := consumer.ReceiveContext(timeoutCtx)
+	endTime := currentTimeMillis()
+	assert.Nil(t, testMsg2)
+	assert.NotNil(t, err2)
+	assert.Equal(t, mqjms.ContextImpl_CONTEXT_DONE_CODE, err2.GetErrorCode())
+	assert.Equal(t, context.DeadlineExceeded, err2.GetLinkedError())
+
+	// Within a reasonable margin of the expected wait time.
+	assert.True(t, (endTime-startTime-waitTime) > -100)
+	assert.True(t, (endTime-startTime-waitTime) < 500)
+
+	// Send a message using a context, and check that it can be received
+	// straight away.
+	msgBody := "ReceiveContextMsg"
+	errSend := jmsContext.CreateProducer().SendContext(context.Background(), queue, jmsContext.CreateTextMessageWithString(msgBody))
+	assert.Nil(t, errSend)
+
+	timeoutCtx2, cancel2 := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel2()
+
+	startTime2 := currentTimeMillis()
+	testMsg3, err3 := consumer.ReceiveContext(timeoutCtx2)
+	endTime2 := currentTimeMillis()
+	assert.Nil(t, err3)
+	assert.NotNil(t, testMsg3)
+	assert.True(t, (endTime2-startTime2) < 300)
+
+}
+
+/*
+ * Test that an indefinite receive using a Go context can be interrupted by
+ * cancelling the context from another goroutine, and that a send using a
+ * cancelled context does not send the message.
+ */
+func TestReceiveContextCancel(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	jmsContext, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if jmsContext != nil {
+		defer jmsContext.Close()
+	}
+
+	queue := jmsContext.CreateQueue("DEV.QUEUE.1")
+
+	consumer, conErr := jmsContext.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// Cancel the context after a short period, as a signal handler would.
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(1500 * time.Millisecond)
+		cancel()
+	}()
+
+	startTime := currentTimeMillis()
+	testMsg, err := consumer.ReceiveContext(cancelCtx)
+	endTime := currentTimeMillis()
+	assert.Nil(t, testMsg)
+	assert.NotNil(t, err)
+	assert.Equal(t, mqjms.ContextImpl_CONTEXT_DONE_REASON, err.GetReason())
+	assert.Equal(t, context.Canceled, err.GetLinkedError())
+
+	// Cancellation is noticed at the end of the current wait slice.
+	assert.True(t, (endTime-startTime) < 1500+int64(mqjms.ConsumerImpl_DEFAULT_RECEIVE_WAIT_SLICE_MILLIS)+200)
+
+	// A send with a context that is already cancelled is rejected.
+	errSend := jmsContext.CreateProducer().SendContext(cancelCtx, queue, jmsContext.CreateTextMessageWithString("NotSent"))
+	assert.NotNil(t, errSend)
+	assert.Equal(t, mqjms.ContextImpl_CONTEXT_DONE_CODE, errSend.GetErrorCode())
+
+	// Check that the message did not arrive on the queue.
+	testMsg2, err2 := consumer.ReceiveNoWait()
+	assert.Nil(t, err2)
+	assert.Nil(t, testMsg2)
+
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+/*
+ * Test receiving messages into a buffer that is supplied by the application.
+ */
+func TestReceiveInto(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// Check no message on the queue to start with
+	testMsg, err := consumer.ReceiveNoWait()
+	assert.Nil(t, err)
+	assert.Nil(t, testMsg)
+
+	producer := context.CreateProducer()
+	buffer := make([]byte, 16)
+
+	// The body of a received BytesMessage refers to the supplied buffer.
+	msgBytes := []byte{'a', 'b', 'c', 'd'}
+	errSend := producer.SendBytes(queue, msgBytes)
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRcv := consumer.ReceiveInto(buffer, 1000)
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	switch msg := rcvMsg.(type) {
+	case jms20subset.BytesMessage:
+		assert.Equal(t, msgBytes, *msg.ReadBytes())
+		assert.Equal(t, 4, msg.GetBodyLength())
+		assert.Equal(t, msgBytes, buffer[0:4])
+	default:
+		assert.Fail(t, "Got something other than a bytes message")
+	}
+
+	// A TextMessage body is copied out of the buffer.
+	msgBody := "ReceiveIntoMsg"
+	errSend = producer.SendString(queue, msgBody)
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRcv = consumer.ReceiveInto(buffer, 1000)
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	switch msg := rcvMsg.(type) {
+	case jms20subset.TextMessage:
+		buffer[0] = 'X'
+		assert.Equal(t, msgBody, *msg.GetText())
+	default:
+		assert.Fail(t, "Got something other than a text message")
+	}
+
+	// A message that is too big for the buffer is left on the queue.
+	bigBytes := make([]byte, 100)
+	errSend = producer.SendBytes(queue, bigBytes)
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRcv = consumer.ReceiveInto(buffer, 1000)
+	assert.NotNil(t, errRcv)
+	assert.Equal(t, "2080", errRcv.GetErrorCode())
+	assert.Equal(t, "MQRC_TRUNCATED_MSG_FAILED", errRcv.GetReason())
+	assert.Nil(t, rcvMsg)
+
+	rcvBytes, errRcv := consumer.ReceiveBytesBodyNoWait()
+	assert.Nil(t, errRcv)
+	assert.Equal(t, 100, len(*rcvBytes))
+
+}
+
+/*
+ * Benchmark the normal receive path, in which the receive buffer is borrowed
+ * from a pool and the message body is copied into a slice of the right size.
+ */
+func BenchmarkReceive(b *testing.B) {
+
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(b, cfErr)
+
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(b, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(b, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	producer := context.CreateProducer()
+	msgBytes := make([]byte, 1024)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		producer.SendBytes(queue, msgBytes)
+		rcvMsg, errRcv := consumer.ReceiveNoWait()
+		if errRcv != nil || rcvMsg == nil {
+			b.Fatal("Failed to receive message", errRcv)
+		}
+	}
+
+}
+
+/*
+ * Benchmark receiving into a single buffer that is supplied by the application,
+ * which avoids allocating a buffer for each message.
+ */
+func BenchmarkReceiveInto(b *testing.B) {
+
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(b, cfErr)
+
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(b, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(b, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	producer := context.CreateProducer()
+	msgBytes := make([]byte, 1024)
+	buffer := make([]byte, 32768)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		producer.SendBytes(queue, msgBytes)
+		rcvMsg, errRcv := consumer.ReceiveInto(buffer, 1000)
+		if errRcv != nil || rcvMsg == nil {
+			b.Fatal("Failed to receive message", errRcv)
+		}
+	}
+
+}
@@ -10,6 +10,8 @@
 package main
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -140,3 +142,87 @@ func replyToMessage(t *testing.T, cf jms20subset.ConnectionFactory, requestQueue
 	assert.Nil(t, err2)
 
 }
+
+/*
+ * Test the same request/reply pattern using the Requestor and Responder helpers,
+ * with several requests outstanding at the same time.
+ */
+func TestRequestorResponder(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	requestQueue := context.CreateQueue("DEV.QUEUE.1")
+	replyQueue := context.CreateQueue("DEV.QUEUE.2")
+
+	requestor, reqErr := mqjms.NewRequestor(context, requestQueue, replyQueue)
+	assert.Nil(t, reqErr)
+	if requestor == nil {
+		return
+	}
+	defer requestor.Close()
+
+	// "Another application" replies to each of the requests.
+	respContext, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if respContext != nil {
+		defer respContext.Close()
+	}
+
+	requestConsumer, rConErr := respContext.CreateConsumer(requestQueue)
+	assert.Nil(t, rConErr)
+	if requestConsumer != nil {
+		defer requestConsumer.Close()
+	}
+
+	numRequests := 5
+	responder := mqjms.NewResponder(respContext)
+
+	go func() {
+		for i := 0; i < numRequests; i++ {
+			reqMsg, err := requestConsumer.Receive(5000)
+			if err != nil || reqMsg == nil {
+				return
+			}
+
+			replyMsg := respContext.CreateTextMessageWithString("Reply to " + *reqMsg.(jms20subset.TextMessage).GetText())
+			assert.Nil(t, responder.Reply(reqMsg, replyMsg))
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < numRequests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			body := fmt.Sprintf("Request %d", i)
+			replyMsg, err := requestor.Request(context.CreateTextMessageWithString(body), 5000)
+			assert.Nil(t, err)
+
+			// Each goroutine receives the reply to its own request.
+			if assert.NotNil(t, replyMsg) {
+				assert.Equal(t, "Reply to "+body, *replyMsg.(jms20subset.TextMessage).GetText())
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// A request that is not answered within the timeout returns no reply.
+	replyMsg, err := requestor.Request(context.CreateTextMessageWithString("Unanswered"), 500)
+	assert.Nil(t, err)
+	assert.Nil(t, replyMsg)
+
+	// Remove the unanswered request from the queue.
+	reqMsg, err := requestConsumer.ReceiveNoWait()
+	assert.Nil(t, err)
+	assert.NotNil(t, reqMsg)
+
+}
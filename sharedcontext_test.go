@@ -0,0 +1,147 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+/*
+ * Test creating further JMSContexts from the first, and that the contexts share
+ * the transaction scope of the connection handle.
+ */
+func TestSharedConnectionContexts(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// Create a transacted context with the same settings.
+	txContext, txCtxErr := context.CreateContext(jms20subset.JMSContextSESSIONTRANSACTED)
+	assert.Nil(t, txCtxErr)
+	if txContext != nil {
+		defer txContext.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// Check no message on the queue to start with
+	testMsg, err := consumer.ReceiveNoWait()
+	assert.Nil(t, err)
+	assert.Nil(t, testMsg)
+
+	// Send a message under the transaction of the second context.
+	msgBody := "SharedConnectionMsg"
+	errSend := txContext.CreateProducer().SendString(queue, msgBody)
+	assert.Nil(t, errSend)
+
+	// The message is not visible to the first context until it is committed.
+	testMsg, err = consumer.ReceiveNoWait()
+	assert.Nil(t, err)
+	assert.Nil(t, testMsg)
+
+	// Commit on the first context, which is not transacted, has no effect.
+	errCommit := context.Commit()
+	assert.Nil(t, errCommit)
+
+	testMsg, err = consumer.ReceiveNoWait()
+	assert.Nil(t, err)
+	assert.Nil(t, testMsg)
+
+	errCommit = txContext.Commit()
+	assert.Nil(t, errCommit)
+
+	rcvBody, err := consumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, err)
+	assert.NotNil(t, rcvBody)
+	assert.Equal(t, msgBody, *rcvBody)
+
+	// A message sent under the transaction of another transacted context is
+	// rolled back by the first, since they share the unit of work.
+	txContext2, txCtxErr2 := context.CreateContext(jms20subset.JMSContextSESSIONTRANSACTED)
+	assert.Nil(t, txCtxErr2)
+	if txContext2 != nil {
+		defer txContext2.Close()
+	}
+
+	errSend = txContext2.CreateProducer().SendString(queue, "RolledBackMsg")
+	assert.Nil(t, errSend)
+
+	errRollback := txContext.Rollback()
+	assert.Nil(t, errRollback)
+
+	errCommit = txContext2.Commit()
+	assert.Nil(t, errCommit)
+
+	testMsg, err = consumer.ReceiveNoWait()
+	assert.Nil(t, err)
+	assert.Nil(t, testMsg)
+
+}
+
+/*
+ * Test that a JMSContext created from another can still be used after the
+ * original context has been closed, since the shared connection handle is only
+ * disconnected when the last context using it is closed.
+ */
+func TestSharedConnectionCloseOrder(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+
+	context2, ctxErr2 := context.CreateContext(jms20subset.JMSContextAUTOACKNOWLEDGE)
+	assert.Nil(t, ctxErr2)
+	if context2 != nil {
+		defer context2.Close()
+	}
+
+	// Close the original context first.
+	context.Close()
+
+	queue := context2.CreateQueue("DEV.QUEUE.1")
+
+	consumer, conErr := context2.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	msgBody := "CloseOrderMsg"
+	errSend := context2.CreateProducer().SendString(queue, msgBody)
+	assert.Nil(t, errSend)
+
+	rcvBody, err := consumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, err)
+	assert.NotNil(t, rcvBody)
+	assert.Equal(t, msgBody, *rcvBody)
+
+}
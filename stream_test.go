@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+/*
+ * Test sending data as a stream, and receiving it back as a stream in chunks
+ * that each fit in the receive buffer.
+ */
+func TestStream(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// Data that is many times larger than the default receive buffer of 32kb,
+	// which is sent as a group of messages that each hold one chunk of it.
+	data := bytes.Repeat([]byte("0123456789abcdef"), 64*1024)
+
+	errSend := context.CreateProducer().SendStream(queue, bytes.NewReader(data))
+	assert.Nil(t, errSend)
+
+	stream, errRcv := consumer.ReceiveStream(5000)
+	assert.Nil(t, errRcv)
+	if !assert.NotNil(t, stream) {
+		return
+	}
+	defer stream.Close()
+
+	rcvData, errRead := io.ReadAll(stream)
+	assert.Nil(t, errRead)
+	assert.Equal(t, len(data), len(rcvData))
+	assert.True(t, bytes.Equal(data, rcvData))
+
+	// Once the whole stream has been read there is nothing left on the queue.
+	stream, errRcv = consumer.ReceiveStream(100)
+	assert.Nil(t, errRcv)
+	assert.Nil(t, stream)
+
+}
@@ -224,3 +224,99 @@ func TestInvalidClientAuthValue(t *testing.T) {
 	}
 
 }
+
+/*
+ * Test that we can connect using a PKCS#12 key store, which is named including
+ * its extension and has its password supplied by the application.
+ */
+func TestAnonymousTLSConnectionPKCS12(t *testing.T) {
+
+	cf, err := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, err)
+
+	cf.ChannelName = "TLS.ANON.SVRCONN"
+	cf.TLSCipherSpec = "ANY_TLS12"
+	cf.KeyRepository = "./tls-samples/anon-tls.p12" // points to .p12 file
+	cf.KeyRepoPassword = "myKeystorePW"
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, errCtx := cf.CreateContext()
+	if context != nil {
+		defer context.Close()
+	}
+
+	if errCtx != nil && (errCtx.GetReason() == "MQRC_UNKNOWN_CHANNEL_NAME" ||
+		errCtx.GetReason() == "MQRC_CHANNEL_CONFIG_ERROR") {
+		// See ./tls-samples/README.md for details on how to configure the required channel.
+		fmt.Println("Skipping TestAnonymousTLSConnectionPKCS12 as required channel is not defined.")
+		return
+	}
+
+	// PKCS#12 key stores require an MQ 9.3 (or later) client.
+	assert.Nil(t, errCtx)
+
+}
+
+/*
+ * Test that the connection is refused if the certificate of the queue manager
+ * does not match the TLSPeerName.
+ */
+func TestTLSPeerNameMismatch(t *testing.T) {
+
+	cf, err := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, err)
+
+	cf.ChannelName = "TLS.ANON.SVRCONN"
+	cf.TLSCipherSpec = "ANY_TLS12"
+	cf.KeyRepository = "./tls-samples/anon-tls" // points to .kdb file
+	cf.TLSPeerName = "CN=NotMyQueueManager,O=Nobody"
+
+	// Also set the other TLS options, which should not prevent the connection.
+	cf.TLSKeyResetCount = 1024 * 1024
+	cf.TLSCertificateValPolicy = mqjms.TLSCertificateValPolicy_ANY
+
+	context, errCtx := cf.CreateContext()
+	if context != nil {
+		defer context.Close()
+	}
+
+	assert.NotNil(t, errCtx)
+
+	if errCtx.GetReason() == "MQRC_UNKNOWN_CHANNEL_NAME" {
+		// See ./tls-samples/README.md for details on how to configure the required channel.
+		fmt.Println("Skipping TestTLSPeerNameMismatch as required channel is not defined.")
+		return
+	}
+
+	assert.Equal(t, "MQRC_SSL_PEER_NAME_MISMATCH", errCtx.GetReason())
+	assert.Equal(t, "2398", errCtx.GetErrorCode())
+
+}
+
+/*
+ * Test that we get an error code when we give an invalid value for the
+ * TLSCertificateValPolicy parameter.
+ */
+func TestInvalidCertificateValPolicy(t *testing.T) {
+
+	cf, err := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, err)
+
+	cf.ChannelName = "TLS.ANON.SVRCONN"
+	cf.TLSCipherSpec = "ANY_TLS12"
+	cf.KeyRepository = "./tls-samples/anon-tls" // points to .kdb file
+	cf.TLSCertificateValPolicy = "INVALID_VALUE!"
+
+	context, errCtx := cf.CreateContext()
+	if context != nil {
+		defer context.Close()
+	}
+
+	assert.NotNil(t, errCtx)
+	if errCtx != nil {
+		assert.Equal(t, "MQRC_SCO_ERROR", errCtx.GetReason())
+		assert.Equal(t, "2380", errCtx.GetErrorCode())
+	}
+
+}
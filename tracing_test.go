@@ -0,0 +1,195 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zemlya25/mq-golang-jms20/jms20subset"
+	"github.com/zemlya25/mq-golang-jms20/mqjms"
+)
+
+// recordingTracer is a Tracer that keeps the spans created by it so that the
+// test can check them.
+type recordingTracer struct {
+	lock   sync.Mutex
+	spans  []*recordedSpan
+	nextID int
+}
+
+type recordedSpan struct {
+	name     string
+	kind     mqjms.SpanKind
+	id       string
+	parentID string
+	attrs    map[string]interface{}
+	err      error
+	ended    bool
+}
+
+type spanKey struct{}
+
+func (span *recordedSpan) SetAttributes(attrs ...mqjms.SpanAttribute) {
+	for _, attr := range attrs {
+		span.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (span *recordedSpan) RecordError(err error) {
+	span.err = err
+}
+
+func (span *recordedSpan) End() {
+	span.ended = true
+}
+
+func (tracer *recordingTracer) StartSpan(ctx context.Context, name string, kind mqjms.SpanKind, attrs ...mqjms.SpanAttribute) (context.Context, mqjms.Span) {
+	tracer.lock.Lock()
+	defer tracer.lock.Unlock()
+
+	tracer.nextID++
+	span := &recordedSpan{
+		name:  name,
+		kind:  kind,
+		id:    fmt.Sprintf("%016x", tracer.nextID),
+		attrs: make(map[string]interface{}),
+	}
+	if parent, ok := ctx.Value(spanKey{}).(string); ok {
+		span.parentID = parent
+	}
+	span.SetAttributes(attrs...)
+	tracer.spans = append(tracer.spans, span)
+
+	return context.WithValue(ctx, spanKey{}, span.id), span
+}
+
+func (tracer *recordingTracer) Inject(ctx context.Context) mqjms.TraceContext {
+	id, _ := ctx.Value(spanKey{}).(string)
+	return mqjms.TraceContext{
+		TraceParent: "00-0af7651916cd43dd8448eb211c80319c-" + id + "-01",
+		TraceState:  "vendor=test",
+	}
+}
+
+func (tracer *recordingTracer) Extract(ctx context.Context, tc mqjms.TraceContext) context.Context {
+	// The parent ID is the third part of the traceparent, after the version and trace ID.
+	if len(tc.TraceParent) != 55 {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey{}, tc.TraceParent[36:52])
+}
+
+// findSpans returns the spans with the specified kind.
+func (tracer *recordingTracer) findSpans(kind mqjms.SpanKind) []*recordedSpan {
+	tracer.lock.Lock()
+	defer tracer.lock.Unlock()
+
+	var found []*recordedSpan
+	for _, span := range tracer.spans {
+		if span.kind == kind {
+			found = append(found, span)
+		}
+	}
+	return found
+}
+
+/*
+ * Test that the trace context is sent in the properties of a message, and that
+ * spans are created for send, receive, commit and rollback.
+ */
+func TestTracing(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	tracer := &recordingTracer{}
+	cf.Tracer = tracer
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContextWithSessionMode(jms20subset.JMSContextSESSIONTRANSACTED)
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+
+	consumer, conErr := context.CreateConsumer(queue)
+	assert.Nil(t, conErr)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// Send a message, which is traced as a producer span.
+	msgBody := "TracingMsg"
+	sendMsg := context.CreateTextMessageWithString(msgBody)
+	errSend := context.CreateProducer().Send(queue, sendMsg)
+	assert.Nil(t, errSend)
+
+	errCommit := context.Commit()
+	assert.Nil(t, errCommit)
+
+	sendSpans := tracer.findSpans(mqjms.SpanKindProducer)
+	assert.Equal(t, 1, len(sendSpans))
+	sendSpan := sendSpans[0]
+	assert.Equal(t, "DEV.QUEUE.1 publish", sendSpan.name)
+	assert.True(t, sendSpan.ended)
+	assert.Nil(t, sendSpan.err)
+	assert.Equal(t, "ibmmq", sendSpan.attrs[mqjms.SpanAttrMessagingSystem])
+	assert.Equal(t, "publish", sendSpan.attrs[mqjms.SpanAttrOperation])
+	assert.Equal(t, "DEV.QUEUE.1", sendSpan.attrs[mqjms.SpanAttrDestinationName])
+	assert.Equal(t, len(msgBody), sendSpan.attrs[mqjms.SpanAttrMessageBodySize])
+	assert.NotNil(t, sendSpan.attrs[mqjms.SpanAttrMessageID])
+
+	// Receive the message, which carries the trace context of the send.
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	tc := mqjms.TraceContextFromMessage(rcvMsg)
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-"+sendSpan.id+"-01", tc.TraceParent)
+	assert.Equal(t, "vendor=test", tc.TraceState)
+
+	rcvSpans := tracer.findSpans(mqjms.SpanKindConsumer)
+	assert.Equal(t, 1, len(rcvSpans))
+	rcvSpan := rcvSpans[0]
+	assert.Equal(t, "DEV.QUEUE.1 receive", rcvSpan.name)
+	assert.True(t, rcvSpan.ended)
+	assert.Equal(t, sendSpan.id, rcvSpan.parentID)
+	assert.Equal(t, "receive", rcvSpan.attrs[mqjms.SpanAttrOperation])
+	assert.Equal(t, len(msgBody), rcvSpan.attrs[mqjms.SpanAttrMessageBodySize])
+	assert.Equal(t, sendSpan.attrs[mqjms.SpanAttrMessageID], rcvSpan.attrs[mqjms.SpanAttrMessageID])
+
+	errRollback := context.Rollback()
+	assert.Nil(t, errRollback)
+
+	// Commit and rollback are traced as internal spans.
+	opSpans := tracer.findSpans(mqjms.SpanKindInternal)
+	assert.Equal(t, 2, len(opSpans))
+	assert.Equal(t, "commit", opSpans[0].name)
+	assert.Equal(t, "rollback", opSpans[1].name)
+	for _, span := range opSpans {
+		assert.True(t, span.ended)
+		assert.Nil(t, span.err)
+	}
+
+	// Tidy up the message that was rolled back onto the queue.
+	rcvMsg, errRcv = consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+	context.Commit()
+
+}